@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
-	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -124,7 +123,7 @@ func (app *WebServerApp) parseMethod(r *gin.Engine, mt reflect.Method, name stri
 
 	// fmt.Printf("path: /%s\n", lastKw)
 
-	r.GET(fmt.Sprintf("/%s", lastKw), func(c *gin.Context) {
+	handler := func(c *gin.Context) {
 		info := &InstanceInfo{}
 		c.ShouldBind(info)
 
@@ -139,7 +138,11 @@ func (app *WebServerApp) parseMethod(r *gin.Engine, mt reflect.Method, name stri
 		v := reflect.ValueOf(s)
 		cp := reflect.New(paramType.Elem()).Interface().(framework.CmdParam)
 		setupDefaultValue(cp)
-		if err := app.BindCmdParam(c, cp); err != nil {
+		if err := app.BindCmdParam(c, s, cp); err != nil {
+			c.Error(err)
+			return
+		}
+		if err := framework.InjectRequired(s, cp); err != nil {
 			c.Error(err)
 			return
 		}
@@ -173,10 +176,14 @@ func (app *WebServerApp) parseMethod(r *gin.Engine, mt reflect.Method, name stri
 		}
 
 		c.Error(errors.New("unexpected branch reached, no result set found"))
-	})
+	}
+	r.GET(fmt.Sprintf("/%s", lastKw), handler)
+	for _, alias := range framework.GetCmdAliasFromFlag(cp) {
+		r.GET(fmt.Sprintf("/%s", alias), handler)
+	}
 }
 
-func (app *WebServerApp) BindCmdParam(c *gin.Context, cp framework.CmdParam) error {
+func (app *WebServerApp) BindCmdParam(c *gin.Context, s framework.State, cp framework.CmdParam) error {
 	v := reflect.ValueOf(cp)
 	if v.Kind() != reflect.Pointer {
 		return errors.New("param is not pointer")
@@ -189,35 +196,22 @@ func (app *WebServerApp) BindCmdParam(c *gin.Context, cp framework.CmdParam) err
 
 	for i := 0; i < v.NumField(); i++ {
 		f := tp.Field(i)
-		if !f.IsExported() {
+		if !f.IsExported() || f.Anonymous || f.Tag.Get("state") != "" {
 			continue
 		}
 		name := f.Tag.Get("name")
 		rawStr, ok := c.GetQuery(name)
 		if !ok {
-			continue
-		}
-		switch f.Type.Kind() {
-		case reflect.Int64:
-			var dv int64
-			if v, err := strconv.ParseInt(rawStr, 10, 64); err == nil {
-				dv = v
+			if f.Tag.Get("required") == "true" {
+				return fmt.Errorf("required parameter %s not provided", name)
 			}
-			v.Field(i).SetInt(dv)
-			fmt.Println("set default", f.Name, dv)
-		case reflect.String:
-			v.Field(i).SetString(rawStr)
-		case reflect.Bool:
-			var dv bool
-			if v, err := strconv.ParseBool(rawStr); err == nil {
-				dv = v
-			}
-			v.Field(i).SetBool(dv)
-		case reflect.Struct:
 			continue
-		default:
-			return fmt.Errorf("field %s with kind %s not supported yet", f.Name, f.Type.Kind())
 		}
+		value, err := framework.ResolveFieldValue(s, f, rawStr)
+		if err != nil {
+			return fmt.Errorf("invalid parameter %s: %w", name, err)
+		}
+		v.Field(i).Set(value)
 	}
 	return nil
 }
@@ -236,33 +230,15 @@ func setupDefaultValue(p framework.CmdParam) {
 
 	for i := 0; i < v.NumField(); i++ {
 		f := tp.Field(i)
-		if !f.IsExported() {
+		if !f.IsExported() || f.Anonymous || f.Tag.Get("state") != "" {
 			continue
 		}
-		defaultStr := f.Tag.Get("default")
-		switch f.Type.Kind() {
-		case reflect.Int64:
-			var dv int64
-			if v, err := strconv.ParseInt(defaultStr, 10, 64); err == nil {
-				dv = v
-			}
-			v.Field(i).SetInt(dv)
-			fmt.Println("set default", f.Name, dv)
-		case reflect.String:
-			v.Field(i).SetString(defaultStr)
-		case reflect.Bool:
-			var dv bool
-			if v, err := strconv.ParseBool(defaultStr); err == nil {
-				dv = v
-			}
-			v.Field(i).SetBool(dv)
-		case reflect.Struct:
-			continue
-		case reflect.Slice:
+		value, err := framework.ParseFieldValue(f, f.Tag.Get("default"))
+		if err != nil {
+			fmt.Printf("failed to set default value for field %s: %s\n", f.Name, err.Error())
 			continue
-		default:
-			fmt.Printf("field %s with kind %s not supported yet\n", f.Name, f.Type.Kind())
 		}
+		v.Field(i).Set(value)
 	}
 }
 
@@ -0,0 +1,270 @@
+package configs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// milvusDefaults are the values milvus uses when item not provided in milvus.yaml.
+var milvusDefaults = map[string]string{
+	"etcd.endpoints":         "localhost:2379",
+	"etcd.rootPath":          "by-dev",
+	"etcd.metaSubPath":       "meta",
+	"etcd.ssl.enabled":       "false",
+	"etcd.ssl.tlsMinVersion": "1.3",
+	"etcd.auth.enabled":      "false",
+	"metastore.type":         "etcd",
+	"tikv.endpoints":         "127.0.0.1:2389",
+	"tikv.rootPath":          "by-dev",
+	"tikv.metaSubPath":       "meta",
+	"tikv.ssl.enabled":       "false",
+	"minio.address":          "localhost",
+	"minio.port":             "9000",
+	"minio.bucketName":       "a-bucket",
+	"minio.rootPath":         "files",
+	"minio.useSSL":           "false",
+	"minio.useIAM":           "false",
+	"minio.cloudProvider":    "aws",
+	"common.storageType":     "remote",
+	"mq.type":                "default",
+	"pulsar.address":         "localhost",
+	"pulsar.port":            "6650",
+	"pulsar.webport":         "80",
+}
+
+// MilvusConfig stores milvus configuration items parsed from milvus.yaml or helm values file.
+// Keys are normalized the same way milvus paramtable does, so env overrides like
+// `ETCD_ENDPOINTS` or `MINIO_ADDRESS` take effect on `etcd.endpoints` and `minio.address`.
+type MilvusConfig struct {
+	values map[string]string
+}
+
+// LoadMilvusConfig parses milvus config from provided file path and applies env overrides.
+func LoadMilvusConfig(file string) (*MilvusConfig, error) {
+	bs, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMilvusConfig(bs, os.Environ())
+}
+
+// ParseMilvusConfig parses milvus config content, env items in `KEY=VALUE` format override file values.
+func ParseMilvusConfig(bs []byte, envs []string) (*MilvusConfig, error) {
+	raw := make(map[string]any)
+	if err := yaml.Unmarshal(bs, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse milvus config: %w", err)
+	}
+
+	c := &MilvusConfig{values: make(map[string]string)}
+	for key, value := range milvusDefaults {
+		c.Set(key, value)
+	}
+
+	if isHelmValues(raw) {
+		if err := c.loadHelmValues(raw); err != nil {
+			return nil, err
+		}
+	} else {
+		c.load(raw)
+	}
+
+	for _, env := range envs {
+		idx := strings.Index(env, "=")
+		if idx <= 0 {
+			continue
+		}
+		// same as milvus env source, irrelevant env like PATH is harmless here
+		c.Set(env[:idx], env[idx+1:])
+	}
+	return c, nil
+}
+
+// Get returns config value for key, empty string returned if not exists.
+func (c *MilvusConfig) Get(key string) string {
+	return c.values[formatKey(key)]
+}
+
+// GetBool returns config value for key as bool value.
+func (c *MilvusConfig) GetBool(key string) bool {
+	v, err := strconv.ParseBool(c.Get(key))
+	return err == nil && v
+}
+
+// Set updates config value for key.
+func (c *MilvusConfig) Set(key, value string) {
+	c.values[formatKey(key)] = value
+}
+
+// UseTiKV returns whether metastore type is tikv.
+func (c *MilvusConfig) UseTiKV() bool {
+	return strings.EqualFold(c.Get("metastore.type"), "tikv")
+}
+
+// EtcdEndpoints returns etcd endpoints list.
+func (c *MilvusConfig) EtcdEndpoints() []string {
+	return splitList(c.Get("etcd.endpoints"))
+}
+
+// TiKVEndpoints returns tikv endpoints list.
+func (c *MilvusConfig) TiKVEndpoints() []string {
+	return splitList(c.Get("tikv.endpoints"))
+}
+
+// MQType returns message queue type milvus uses, "default" is resolved by configured mq items.
+func (c *MilvusConfig) MQType() string {
+	mqType := strings.ToLower(c.Get("mq.type"))
+	if mqType != "" && mqType != "default" {
+		return mqType
+	}
+	switch {
+	case c.Get("kafka.brokerList") != "":
+		return "kafka"
+	case c.Get("pulsar.address") != "":
+		return "pulsar"
+	default:
+		return "rocksmq"
+	}
+}
+
+// MQAddress returns message queue service address for current mq type.
+func (c *MilvusConfig) MQAddress() string {
+	switch c.MQType() {
+	case "pulsar":
+		addr := c.Get("pulsar.address")
+		if strings.Contains(addr, "://") {
+			return addr
+		}
+		return fmt.Sprintf("pulsar://%s:%s", addr, c.Get("pulsar.port"))
+	case "kafka":
+		return c.Get("kafka.brokerList")
+	default:
+		return ""
+	}
+}
+
+func (c *MilvusConfig) load(raw map[string]any) {
+	for key, value := range flatten("", raw) {
+		c.Set(key, value)
+	}
+}
+
+// helm values external dependency items to milvus config items.
+var helmExternalMapping = map[string]map[string]string{
+	"externalEtcd": {
+		"endpoints": "etcd.endpoints",
+	},
+	"externalS3": {
+		"host":          "minio.address",
+		"port":          "minio.port",
+		"accessKey":     "minio.accessKeyID",
+		"secretKey":     "minio.secretAccessKey",
+		"useSSL":        "minio.useSSL",
+		"bucketName":    "minio.bucketName",
+		"rootPath":      "minio.rootPath",
+		"useIAM":        "minio.useIAM",
+		"cloudProvider": "minio.cloudProvider",
+		"iamEndpoint":   "minio.iamEndpoint",
+		"region":        "minio.region",
+	},
+	"externalPulsar": {
+		"host": "pulsar.address",
+		"port": "pulsar.port",
+	},
+	"externalKafka": {
+		"brokerList": "kafka.brokerList",
+	},
+}
+
+func isHelmValues(raw map[string]any) bool {
+	_, ok := raw["extraConfigFiles"]
+	if ok {
+		return true
+	}
+	for section := range helmExternalMapping {
+		if _, ok := raw[section]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// loadHelmValues parses milvus helm chart values, external dependencies and `extraConfigFiles.user.yaml` are used.
+func (c *MilvusConfig) loadHelmValues(raw map[string]any) error {
+	for section, mapping := range helmExternalMapping {
+		values, ok := raw[section].(map[string]any)
+		if !ok {
+			continue
+		}
+		if enabled, ok := values["enabled"].(bool); ok && !enabled {
+			continue
+		}
+		flat := flatten("", values)
+		for from, to := range mapping {
+			if v, ok := flat[from]; ok {
+				c.Set(to, v)
+			}
+		}
+	}
+
+	extra, ok := raw["extraConfigFiles"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	content, ok := extra["user.yaml"].(string)
+	if !ok {
+		return nil
+	}
+	user := make(map[string]any)
+	if err := yaml.Unmarshal([]byte(content), &user); err != nil {
+		return fmt.Errorf("failed to parse extraConfigFiles.user.yaml: %w", err)
+	}
+	c.load(user)
+	return nil
+}
+
+// flatten converts nested yaml map into dot separated keys, list items are joined with comma.
+func flatten(prefix string, raw map[string]any) map[string]string {
+	result := make(map[string]string)
+	for key, value := range raw {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			for k, v := range flatten(key, v) {
+				result[k] = v
+			}
+		case []any:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			result[key] = strings.Join(items, ",")
+		case nil:
+		default:
+			result[key] = fmt.Sprint(v)
+		}
+	}
+	return result
+}
+
+// formatKey normalizes config key like milvus paramtable, ignoring case, `.`, `_` and `/`.
+func formatKey(key string) string {
+	key = strings.ToLower(key)
+	return strings.NewReplacer(".", "", "_", "", "/", "").Replace(key)
+}
+
+func splitList(v string) []string {
+	var result []string
+	for _, item := range strings.Split(v, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+package configs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMilvusConfig(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		mc, err := ParseMilvusConfig([]byte(``), nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"localhost:2379"}, mc.EtcdEndpoints())
+		assert.Equal(t, "by-dev", mc.Get("etcd.rootPath"))
+		assert.False(t, mc.UseTiKV())
+		assert.Equal(t, "pulsar", mc.MQType())
+		assert.Equal(t, "pulsar://localhost:6650", mc.MQAddress())
+	})
+
+	t.Run("nested_keys", func(t *testing.T) {
+		mc, err := ParseMilvusConfig([]byte(`
+etcd:
+  endpoints:
+    - etcd-0:2379
+    - etcd-1:2379
+  rootPath: prod
+  ssl:
+    enabled: true
+    tlsMinVersion: "1.2"
+metastore:
+  type: tikv
+tikv:
+  endpoints: tikv-0:2389, tikv-1:2389
+minio:
+  bucketName: milvus-bucket
+mq:
+  type: kafka
+kafka:
+  brokerList: kafka:9092
+`), nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"etcd-0:2379", "etcd-1:2379"}, mc.EtcdEndpoints())
+		assert.Equal(t, "prod", mc.Get("etcd.rootPath"))
+		assert.True(t, mc.GetBool("etcd.ssl.enabled"))
+		assert.Equal(t, "1.2", mc.Get("etcd.ssl.tlsMinVersion"))
+		// keys are normalized ignoring case & separators
+		assert.Equal(t, "milvus-bucket", mc.Get("MINIO_BUCKETNAME"))
+		assert.True(t, mc.UseTiKV())
+		assert.Equal(t, []string{"tikv-0:2389", "tikv-1:2389"}, mc.TiKVEndpoints())
+		assert.Equal(t, "kafka", mc.MQType())
+		assert.Equal(t, "kafka:9092", mc.MQAddress())
+	})
+
+	t.Run("env_overrides", func(t *testing.T) {
+		mc, err := ParseMilvusConfig([]byte(`
+etcd:
+  endpoints: etcd-0:2379
+minio:
+  address: minio
+`), []string{"ETCD_ENDPOINTS=etcd-env:2379", "MINIO_ADDRESS=minio-env", "PATH=/usr/bin", "INVALID"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"etcd-env:2379"}, mc.EtcdEndpoints())
+		assert.Equal(t, "minio-env", mc.Get("minio.address"))
+		assert.Equal(t, "9000", mc.Get("minio.port"))
+	})
+
+	t.Run("helm_values", func(t *testing.T) {
+		mc, err := ParseMilvusConfig([]byte(`
+externalEtcd:
+  enabled: true
+  endpoints:
+    - ext-etcd:2379
+externalS3:
+  enabled: false
+  host: ignored
+externalPulsar:
+  enabled: true
+  host: ext-pulsar
+  port: 6651
+extraConfigFiles:
+  user.yaml: |+
+    etcd:
+      rootPath: helm-root
+`), nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ext-etcd:2379"}, mc.EtcdEndpoints())
+		assert.Equal(t, "localhost", mc.Get("minio.address"))
+		assert.Equal(t, "helm-root", mc.Get("etcd.rootPath"))
+		assert.Equal(t, "pulsar://ext-pulsar:6651", mc.MQAddress())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseMilvusConfig([]byte("etcd: [unclosed"), nil)
+		assert.Error(t, err)
+	})
+}
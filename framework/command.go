@@ -8,10 +8,24 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
+// ReadOnlyAnnotation is the cobra command annotation marking command not modifying milvus meta or data,
+// declared with `readonly:"true"` tag on ParamBase.
+const ReadOnlyAnnotation = "readonly"
+
+// RootAliasAnnotation is the cobra command annotation holding aliases resolved from root command,
+// declared with '/' prefixed names in `alias` tag, e.g. `alias:"/parse-ts"` of `ts parse`.
+const RootAliasAnnotation = "root-alias"
+
+// IsReadOnly returns whether command is declared read-only.
+func IsReadOnly(cmd *cobra.Command) bool {
+	return cmd != nil && cmd.Annotations[ReadOnlyAnnotation] == "true"
+}
+
 type commandItem struct {
 	kws []string
 	cmd *cobra.Command
@@ -92,22 +106,60 @@ func parseMethod(state State, mt reflect.Method) (*cobra.Command, []string, bool
 	uses := ParseUseSegments(use)
 	lastKw := uses[len(uses)-1]
 
+	var aliases, rootAliases []string
+	for _, alias := range GetCmdAliasFromFlag(cp) {
+		if name, ok := strings.CutPrefix(alias, "/"); ok {
+			rootAliases = append(rootAliases, name)
+			continue
+		}
+		aliases = append(aliases, alias)
+	}
 	cmd := &cobra.Command{
-		Use: lastKw,
+		Use:         lastKw,
+		Aliases:     aliases,
+		Annotations: make(map[string]string),
+	}
+	if paramBaseTag(cp, ReadOnlyAnnotation) == "true" {
+		cmd.Annotations[ReadOnlyAnnotation] = "true"
+	}
+	if len(rootAliases) > 0 {
+		cmd.Annotations[RootAliasAnnotation] = strings.Join(rootAliases, ",")
 	}
 	setupFlags(cp, cmd.Flags())
+	returnsResultSet := lo.ContainsBy(lo.Range(t.NumOut()), func(i int) bool {
+		return t.Out(i).Implements(reflect.TypeOf((*ResultSet)(nil)).Elem())
+	})
+	if returnsResultSet {
+		setupQueryFlags(cmd.Flags())
+	}
 	cmd.Short = short
 	cmd.Run = func(cmd *cobra.Command, args []string) {
 		cp := reflect.New(paramType.Elem()).Interface().(CmdParam)
 
-		cp.ParseArgs(args)
-		if err := parseFlags(cp, cmd.Flags()); err != nil {
+		defer resetFlags(cmd.Flags())
+		collector, collecting := cmd.Context().Value(collectorKey{}).(ResultCollector)
+		if err := parseParam(state, cp, args, cmd.Flags()); err != nil {
+			if collecting {
+				collector(nil, err)
+				return
+			}
 			fmt.Println(err.Error())
 			return
 		}
 		ctx, cancel := state.Ctx()
 		defer cancel()
 
+		if pp, ok := cp.(planParam); ok && pp.planFile() != "" {
+			if err := runPlanned(ctx, state, mt.Name, use, pp.planFile(), cp); err != nil {
+				if collecting {
+					collector(nil, err)
+					return
+				}
+				fmt.Println(err.Error())
+			}
+			return
+		}
+
 		m := v.MethodByName(mt.Name)
 		results := m.Call([]reflect.Value{
 			reflect.ValueOf(ctx),
@@ -123,13 +175,29 @@ func parseMethod(state State, mt reflect.Method) (*cobra.Command, []string, bool
 					continue
 				}
 				err := result.Interface().(error)
+				if collecting {
+					collector(nil, err)
+					return
+				}
 				fmt.Println(err.Error())
 				return
 			case result.Type().Implements(reflect.TypeOf((*ResultSet)(nil)).Elem()):
 				if result.IsNil() {
 					continue
 				}
-				rs := result.Interface().(ResultSet)
+				rs, err := parseQueryFlags(cp, cmd.Flags()).apply(result.Interface().(ResultSet))
+				if err != nil {
+					if collecting {
+						collector(nil, err)
+						return
+					}
+					fmt.Println(err.Error())
+					return
+				}
+				if collecting {
+					collector(rs, nil)
+					return
+				}
 				if preset, ok := rs.(*PresetResultSet); ok {
 					fmt.Println(preset.String())
 					return
@@ -141,29 +209,49 @@ func parseMethod(state State, mt reflect.Method) (*cobra.Command, []string, bool
 	return cmd, uses, true
 }
 
-func GetCmdFromFlag(p CmdParam) (string, string) {
-	v := reflect.ValueOf(p)
-	if v.Kind() != reflect.Pointer {
-		fmt.Println("param is not pointer")
-		return "", ""
+// runPlanned executes command method against state copy provided by Planner,
+// `run` flag is implied since meta is not modified.
+func runPlanned(ctx context.Context, state State, method, command, planOut string, cp CmdParam) error {
+	planner, ok := state.(Planner)
+	if !ok {
+		return fmt.Errorf("command %s does not support --plan-out", command)
 	}
-
-	for v.Kind() != reflect.Struct {
-		v = v.Elem()
+	v := reflect.ValueOf(cp).Elem()
+	for _, f := range reflect.VisibleFields(v.Type()) {
+		if f.Tag.Get("name") == "run" && f.Type.Kind() == reflect.Bool {
+			v.FieldByIndex(f.Index).SetBool(true)
+		}
 	}
-	tp := v.Type()
+	return planner.Plan(ctx, command, planOut, func(state State) error {
+		results := reflect.ValueOf(state).MethodByName(method).Call([]reflect.Value{
+			reflect.ValueOf(ctx),
+			reflect.ValueOf(cp),
+		})
+		for _, result := range results {
+			if err, ok := result.Interface().(error); ok && err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
 
-	f, has := tp.FieldByName("ParamBase")
-	if !has {
-		return "", ""
-	}
+type collectorKey struct{}
 
-	if f.Type.Kind() != reflect.Struct {
+// ResultCollector receives command result instead of printing it to stdout.
+type ResultCollector func(rs ResultSet, err error)
+
+// WithResultCollector returns context carrying collector for command results.
+func WithResultCollector(ctx context.Context, collector ResultCollector) context.Context {
+	return context.WithValue(ctx, collectorKey{}, collector)
+}
+
+func GetCmdFromFlag(p CmdParam) (string, string) {
+	if reflect.ValueOf(p).Kind() != reflect.Pointer {
+		fmt.Println("param is not pointer")
 		return "", ""
 	}
-
-	tag := f.Tag
-	return tag.Get("use"), tag.Get("desc")
+	return paramBaseTag(p, "use"), paramBaseTag(p, "desc")
 }
 
 func ParseUseSegments(use string) []string {
@@ -186,6 +274,27 @@ func ParseUseSegments(use string) []string {
 	return result
 }
 
+// parseParam fills param with positional args, flags & required states.
+func parseParam(state State, cp CmdParam, args []string, flags *pflag.FlagSet) error {
+	if err := cp.ParseArgs(args); err != nil {
+		return err
+	}
+	if err := parsePositionalArgs(cp, args); err != nil {
+		return err
+	}
+	if err := parseFlags(state, cp, flags); err != nil {
+		return err
+	}
+	if recorder, ok := cp.(FlagRecorder); ok {
+		changed := make(map[string]bool)
+		flags.Visit(func(flag *pflag.Flag) {
+			changed[flag.Name] = true
+		})
+		recorder.RecordFlags(changed)
+	}
+	return InjectRequired(state, cp)
+}
+
 // setupFlags performs command flag setup with CmdParam provided information.
 func setupFlags(p CmdParam, flags *pflag.FlagSet) {
 	v := reflect.ValueOf(p)
@@ -199,15 +308,45 @@ func setupFlags(p CmdParam, flags *pflag.FlagSet) {
 	}
 	tp := v.Type()
 
-	for i := 0; i < v.NumField(); i++ {
-		f := tp.Field(i)
+	// fields promoted from embedded struct like PlanParam are included
+	for _, f := range reflect.VisibleFields(tp) {
 		if !f.IsExported() {
 			continue
 		}
+		// injected by InjectRequired or parsed from positional args
+		if _, positional := positionalIndex(f); f.Tag.Get("state") != "" || positional {
+			continue
+		}
 		name := f.Tag.Get("name")
 		defaultStr := f.Tag.Get("default")
 		desc := f.Tag.Get("desc")
+		if kind := f.Tag.Get("resolve"); kind != "" {
+			flags.Var(&resolveValue{kind: kind, raw: defaultStr}, name, desc)
+			continue
+		}
+		if useFieldValue(f) {
+			fv, err := newFieldValue(f)
+			if err != nil {
+				fmt.Println(err.Error())
+				continue
+			}
+			flags.Var(fv, name, desc)
+			annotateEnum(flags, name, f)
+			continue
+		}
 		switch f.Type.Kind() {
+		case reflect.Int:
+			var dv int
+			if v, err := strconv.Atoi(defaultStr); err == nil {
+				dv = v
+			}
+			flags.Int(name, dv, desc)
+		case reflect.Float64:
+			var dv float64
+			if v, err := strconv.ParseFloat(defaultStr, 64); err == nil {
+				dv = v
+			}
+			flags.Float64(name, dv, desc)
 		case reflect.Int64:
 			var dv int64
 			if v, err := strconv.ParseInt(defaultStr, 10, 64); err == nil {
@@ -227,9 +366,16 @@ func setupFlags(p CmdParam, flags *pflag.FlagSet) {
 		case reflect.Slice:
 			switch f.Type.Elem().Kind() {
 			case reflect.Int64:
-				flags.Int64Slice(name, []int64{}, desc)
+				dv := []int64{}
+				for _, part := range splitSliceValue(defaultStr) {
+					if v, err := strconv.ParseInt(part, 10, 64); err == nil {
+						dv = append(dv, v)
+					}
+				}
+				flags.Int64Slice(name, dv, desc)
 			case reflect.String:
-				flags.StringSlice(name, []string{}, desc)
+				flags.StringSlice(name, splitSliceValue(defaultStr), desc)
+				annotateEnum(flags, name, f)
 			default:
 				fmt.Printf("field %s with slice kind %s not supported yet\n", f.Name, f.Type.Elem().Kind())
 			}
@@ -240,7 +386,8 @@ func setupFlags(p CmdParam, flags *pflag.FlagSet) {
 }
 
 // parseFlags parse parameters from flagset and setup value via reflection.
-func parseFlags(p CmdParam, flags *pflag.FlagSet) error {
+// value of field with `resolve` tag is resolved by state.
+func parseFlags(state State, p CmdParam, flags *pflag.FlagSet) error {
 	v := reflect.ValueOf(p)
 	if v.Kind() != reflect.Pointer {
 		return errors.New("param is not pointer")
@@ -249,31 +396,65 @@ func parseFlags(p CmdParam, flags *pflag.FlagSet) error {
 	v = v.Elem()
 	tp := v.Type()
 
-	for i := 0; i < v.NumField(); i++ {
-		f := tp.Field(i)
+	for _, f := range reflect.VisibleFields(tp) {
 		if !f.IsExported() {
 			continue
 		}
+		if _, positional := positionalIndex(f); f.Tag.Get("state") != "" || positional {
+			continue
+		}
 		name := f.Tag.Get("name")
+		if f.Tag.Get("required") == "true" && !flags.Changed(name) {
+			return fmt.Errorf("required flag --%s not provided", name)
+		}
+		if useFieldValue(f) || f.Tag.Get("resolve") != "" {
+			flag := flags.Lookup(name)
+			if flag == nil {
+				return fmt.Errorf("flag --%s not found", name)
+			}
+			switch value := flag.Value.(type) {
+			case *fieldValue:
+				v.FieldByIndex(f.Index).Set(value.value)
+			case *resolveValue:
+				rv, err := ResolveFieldValue(state, f, value.raw)
+				if err != nil {
+					return err
+				}
+				v.FieldByIndex(f.Index).Set(rv)
+			}
+			continue
+		}
 		switch f.Type.Kind() {
+		case reflect.Int:
+			p, err := flags.GetInt(name)
+			if err != nil {
+				return err
+			}
+			v.FieldByIndex(f.Index).SetInt(int64(p))
+		case reflect.Float64:
+			p, err := flags.GetFloat64(name)
+			if err != nil {
+				return err
+			}
+			v.FieldByIndex(f.Index).SetFloat(p)
 		case reflect.Int64:
 			p, err := flags.GetInt64(name)
 			if err != nil {
 				return err
 			}
-			v.FieldByName(f.Name).SetInt(p)
+			v.FieldByIndex(f.Index).SetInt(p)
 		case reflect.String:
 			p, err := flags.GetString(name)
 			if err != nil {
 				return err
 			}
-			v.FieldByName(f.Name).SetString(p)
+			v.FieldByIndex(f.Index).SetString(p)
 		case reflect.Bool:
 			p, err := flags.GetBool(name)
 			if err != nil {
 				return err
 			}
-			v.FieldByName(f.Name).SetBool(p)
+			v.FieldByIndex(f.Index).SetBool(p)
 		case reflect.Struct:
 			continue
 		case reflect.Slice:
@@ -284,7 +465,15 @@ func parseFlags(p CmdParam, flags *pflag.FlagSet) error {
 			case reflect.Int64:
 				p, err = flags.GetInt64Slice(name)
 			case reflect.String:
-				p, err = flags.GetStringSlice(name)
+				var values []string
+				if values, err = flags.GetStringSlice(name); err == nil {
+					for idx := range values {
+						if values[idx], err = matchEnum(f, values[idx]); err != nil {
+							break
+						}
+					}
+				}
+				p = values
 			default:
 				fmt.Printf("field %s with slice kind %s not supported yet\n", f.Name, f.Type.Elem().Kind())
 				continue
@@ -292,7 +481,7 @@ func parseFlags(p CmdParam, flags *pflag.FlagSet) error {
 			if err != nil {
 				return err
 			}
-			v.FieldByName(f.Name).Set(reflect.ValueOf(p))
+			v.FieldByIndex(f.Index).Set(reflect.ValueOf(p))
 		default:
 			fmt.Printf("field %s with kind %s not supported yet\n", f.Name, f.Type.Kind())
 		}
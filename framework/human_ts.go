@@ -0,0 +1,143 @@
+package framework
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/milvus-io/birdwatcher/utils"
+)
+
+const (
+	queryHumanTS = "human-ts"
+
+	humanTSLayout = "2006-01-02 15:04:05.000 MST"
+
+	// maxHumanTSDepth limits recursion into nested entity fields.
+	maxHumanTSDepth = 16
+)
+
+var (
+	// tsNamePattern matches entity field name holding hybrid timestamp.
+	// allocated ids share the same range as timestamps, so uint64 fields not named as timestamp are left untouched.
+	tsNamePattern = regexp.MustCompile(`(?:[Tt]imestamp|[Tt]ime|TS|Ts|TSO|Tso)$|^(?:ts|tso)$`)
+
+	// tsTextPattern matches numbers in printed output which could be hybrid timestamp.
+	tsTextPattern = regexp.MustCompile(`\b\d{15,20}\b`)
+
+	minHumanTS = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// humanTS returns time of hybrid timestamp, false if physical part is not a plausible time.
+func humanTS(ts uint64) (time.Time, bool) {
+	t, _ := utils.ParseTS(ts)
+	if t.Before(minHumanTS) || t.After(time.Now().AddDate(10, 0, 0)) {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// humanizeValue renders value of uint64 timestamp named field as time.
+func humanizeValue(name string, value any) any {
+	if !tsNamePattern.MatchString(name) {
+		return value
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Uint64 {
+		return value
+	}
+	if t, ok := humanTS(v.Uint()); ok {
+		return t.Format(humanTSLayout)
+	}
+	return value
+}
+
+// collectTimestamps walks value recursively and records uint64 timestamp named fields,
+// e.g. `Timestamp` of nested `StartPosition` or checkpoints in slices & maps.
+func collectTimestamps(name string, v reflect.Value, found map[uint64]string, depth int) {
+	if depth > maxHumanTSDepth || !v.IsValid() {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		if m := v.MethodByName("GetProto"); v.Kind() == reflect.Pointer && m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() == 1 {
+			collectTimestamps(name, m.Call(nil)[0], found, depth+1)
+		}
+		collectTimestamps(name, v.Elem(), found, depth+1)
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return
+		}
+		tp := v.Type()
+		for i := 0; i < tp.NumField(); i++ {
+			f := tp.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			fieldName := f.Name
+			if f.Anonymous {
+				fieldName = name
+			}
+			collectTimestamps(fieldName, v.Field(i), found, depth+1)
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			collectTimestamps(name, v.Index(i), found, depth+1)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			key := name
+			if iter.Key().Kind() == reflect.String {
+				key = iter.Key().String()
+			}
+			collectTimestamps(key, iter.Value(), found, depth+1)
+		}
+	case reflect.Uint64:
+		if !tsNamePattern.MatchString(name) {
+			return
+		}
+		if t, ok := humanTS(v.Uint()); ok {
+			found[v.Uint()] = t.Format(humanTSLayout)
+		}
+	}
+}
+
+// humanTSResultSet renders hybrid timestamps found in entities as readable time
+// within the normal output of wrapped result set.
+type humanTSResultSet struct {
+	ResultSet
+	timestamps map[uint64]string
+}
+
+func newHumanTSResultSet(rs ResultSet) *humanTSResultSet {
+	timestamps := make(map[uint64]string)
+	collectTimestamps("", reflect.ValueOf(rs.Entities()), timestamps, 0)
+	return &humanTSResultSet{ResultSet: rs, timestamps: timestamps}
+}
+
+// PrintAs implements ResultSet, json output is kept as raw value.
+func (rs *humanTSResultSet) PrintAs(format Format) string {
+	output := rs.ResultSet.PrintAs(format)
+	if format == FormatJSON || len(rs.timestamps) == 0 {
+		return output
+	}
+	return tsTextPattern.ReplaceAllStringFunc(output, func(text string) string {
+		ts, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			return text
+		}
+		if readable, ok := rs.timestamps[ts]; ok {
+			return fmt.Sprintf("%s(%s)", text, readable)
+		}
+		return text
+	})
+}
@@ -0,0 +1,46 @@
+package framework
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/birdwatcher/utils"
+)
+
+func TestHumanizeTS(t *testing.T) {
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	ts := utils.MakeTS(now, 1)
+	readable := now.Local().Format(humanTSLayout)
+
+	assert.Equal(t, readable, humanizeValue("StartTs", ts))
+	assert.Equal(t, ts, humanizeValue("ID", ts))
+	// ids share range with timestamps, only uint64 fields are converted
+	assert.Equal(t, int64(ts), humanizeValue("StartTs", int64(ts)))
+	// not plausible time
+	assert.Equal(t, uint64(1000000000000000), humanizeValue("StartTs", uint64(1000000000000000)))
+}
+
+func TestCollectTimestamps(t *testing.T) {
+	ts := utils.MakeTS(time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC), 1)
+	type position struct {
+		Timestamp uint64
+		MsgID     []byte
+	}
+	type entity struct {
+		ID          uint64
+		Position    *position
+		Checkpoints map[string]uint64
+	}
+
+	found := make(map[uint64]string)
+	collectTimestamps("", reflect.ValueOf([]*entity{{
+		ID:          ts + 1,
+		Position:    &position{Timestamp: ts},
+		Checkpoints: map[string]uint64{"ts": ts + 2, "id": ts + 3},
+	}}), found, 0)
+	assert.ElementsMatch(t, []uint64{ts, ts + 2}, lo.Keys(found))
+}
@@ -1,11 +1,19 @@
 package framework
 
+import "context"
+
 // CmdParam is the interface definition for command parameter.
 type CmdParam interface {
 	ParseArgs(args []string) error
 	Desc() (string, string)
 }
 
+// FlagRecorder is implemented by params which need to know the flags explicitly
+// provided in command line, e.g. not to override them with values from other sources.
+type FlagRecorder interface {
+	RecordFlags(changed map[string]bool)
+}
+
 // ParamBase implmenet CmdParam when empty args parser.
 type ParamBase struct{}
 
@@ -16,3 +24,24 @@ func (pb ParamBase) ParseArgs(args []string) error {
 func (pb ParamBase) Desc() (string, string) {
 	return "", ""
 }
+
+// PlanParam is embedded in params of meta mutating commands to support `--plan-out`.
+// When provided, the command is executed as if `--run` provided against a copy-on-write
+// meta by state implementing Planner, and the meta changes are saved as plan instead.
+type PlanParam struct {
+	PlanOut string `name:"plan-out" default:"" desc:"save meta change plan to file instead of modifying meta, execute it with apply command"`
+}
+
+func (p *PlanParam) planFile() string {
+	return p.PlanOut
+}
+
+type planParam interface {
+	planFile() string
+}
+
+// Planner is implemented by states which could execute commands in plan mode.
+type Planner interface {
+	// Plan calls run with copy of state whose meta writes are saved into planOut as plan.
+	Plan(ctx context.Context, command, planOut string, run func(state State) error) error
+}
@@ -0,0 +1,312 @@
+package framework
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/spf13/pflag"
+
+	"github.com/milvus-io/birdwatcher/states/autocomplete"
+	"github.com/milvus-io/birdwatcher/utils"
+)
+
+var (
+	durationType     = reflect.TypeOf(time.Duration(0))
+	timeType         = reflect.TypeOf(time.Time{})
+	float32SliceType = reflect.TypeOf([]float32{})
+)
+
+// ParseFieldValue parses raw string into value of param field type,
+// value is validated with `enum` tag if provided.
+//
+// Besides basic kinds, time.Duration accepts "d" suffix for days,
+// time.Time accepts RFC3339, "now-5m" style relative time or milvus hybrid timestamp
+// and slices accept comma separated values, e.g. "[0.1,0.2]" for []float32.
+func ParseFieldValue(f reflect.StructField, raw string) (reflect.Value, error) {
+	switch f.Type {
+	case durationType:
+		if raw == "" {
+			return reflect.ValueOf(time.Duration(0)), nil
+		}
+		d, err := utils.ParseDuration(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(d), nil
+	case timeType:
+		t, err := parseTime(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(t), nil
+	}
+
+	switch f.Type.Kind() {
+	case reflect.String:
+		value, err := matchEnum(f, raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(value).Convert(f.Type), nil
+	case reflect.Bool:
+		if raw == "" {
+			return reflect.ValueOf(false), nil
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(b), nil
+	case reflect.Int, reflect.Int64:
+		if raw == "" {
+			return reflect.Zero(f.Type), nil
+		}
+		i, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(i).Convert(f.Type), nil
+	case reflect.Float64:
+		if raw == "" {
+			return reflect.ValueOf(float64(0)), nil
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(v), nil
+	case reflect.Slice:
+		elemField := reflect.StructField{Name: f.Name, Type: f.Type.Elem(), Tag: f.Tag}
+		result := reflect.MakeSlice(f.Type, 0, 0)
+		for _, part := range splitSliceValue(raw) {
+			var ev reflect.Value
+			var err error
+			if f.Type.Elem().Kind() == reflect.Float32 {
+				var v float64
+				v, err = strconv.ParseFloat(part, 32)
+				ev = reflect.ValueOf(float32(v))
+			} else {
+				ev, err = ParseFieldValue(elemField, part)
+			}
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			result = reflect.Append(result, ev)
+		}
+		return result, nil
+	default:
+		return reflect.Value{}, fmt.Errorf("field %s with type %s not supported yet", f.Name, f.Type.String())
+	}
+}
+
+// parseTime parses time in RFC3339 format, relative time like "now-5m" or milvus hybrid timestamp.
+func parseTime(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if ts, err := strconv.ParseUint(raw, 10, 64); err == nil {
+		t, _ := utils.ParseTS(ts)
+		return t, nil
+	}
+	t, err := utils.ParseTimeExpr(raw, time.Now())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %s, shall be RFC3339, now[+-]duration or hybrid timestamp", raw)
+	}
+	return t, nil
+}
+
+// matchEnum validates value with `enum` tag, matching is case insensitive and
+// returns the value spelled as in tag. Empty value means not provided and always passes.
+func matchEnum(f reflect.StructField, raw string) (string, error) {
+	values := enumValues(f)
+	if len(values) == 0 || raw == "" {
+		return raw, nil
+	}
+	for _, value := range values {
+		if strings.EqualFold(value, raw) {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("invalid value %s, shall be one of %s", raw, strings.Join(values, ","))
+}
+
+func enumValues(f reflect.StructField) []string {
+	return splitTag(f.Tag.Get("enum"))
+}
+
+func splitSliceValue(raw string) []string {
+	raw = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(raw), "["), "]")
+	parts := lo.Map(strings.Split(raw, ","), func(part string, _ int) string {
+		return strings.TrimSpace(part)
+	})
+	return lo.Filter(parts, func(part string, _ int) bool { return part != "" })
+}
+
+// fieldValue implements pflag.Value for field types without native pflag support,
+// parsing & validating with ParseFieldValue when set.
+type fieldValue struct {
+	field reflect.StructField
+	raw   string
+	value reflect.Value
+}
+
+func newFieldValue(f reflect.StructField) (*fieldValue, error) {
+	fv := &fieldValue{field: f}
+	if err := fv.Set(f.Tag.Get("default")); err != nil {
+		return nil, fmt.Errorf("invalid default value for field %s: %w", f.Name, err)
+	}
+	return fv, nil
+}
+
+// String implements pflag.Value.
+func (fv *fieldValue) String() string {
+	return fv.raw
+}
+
+// Set implements pflag.Value.
+func (fv *fieldValue) Set(raw string) error {
+	value, err := ParseFieldValue(fv.field, raw)
+	if err != nil {
+		return err
+	}
+	fv.raw, fv.value = raw, value
+	return nil
+}
+
+// Type implements pflag.Value, enum values are listed for help message.
+func (fv *fieldValue) Type() string {
+	if values := enumValues(fv.field); len(values) > 0 {
+		return strings.Join(values, "|")
+	}
+	switch fv.field.Type {
+	case durationType:
+		return "duration"
+	case timeType:
+		return "time"
+	case float32SliceType:
+		return "float32Slice"
+	}
+	return fv.field.Type.String()
+}
+
+// useFieldValue returns whether field is bound with fieldValue instead of native pflag types.
+func useFieldValue(f reflect.StructField) bool {
+	switch f.Type {
+	case durationType, timeType, float32SliceType:
+		return true
+	}
+	return f.Type.Kind() == reflect.String && len(enumValues(f)) > 0
+}
+
+// positionalIndex returns position declared with `arg` tag, -1 for variadic "*".
+func positionalIndex(f reflect.StructField) (int, bool) {
+	tag, ok := f.Tag.Lookup("arg")
+	if !ok {
+		return 0, false
+	}
+	if tag == "*" {
+		return -1, true
+	}
+	idx, err := strconv.Atoi(tag)
+	if err != nil || idx < 0 {
+		return 0, false
+	}
+	return idx, true
+}
+
+func fieldArgName(f reflect.StructField) string {
+	if name := f.Tag.Get("name"); name != "" {
+		return name
+	}
+	return strings.ToLower(f.Name)
+}
+
+// parsePositionalArgs sets fields tagged with `arg:"<index>"` from args by position,
+// field tagged with `arg:"*"` receives all args after the last indexed one.
+// Params without positional fields are left for their ParseArgs implementation.
+func parsePositionalArgs(p CmdParam, args []string) error {
+	v := reflect.ValueOf(p).Elem()
+	tp := v.Type()
+
+	maxIdx, variadic := -1, -1
+	for i := 0; i < tp.NumField(); i++ {
+		f := tp.Field(i)
+		idx, ok := positionalIndex(f)
+		if !f.IsExported() || !ok {
+			continue
+		}
+		if idx < 0 {
+			variadic = i
+			continue
+		}
+		maxIdx = max(maxIdx, idx)
+	}
+	if maxIdx < 0 && variadic < 0 {
+		return nil
+	}
+	if variadic < 0 && len(args) > maxIdx+1 {
+		return fmt.Errorf("too many arguments, at most %d expected", maxIdx+1)
+	}
+
+	for i := 0; i < tp.NumField(); i++ {
+		f := tp.Field(i)
+		idx, ok := positionalIndex(f)
+		if !f.IsExported() || !ok {
+			continue
+		}
+
+		var value reflect.Value
+		var err error
+		switch {
+		case idx < 0 && len(args) > maxIdx+1:
+			if f.Type.Kind() != reflect.Slice {
+				return fmt.Errorf("variadic argument field %s shall be slice", f.Name)
+			}
+			value = reflect.MakeSlice(f.Type, 0, len(args)-maxIdx-1)
+			elemField := reflect.StructField{Name: f.Name, Type: f.Type.Elem(), Tag: f.Tag}
+			for _, arg := range args[maxIdx+1:] {
+				ev, err := ParseFieldValue(elemField, arg)
+				if err != nil {
+					return fmt.Errorf("invalid argument %s: %w", fieldArgName(f), err)
+				}
+				value = reflect.Append(value, ev)
+			}
+		case idx >= 0 && idx < len(args):
+			value, err = ParseFieldValue(f, args[idx])
+		default:
+			if f.Tag.Get("required") == "true" {
+				return fmt.Errorf("argument %s not provided", fieldArgName(f))
+			}
+			value, err = ParseFieldValue(f, f.Tag.Get("default"))
+		}
+		if err != nil {
+			return fmt.Errorf("invalid argument %s: %w", fieldArgName(f), err)
+		}
+		v.Field(i).Set(value)
+	}
+	return nil
+}
+
+// resetFlags restores flags to default values after execution,
+// since flag set is kept by command and reused for each execution.
+func resetFlags(flags *pflag.FlagSet) {
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if sv, ok := flag.Value.(pflag.SliceValue); ok {
+			// Set appends to slice values, replace with parsed default instead
+			sv.Replace(splitSliceValue(flag.DefValue))
+		} else {
+			flag.Value.Set(flag.DefValue)
+		}
+		flag.Changed = false
+	})
+}
+
+func annotateEnum(flags *pflag.FlagSet, name string, f reflect.StructField) {
+	if values := enumValues(f); len(values) > 0 {
+		flags.SetAnnotation(name, autocomplete.FlagEnumAnnotation, values)
+	}
+}
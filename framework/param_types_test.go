@@ -0,0 +1,108 @@
+package framework
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedParam struct {
+	ParamBase `use:"typed [name]" desc:"typed param test command"`
+	Name      string        `arg:"0" name:"name" required:"true"`
+	Rest      []int64       `arg:"*" name:"rest"`
+	Timeout   time.Duration `name:"timeout" default:"1d" desc:"duration"`
+	Since     time.Time     `name:"since" default:"" desc:"time"`
+	Ratio     float64       `name:"ratio" default:"0.5" desc:"float"`
+	Count     int           `name:"count" default:"3" desc:"int"`
+	Mode      string        `name:"mode" default:"fast" enum:"fast,slow" desc:"enum"`
+	Vector    []float32     `name:"vector" desc:"vector"`
+	Target    int64         `name:"target" required:"true" desc:"required"`
+	Tags      []string      `name:"tag" default:"a,b" desc:"string slice"`
+	IDs       []int64       `name:"id" desc:"int64 slice"`
+
+	changed map[string]bool
+}
+
+func (p *typedParam) RecordFlags(changed map[string]bool) {
+	p.changed = changed
+}
+
+type typedState struct {
+	*CmdState
+	last *typedParam
+}
+
+func (s *typedState) TypedCommand(ctx context.Context, p *typedParam) error {
+	s.last = p
+	return nil
+}
+
+func TestTypedParam(t *testing.T) {
+	s := &typedState{CmdState: NewCmdState("typed", nil)}
+	s.UpdateState(&cobra.Command{}, s, nil)
+
+	run := func(cmd string) (*typedParam, error) {
+		s.last = nil
+		var cmdErr error
+		err := s.Collect(cmd, func(_ ResultSet, err error) { cmdErr = err })
+		if err != nil {
+			return nil, err
+		}
+		return s.last, cmdErr
+	}
+
+	p, err := run("typed abc 1 2 --target 10")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", p.Name)
+	assert.Equal(t, []int64{1, 2}, p.Rest)
+	assert.Equal(t, 24*time.Hour, p.Timeout)
+	assert.True(t, p.Since.IsZero())
+	assert.Equal(t, 0.5, p.Ratio)
+	assert.Equal(t, 3, p.Count)
+	assert.Equal(t, "fast", p.Mode)
+	assert.Empty(t, p.Vector)
+	assert.EqualValues(t, 10, p.Target)
+	assert.Equal(t, []string{"a", "b"}, p.Tags)
+	assert.Empty(t, p.IDs)
+	assert.Equal(t, map[string]bool{"target": true}, p.changed)
+
+	p, err = run("typed abc --target 1 --timeout 90m --since 2024-05-01T00:00:00Z --ratio 0.1 --count 7 --mode SLOW --vector [0.5,1.5] --tag c --id 1,2")
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, p.Timeout)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), p.Since.UTC())
+	assert.Equal(t, 0.1, p.Ratio)
+	assert.Equal(t, 7, p.Count)
+	assert.Equal(t, "slow", p.Mode)
+	assert.Equal(t, []float32{0.5, 1.5}, p.Vector)
+	assert.Equal(t, []string{"c"}, p.Tags)
+	assert.Equal(t, []int64{1, 2}, p.IDs)
+	assert.True(t, p.changed["timeout"])
+	assert.False(t, p.changed["name"])
+
+	// hybrid ts
+	p, err = run("typed abc --target 1 --since 450000000000000000")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1716613769531, p.Since.UnixMilli())
+	// slice flags are reset to default
+	assert.Equal(t, []string{"a", "b"}, p.Tags)
+	assert.Empty(t, p.IDs)
+
+	// flags are reset after each execution
+	_, err = run("typed abc")
+	assert.Error(t, err)
+
+	for _, cmd := range []string{
+		"typed --target 1",
+		"typed abc --target 1 --mode medium",
+		"typed abc --target 1 --timeout 1y",
+		"typed abc --target 1 --since yesterday",
+		"typed abc --target 1 --vector 1,a",
+	} {
+		_, err := run(cmd)
+		assert.Error(t, err, cmd)
+	}
+}
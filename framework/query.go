@@ -0,0 +1,448 @@
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/samber/lo"
+	"github.com/spf13/pflag"
+)
+
+const (
+	queryWhere   = "where"
+	querySort    = "sort"
+	queryLimit   = "limit"
+	queryColumns = "columns"
+	queryCount   = "count"
+)
+
+// EntitiesSetter is implemented by ResultSet which could be rendered with filtered entities,
+// entities provided are the same slice type returned by `Entities()`.
+type EntitiesSetter interface {
+	SetEntities(entities any)
+}
+
+// queryOptions is the post-processing options applied on entities of ResultSet.
+type queryOptions struct {
+	where   string
+	sort    []string
+	limit   int64
+	columns []string
+	count   bool
+	humanTS bool
+}
+
+func (q *queryOptions) enabled() bool {
+	return q.where != "" || len(q.sort) > 0 || q.limit > 0 || len(q.columns) > 0 || q.count
+}
+
+// setupQueryFlags registers query flags for command returning ResultSet,
+// flags already declared by command param are left untouched.
+func setupQueryFlags(flags *pflag.FlagSet) {
+	add := func(name string, fn func()) {
+		if flags.Lookup(name) == nil {
+			fn()
+		}
+	}
+	add(queryWhere, func() {
+		flags.String(queryWhere, "", "filter result entities with expression, e.g. 'NumOfRows < 1000 && Level == \"L1\"'")
+	})
+	add(querySort, func() {
+		flags.StringSlice(querySort, []string{}, "sort result entities with fields, prefix '-' for descending order, e.g. -NumOfRows")
+	})
+	add(queryLimit, func() { flags.Int64(queryLimit, 0, "max number of result entities, 0 for unlimited") })
+	add(queryColumns, func() {
+		flags.StringSlice(queryColumns, []string{}, "entity fields printed as table columns, e.g. ID,State,NumOfRows")
+	})
+	add(queryCount, func() { flags.Bool(queryCount, false, "print number of result entities only") })
+	add(queryHumanTS, func() { flags.Bool(queryHumanTS, false, "render hybrid timestamps as readable time") })
+}
+
+// parseQueryFlags reads query options from flags, query flags declared by command param are ignored.
+func parseQueryFlags(p CmdParam, flags *pflag.FlagSet) *queryOptions {
+	declared := make(map[string]struct{})
+	tp := reflect.TypeOf(p).Elem()
+	for i := 0; i < tp.NumField(); i++ {
+		declared[tp.Field(i).Tag.Get("name")] = struct{}{}
+	}
+	q := &queryOptions{}
+	has := func(name string) bool {
+		_, ok := declared[name]
+		return !ok && flags.Lookup(name) != nil
+	}
+	if has(queryWhere) {
+		q.where, _ = flags.GetString(queryWhere)
+	}
+	if has(querySort) {
+		q.sort, _ = flags.GetStringSlice(querySort)
+	}
+	if has(queryLimit) {
+		q.limit, _ = flags.GetInt64(queryLimit)
+	}
+	if has(queryColumns) {
+		q.columns, _ = flags.GetStringSlice(queryColumns)
+	}
+	if has(queryCount) {
+		q.count, _ = flags.GetBool(queryCount)
+	}
+	if has(queryHumanTS) {
+		q.humanTS, _ = flags.GetBool(queryHumanTS)
+	}
+	return q
+}
+
+// apply applies query options on result set.
+// With `human-ts`, hybrid timestamps of entities, nested fields included, are rendered as time
+// within the normal output of result set.
+func (q *queryOptions) apply(rs ResultSet) (ResultSet, error) {
+	rs, err := q.filter(rs)
+	if err != nil || !q.humanTS {
+		return rs, err
+	}
+	switch r := rs.(type) {
+	case *QueryResultSet:
+		r.humanTS = true
+	case *PresetResultSet:
+		r.ResultSet = newHumanTSResultSet(r.ResultSet)
+	default:
+		rs = newHumanTSResultSet(rs)
+	}
+	return rs, nil
+}
+
+// filter filters, sorts & limits entities of result set.
+// Result set is rendered with filtered entities if it implements EntitiesSetter,
+// otherwise, or columns/count provided, result is printed as entity table.
+func (q *queryOptions) filter(rs ResultSet) (ResultSet, error) {
+	if !q.enabled() {
+		return rs, nil
+	}
+	preset, isPreset := rs.(*PresetResultSet)
+	if isPreset {
+		rs = preset.ResultSet
+	}
+
+	entities := reflect.ValueOf(rs.Entities())
+	if entities.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("query options not supported, result entities is %T not slice", rs.Entities())
+	}
+
+	rows := make([]*queryRow, 0, entities.Len())
+	for i := 0; i < entities.Len(); i++ {
+		rows = append(rows, newQueryRow(entities.Index(i)))
+	}
+	schema := newQuerySchema(entities.Type().Elem(), rows)
+
+	if q.where != "" {
+		program, err := expr.Compile(q.where, expr.Env(schema.values), expr.AsBool())
+		if err != nil {
+			return nil, fmt.Errorf("invalid where expression: %w", err)
+		}
+		var matched []*queryRow
+		for _, row := range rows {
+			output, err := expr.Run(program, row.values)
+			if err != nil {
+				return nil, fmt.Errorf("failed to evaluate where expression: %w", err)
+			}
+			if output.(bool) {
+				matched = append(matched, row)
+			}
+		}
+		rows = matched
+	}
+
+	if len(q.sort) > 0 {
+		if err := sortRows(schema, rows, q.sort); err != nil {
+			return nil, err
+		}
+	}
+
+	if q.limit > 0 && int64(len(rows)) > q.limit {
+		rows = rows[:q.limit]
+	}
+
+	setter, ok := rs.(EntitiesSetter)
+	if ok && len(q.columns) == 0 && !q.count {
+		filtered := reflect.MakeSlice(entities.Type(), 0, len(rows))
+		for _, row := range rows {
+			filtered = reflect.Append(filtered, row.entity)
+		}
+		setter.SetEntities(filtered.Interface())
+		if isPreset {
+			return preset, nil
+		}
+		return rs, nil
+	}
+
+	result := &QueryResultSet{columns: q.columns, rows: rows, count: q.count}
+	if len(result.columns) == 0 {
+		result.columns = defaultColumns(schema)
+	}
+	for _, column := range result.columns {
+		if !schema.has(column) {
+			return nil, fmt.Errorf("unknown column %s, candidates: %s", column, strings.Join(schema.names, ","))
+		}
+	}
+	return result, nil
+}
+
+// queryRow is entity flattened into field name to value mapping.
+type queryRow struct {
+	entity reflect.Value
+	names  []string
+	values map[string]any
+}
+
+func (r *queryRow) has(name string) bool {
+	_, ok := r.values[name]
+	return ok
+}
+
+func newQueryRow(entity reflect.Value) *queryRow {
+	row := &queryRow{entity: entity, values: make(map[string]any)}
+	row.flatten(entity)
+	if len(row.names) == 0 && entity.IsValid() {
+		row.add("Value", entity)
+	}
+	return row
+}
+
+// newQuerySchema returns row of zero values discovered from entity type,
+// so that expression env & columns are available for empty result.
+// Fields could not be discovered from interface type, first row is used instead if any.
+func newQuerySchema(tp reflect.Type, rows []*queryRow) *queryRow {
+	if tp.Kind() == reflect.Interface && len(rows) > 0 {
+		return rows[0]
+	}
+	schema := &queryRow{values: make(map[string]any)}
+	schema.flattenType(tp)
+	if len(schema.names) == 0 && tp.Kind() != reflect.Interface {
+		schema.add("Value", reflect.Zero(tp))
+	}
+	return schema
+}
+
+// flattenType is the type counterpart of flatten, fields are added with zero values.
+func (r *queryRow) flattenType(tp reflect.Type) {
+	for tp.Kind() == reflect.Pointer {
+		if m, ok := tp.MethodByName("GetProto"); ok && m.Type.NumIn() == 1 && m.Type.NumOut() == 1 {
+			defer r.flattenType(m.Type.Out(0))
+		}
+		tp = tp.Elem()
+	}
+	if tp.Kind() != reflect.Struct || tp == timeType {
+		return
+	}
+	for i := 0; i < tp.NumField(); i++ {
+		f := tp.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if f.Anonymous {
+			r.flattenType(f.Type)
+			continue
+		}
+		r.add(f.Name, reflect.Zero(f.Type))
+	}
+}
+
+// flatten discovers fields of entity with reflection, embedded structs are flattened
+// and proto message wrapped by model (`GetProto()`) is flattened as well.
+func (r *queryRow) flatten(v reflect.Value) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		if m := v.MethodByName("GetProto"); v.Kind() == reflect.Pointer && m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() == 1 {
+			defer r.flatten(m.Call(nil)[0])
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || v.Type() == reflect.TypeOf(time.Time{}) {
+		return
+	}
+	tp := v.Type()
+	for i := 0; i < tp.NumField(); i++ {
+		f := tp.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if f.Anonymous {
+			r.flatten(v.Field(i))
+			continue
+		}
+		r.add(f.Name, v.Field(i))
+	}
+}
+
+func (r *queryRow) add(name string, v reflect.Value) {
+	if r.has(name) {
+		return
+	}
+	r.names = append(r.names, name)
+	r.values[name] = queryValue(v)
+}
+
+// queryValue normalizes field value, enums are converted into string for readability.
+func queryValue(v reflect.Value) any {
+	if !v.CanInterface() {
+		return nil
+	}
+	if stringer, ok := v.Interface().(fmt.Stringer); ok && v.Kind() == reflect.Int32 {
+		return stringer.String()
+	}
+	return v.Interface()
+}
+
+// defaultColumns returns fields with scalar values for entity table.
+func defaultColumns(schema *queryRow) []string {
+	return lo.Filter(schema.names, func(name string, _ int) bool {
+		switch reflect.ValueOf(schema.values[name]).Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Struct, reflect.Interface, reflect.Invalid:
+			_, isTime := schema.values[name].(time.Time)
+			return isTime
+		default:
+			return true
+		}
+	})
+}
+
+func sortRows(schema *queryRow, rows []*queryRow, fields []string) error {
+	type sortKey struct {
+		name string
+		desc bool
+	}
+	keys := lo.Map(fields, func(field string, _ int) sortKey {
+		return sortKey{name: strings.TrimLeft(field, "-+"), desc: strings.HasPrefix(field, "-")}
+	})
+	for _, key := range keys {
+		if !schema.has(key.name) {
+			return fmt.Errorf("unknown sort field %s", key.name)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, key := range keys {
+			c := compareValue(rows[i].values[key.name], rows[j].values[key.name])
+			if c == 0 {
+				continue
+			}
+			if key.desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+	return nil
+}
+
+// compareValue compares numbers, strings, bools & times, other types compared as formatted string.
+func compareValue(a, b any) int {
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case isNumber(av) && isNumber(bv):
+		af, bf := toFloat(av), toFloat(bv)
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	case av.Kind() == reflect.Bool && bv.Kind() == reflect.Bool:
+		ab, bb := av.Bool(), bv.Bool()
+		switch {
+		case ab == bb:
+			return 0
+		case !ab:
+			return -1
+		}
+		return 1
+	}
+	if at, ok := a.(time.Time); ok {
+		if bt, ok := b.(time.Time); ok {
+			return at.Compare(bt)
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func isNumber(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case v.CanInt():
+		return float64(v.Int())
+	case v.CanUint():
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
+
+// QueryResultSet is the entity table result of query options.
+type QueryResultSet struct {
+	columns []string
+	rows    []*queryRow
+	count   bool
+	humanTS bool
+}
+
+// PrintAs implements ResultSet.
+func (rs *QueryResultSet) PrintAs(format Format) string {
+	if format == FormatJSON {
+		bs, err := json.MarshalIndent(rs.Entities(), "", "  ")
+		if err != nil {
+			return err.Error()
+		}
+		return string(bs)
+	}
+	if rs.count {
+		return fmt.Sprintf("--- Count: %d", len(rs.rows))
+	}
+
+	sb := &strings.Builder{}
+	w := tabwriter.NewWriter(sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(rs.columns, "\t"))
+	for _, row := range rs.rows {
+		values := lo.Map(rs.columns, func(column string, _ int) string {
+			if rs.humanTS {
+				return fmt.Sprint(humanizeValue(column, row.values[column]))
+			}
+			return fmt.Sprint(row.values[column])
+		})
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	w.Flush()
+	fmt.Fprintf(sb, "--- Total: %d", len(rs.rows))
+	return sb.String()
+}
+
+// Entities implements ResultSet, returns projected rows or count.
+func (rs *QueryResultSet) Entities() any {
+	if rs.count {
+		return map[string]int{"count": len(rs.rows)}
+	}
+	return lo.Map(rs.rows, func(row *queryRow, _ int) map[string]any {
+		return lo.SliceToMap(rs.columns, func(column string) (string, any) {
+			if rs.humanTS {
+				return column, humanizeValue(column, row.values[column])
+			}
+			return column, row.values[column]
+		})
+	})
+}
@@ -0,0 +1,153 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/utils"
+)
+
+type queryTestLevel int32
+
+func (l queryTestLevel) String() string {
+	return []string{"L0", "L1", "L2"}[l]
+}
+
+// QueryTestBase is exported, fields of unexported embedded struct are not accessible with reflection.
+type QueryTestBase struct {
+	ID    int64
+	Level queryTestLevel
+}
+
+type queryTestPosition struct {
+	Timestamp uint64
+}
+
+type queryTestEntity struct {
+	*QueryTestBase
+	NumOfRows int64
+	Name      string
+	StartTs   uint64
+	Positions []*queryTestPosition
+}
+
+type queryTestEntities struct {
+	ListResultSet[*queryTestEntity]
+}
+
+func (rs *queryTestEntities) PrintAs(format Format) string {
+	sb := &strings.Builder{}
+	for _, entity := range rs.Data {
+		fmt.Fprintf(sb, "ID: %d StartTs: %d", entity.ID, entity.StartTs)
+		for _, pos := range entity.Positions {
+			fmt.Fprintf(sb, " Position: %d", pos.Timestamp)
+		}
+		fmt.Fprintln(sb)
+	}
+	return sb.String()
+}
+
+type queryTestParam struct {
+	ParamBase `use:"list" desc:"query test command"`
+	Empty     bool `name:"empty" default:"false" desc:"return no entity"`
+}
+
+type queryTestState struct {
+	*CmdState
+}
+
+func (s *queryTestState) ListCommand(ctx context.Context, p *queryTestParam) (*queryTestEntities, error) {
+	if p.Empty {
+		return NewListResult[queryTestEntities]([]*queryTestEntity{}), nil
+	}
+	return NewListResult[queryTestEntities]([]*queryTestEntity{
+		{QueryTestBase: &QueryTestBase{ID: 1, Level: 1}, NumOfRows: 3000, Name: "a", StartTs: utils.MakeTS(time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC), 1)},
+		{QueryTestBase: &QueryTestBase{ID: 2, Level: 1}, NumOfRows: 500, Name: "b", Positions: []*queryTestPosition{{Timestamp: utils.MakeTS(time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC), 0)}}},
+		{QueryTestBase: &QueryTestBase{ID: 3, Level: 0}, NumOfRows: 100, Name: "c"},
+		{QueryTestBase: &QueryTestBase{ID: 4, Level: 1}, NumOfRows: 800, Name: "d"},
+	}), nil
+}
+
+func TestQueryOptions(t *testing.T) {
+	s := &queryTestState{CmdState: NewCmdState("query", nil)}
+	s.UpdateState(&cobra.Command{}, s, nil)
+
+	run := func(cmd string) (ResultSet, error) {
+		var result ResultSet
+		var cmdErr error
+		err := s.Collect(cmd, func(rs ResultSet, err error) { result, cmdErr = rs, err })
+		require.NoError(t, err)
+		return result, cmdErr
+	}
+	ids := func(rs ResultSet) []int64 {
+		var result []int64
+		for _, entity := range rs.Entities().([]*queryTestEntity) {
+			result = append(result, entity.ID)
+		}
+		return result
+	}
+
+	rs, err := run("list")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3, 4}, ids(rs))
+
+	rs, err = run(`list --where NumOfRows<1000&&Level=="L1" --sort -NumOfRows`)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{4, 2}, ids(rs))
+
+	rs, err = run("list --sort Level,-ID --limit 2")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 4}, ids(rs))
+
+	rs, err = run("list --columns ID,Name --sort Name --limit 1")
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"ID": int64(1), "Name": "a"}}, rs.Entities())
+	assert.Contains(t, rs.PrintAs(FormatDefault), "ID")
+
+	rs, err = run("list --where NumOfRows>200 --count")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"count": 3}, rs.Entities())
+
+	// query flags are reset after execution
+	rs, err = run("list")
+	require.NoError(t, err)
+	assert.Len(t, ids(rs), 4)
+
+	rs, err = run("list --human-ts --columns ID,StartTs --limit 1")
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"ID": int64(1), "StartTs": time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC).Local().Format(humanTSLayout)}}, rs.Entities())
+	// humanized within normal output, nested timestamps included, entities kept raw
+	rs, err = run("list --human-ts --where ID<=2")
+	require.NoError(t, err)
+	output := rs.PrintAs(FormatDefault)
+	assert.Contains(t, output, fmt.Sprintf("StartTs: %d(2024-05-01", utils.MakeTS(time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC), 1)))
+	assert.Contains(t, output, "(2024-06-01")
+	assert.Equal(t, []int64{1, 2}, ids(rs))
+	assert.NotContains(t, rs.PrintAs(FormatJSON), "2024-05-01")
+
+	// fields discovered from entity type for empty result
+	rs, err = run(`list --empty --where NumOfRows>1&&Level=="L1" --sort -ID`)
+	require.NoError(t, err)
+	assert.Empty(t, ids(rs))
+	rs, err = run("list --empty --count")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"count": 0}, rs.Entities())
+	rs, err = run("list --empty --columns ID,Name")
+	require.NoError(t, err)
+	assert.Contains(t, rs.PrintAs(FormatDefault), "Name")
+
+	for _, cmd := range []string{
+		"list --where Unknown>1", "list --where NumOfRows", "list --sort Unknown", "list --columns Unknown",
+		"list --empty --where Unknown>1", "list --empty --where Name>1", "list --empty --sort Unknown", "list --empty --columns Unknown",
+	} {
+		_, err := run(cmd)
+		assert.Error(t, err, cmd)
+	}
+}
@@ -0,0 +1,133 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// StateResolver is implemented by state which resolves connected states
+// for commands declaring dependencies with `require` tag.
+type StateResolver interface {
+	// ResolveState returns the connected state with provided name, e.g. "etcd", "minio",
+	// error shall tell how to connect if not connected yet.
+	ResolveState(name string) (State, error)
+}
+
+// ValueResolver is implemented by state which resolves references provided for fields
+// with `resolve` tag into field values, e.g. collection name into collection id.
+type ValueResolver interface {
+	ResolveValue(ctx context.Context, kind string, raw string) (any, error)
+}
+
+// ResolveFieldValue parses raw value of field, value of field with `resolve` tag is resolved by state
+// if state implements ValueResolver, otherwise raw value is parsed as is.
+func ResolveFieldValue(state State, f reflect.StructField, raw string) (reflect.Value, error) {
+	kind := f.Tag.Get("resolve")
+	resolver, ok := state.(ValueResolver)
+	if kind == "" || !ok {
+		value, err := ParseFieldValue(f, raw)
+		if err != nil && kind != "" {
+			return reflect.Value{}, fmt.Errorf("%s %s cannot be resolved in current state: %w", kind, raw, err)
+		}
+		return value, err
+	}
+
+	ctx, cancel := state.Ctx()
+	defer cancel()
+	resolved, err := resolver.ResolveValue(ctx, kind, raw)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	rv := reflect.ValueOf(resolved)
+	if !rv.IsValid() || !rv.Type().ConvertibleTo(f.Type) {
+		return reflect.Value{}, fmt.Errorf("resolved %s is %T, not %s", kind, resolved, f.Type.String())
+	}
+	return rv.Convert(f.Type), nil
+}
+
+// resolveValue implements pflag.Value keeping raw reference for fields with `resolve` tag.
+type resolveValue struct {
+	kind string
+	raw  string
+}
+
+func (v *resolveValue) String() string { return v.raw }
+
+func (v *resolveValue) Set(raw string) error {
+	v.raw = raw
+	return nil
+}
+
+func (v *resolveValue) Type() string { return v.kind }
+
+// InjectRequired resolves states declared in `require` tag of param with resolver state,
+// then sets them into param fields with matching `state` tag.
+func InjectRequired(state State, p CmdParam) error {
+	requires := GetCmdRequireFromFlag(p)
+	if len(requires) == 0 {
+		return nil
+	}
+	resolver, ok := state.(StateResolver)
+	if !ok {
+		return fmt.Errorf("command requires %s connected, which is not supported in current state", strings.Join(requires, ","))
+	}
+
+	v := reflect.ValueOf(p).Elem()
+	tp := v.Type()
+	for _, name := range requires {
+		resolved, err := resolver.ResolveState(name)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < tp.NumField(); i++ {
+			f := tp.Field(i)
+			if !f.IsExported() || f.Tag.Get("state") != name {
+				continue
+			}
+			rv := reflect.ValueOf(resolved)
+			if !rv.Type().AssignableTo(f.Type) {
+				return fmt.Errorf("%s state is %T, not %s", name, resolved, f.Type.String())
+			}
+			v.Field(i).Set(rv)
+		}
+	}
+	return nil
+}
+
+// GetCmdAliasFromFlag returns aliases declared in `alias` tag of ParamBase,
+// aliases prefixed with '/' are resolved from root command instead of parent command.
+func GetCmdAliasFromFlag(p CmdParam) []string {
+	return splitTag(paramBaseTag(p, "alias"))
+}
+
+// GetCmdRequireFromFlag returns dependencies declared in `require` tag of ParamBase.
+func GetCmdRequireFromFlag(p CmdParam) []string {
+	return splitTag(paramBaseTag(p, "require"))
+}
+
+func paramBaseTag(p CmdParam, key string) string {
+	v := reflect.ValueOf(p)
+	if v.Kind() != reflect.Pointer {
+		return ""
+	}
+	for v.Kind() != reflect.Struct {
+		v = v.Elem()
+	}
+	f, has := v.Type().FieldByName("ParamBase")
+	if !has || f.Type.Kind() != reflect.Struct {
+		return ""
+	}
+	return f.Tag.Get(key)
+}
+
+func splitTag(tag string) []string {
+	var result []string
+	for _, part := range strings.Split(tag, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
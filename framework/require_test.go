@@ -0,0 +1,75 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type requireTestState struct {
+	*CmdState
+	connected map[string]State
+}
+
+func (s *requireTestState) ResolveState(name string) (State, error) {
+	state, ok := s.connected[name]
+	if !ok {
+		return nil, errors.New(name + " not connected")
+	}
+	return state, nil
+}
+
+type requireTestParam struct {
+	ParamBase `use:"require test" alias:"rt, reqtest" require:"etcd"`
+	Etcd      *CmdState `state:"etcd"`
+}
+
+func TestInjectRequired(t *testing.T) {
+	p := &requireTestParam{}
+	assert.Equal(t, []string{"rt", "reqtest"}, GetCmdAliasFromFlag(p))
+	assert.Equal(t, []string{"etcd"}, GetCmdRequireFromFlag(p))
+
+	etcd := NewCmdState("etcd", nil)
+	state := &requireTestState{CmdState: NewCmdState("test", nil), connected: map[string]State{}}
+	assert.Error(t, InjectRequired(state, p))
+
+	state.connected["etcd"] = etcd
+	assert.NoError(t, InjectRequired(state, p))
+	assert.Same(t, etcd, p.Etcd)
+
+	// state without resolver cannot serve required states
+	assert.Error(t, InjectRequired(NewCmdState("plain", nil), p))
+}
+
+type aliasTestState struct {
+	*CmdState
+}
+
+type aliasTestParam struct {
+	ParamBase `use:"alias test" alias:"at, /alias-test"`
+}
+
+func (s *aliasTestState) AliasTestCommand(ctx context.Context, p *aliasTestParam) error {
+	return errors.New("alias test called")
+}
+
+func TestRootAlias(t *testing.T) {
+	s := &aliasTestState{CmdState: NewCmdState("alias", nil)}
+	s.UpdateState(&cobra.Command{}, s, nil)
+
+	for _, cmd := range []string{"alias test", "alias at", "alias-test"} {
+		var called error
+		require.NoError(t, s.Collect(cmd, func(_ ResultSet, err error) { called = err }), cmd)
+		assert.EqualError(t, called, "alias test called", cmd)
+	}
+	// root alias is not registered as sub command
+	assert.Equal(t, []string{"at"}, lo.Map(s.RootCmd.Commands()[0].Commands(), func(cmd *cobra.Command, _ int) string {
+		return strings.Join(cmd.Aliases, ",")
+	}))
+}
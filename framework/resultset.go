@@ -59,6 +59,13 @@ func (rs *ListResultSet[T]) Entities() any {
 	return rs.Data
 }
 
+// SetEntities implements EntitiesSetter.
+func (rs *ListResultSet[T]) SetEntities(entities any) {
+	if data, ok := entities.([]T); ok {
+		rs.Data = data
+	}
+}
+
 func (rs *ListResultSet[T]) SetData(data []T) {
 	rs.Data = data
 }
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 
 	"github.com/milvus-io/birdwatcher/common"
@@ -153,11 +154,37 @@ func (s *CmdState) Suggestions(input string) map[string]string {
 // Process is the main entry for processing command.
 func (s *CmdState) Process(cmd string) (State, error) {
 	s.Log(s.label, "processing command:", cmd)
-	args := strings.Split(cmd, " ")
+	err := s.execute(context.Background(), cmd)
+
+	if errors.Is(err, common.ExitErr) {
+		return s.nextState, common.ExitErr
+	}
+	if err != nil {
+		return s, err
+	}
+	if s.nextState != nil {
+		nextState := s.nextState
+		s.nextState = nil
+		return nextState, nil
+	}
+
+	return s, nil
+}
+
+// Collect executes command and hands command result to collector instead of printing it.
+func (s *CmdState) Collect(cmd string, collector ResultCollector) error {
+	s.Log(s.label, "collecting command:", cmd)
+	return s.execute(WithResultCollector(context.Background(), collector), cmd)
+}
+
+func (s *CmdState) execute(ctx context.Context, cmd string) error {
+	args := s.resolveRootAlias(strings.Split(cmd, " "))
 
 	target, _, err := s.RootCmd.Find(args)
 	if err == nil && target != nil {
 		defer target.SetArgs(nil)
+		// sub command keeps context once set, override it for each execution
+		target.SetContext(ctx)
 	}
 
 	signal.Reset(syscall.SIGINT)
@@ -166,22 +193,34 @@ func (s *CmdState) Process(cmd string) (State, error) {
 	s.signal = c
 
 	s.RootCmd.SetArgs(args)
-	err = s.RootCmd.Execute()
+	err = s.RootCmd.ExecuteContext(ctx)
 	signal.Reset(syscall.SIGINT)
+	return err
+}
 
-	if errors.Is(err, common.ExitErr) {
-		return s.nextState, common.ExitErr
+// resolveRootAlias replaces first arg with command path if it is root alias of nested command,
+// e.g. `parse-ts 1` is executed as `ts parse 1`.
+func (s *CmdState) resolveRootAlias(args []string) []string {
+	if target, _, err := s.RootCmd.Find(args[:1]); err == nil && target != s.RootCmd {
+		return args
 	}
-	if err != nil {
-		return s, err
+	var walk func(cmd *cobra.Command) []string
+	walk = func(cmd *cobra.Command) []string {
+		for _, sub := range cmd.Commands() {
+			if lo.Contains(splitTag(sub.Annotations[RootAliasAnnotation]), args[0]) {
+				return strings.Fields(strings.TrimPrefix(sub.CommandPath(), s.RootCmd.CommandPath()))
+			}
+			if path := walk(sub); path != nil {
+				return path
+			}
+		}
+		return nil
 	}
-	if s.nextState != nil {
-		nextState := s.nextState
-		s.nextState = nil
-		return nextState, nil
+	path := walk(s.RootCmd)
+	if path == nil {
+		return args
 	}
-
-	return s, nil
+	return append(path, args[1:]...)
 }
 
 // SetNext simple method to set next state.
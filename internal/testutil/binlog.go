@@ -0,0 +1,161 @@
+// Package testutil provides helpers to generate milvus data files for tests.
+package testutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+
+	"github.com/milvus-io/birdwatcher/storage"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+var endian = binary.LittleEndian
+
+// eventHeader is the binary layout of binlog event header.
+type eventHeader struct {
+	Timestamp    uint64
+	TypeCode     storage.EventTypeCode
+	EventLength  int32
+	NextPosition int32
+}
+
+// eventDataFixPart is the fix part of insert, delete & index file event data.
+type eventDataFixPart struct {
+	StartTimestamp uint64
+	EndTimestamp   uint64
+}
+
+// WriteInsertBinlog writes single column data as milvus insert binlog,
+// which contains magic number, descriptor event and one insert event with parquet payload.
+func WriteInsertBinlog(w io.Writer, fixPart storage.DescriptorEventDataFixPart, column arrow.Array) error {
+	payload, err := writeParquetPayload(column)
+	if err != nil {
+		return err
+	}
+	return writeBinlog(w, fixPart, nil, storage.InsertEventType, payload)
+}
+
+// WriteIndexFile writes index data as milvus index file, key is recorded in descriptor extras
+// and data is stored as int8 parquet payload like index nodes do.
+func WriteIndexFile(w io.Writer, fixPart storage.DescriptorEventDataFixPart, key string, data []byte) error {
+	builder := array.NewInt8Builder(memory.DefaultAllocator)
+	for _, b := range data {
+		builder.Append(int8(b))
+	}
+	column := builder.NewArray()
+	builder.Release()
+	defer column.Release()
+	payload, err := writeParquetPayload(column)
+	if err != nil {
+		return err
+	}
+
+	fixPart.PayloadDataType = schemapb.DataType_Int8
+	return writeBinlog(w, fixPart, map[string]string{storage.IndexFileKeyExtra: key}, storage.IndexFileEventType, payload)
+}
+
+// WriteDeltalog writes delete records as milvus deltalog, each record is stored as
+// json string of pk, ts & pk type in one delete event.
+func WriteDeltalog(w io.Writer, fixPart storage.DescriptorEventDataFixPart, data *storage.DeltaData) error {
+	builder := array.NewStringBuilder(memory.DefaultAllocator)
+	var err error
+	data.Range(func(pk storage.PrimaryKey, ts uint64) bool {
+		var bs []byte
+		bs, err = json.Marshal(&storage.DeleteLog{Pk: pk, Ts: ts, PkType: int64(pk.Type())})
+		if err != nil {
+			return false
+		}
+		builder.Append(string(bs))
+		return true
+	})
+	column := builder.NewArray()
+	builder.Release()
+	defer column.Release()
+	if err != nil {
+		return err
+	}
+	payload, err := writeParquetPayload(column)
+	if err != nil {
+		return err
+	}
+
+	fixPart.PayloadDataType = schemapb.DataType_String
+	return writeBinlog(w, fixPart, nil, storage.DeleteEventType, payload)
+}
+
+func writeBinlog(w io.Writer, fixPart storage.DescriptorEventDataFixPart, extras map[string]string, eventType storage.EventTypeCode, payload []byte) error {
+	buf := &bytes.Buffer{}
+	if err := binary.Write(buf, endian, storage.MagicNumber); err != nil {
+		return err
+	}
+
+	// descriptor event data: fix part, post header lengths of all event types & extras json
+	ex := make(map[string]any)
+	for k, v := range extras {
+		ex[k] = v
+	}
+	ex["original_size"] = strconv.Itoa(len(payload))
+	extraBytes, err := json.Marshal(ex)
+	if err != nil {
+		return err
+	}
+	fixPartSize := int32(binary.Size(eventDataFixPart{}))
+	postHeaderLengths := make([]uint8, 0, storage.EventTypeEnd)
+	for i := storage.DescriptorEventType; i < storage.EventTypeEnd; i++ {
+		size := fixPartSize
+		if i == storage.DescriptorEventType {
+			size = int32(binary.Size(fixPart))
+		}
+		postHeaderLengths = append(postHeaderLengths, uint8(size))
+	}
+
+	headerSize := int32(binary.Size(eventHeader{}))
+	deHeader := eventHeader{TypeCode: storage.DescriptorEventType}
+	deHeader.EventLength = headerSize + int32(binary.Size(fixPart)) + int32(len(postHeaderLengths)) + int32(binary.Size(int32(0))) + int32(len(extraBytes))
+	deHeader.NextPosition = int32(binary.Size(storage.MagicNumber)) + deHeader.EventLength
+	for _, v := range []any{deHeader, fixPart, postHeaderLengths, int32(len(extraBytes)), extraBytes} {
+		if err := binary.Write(buf, endian, v); err != nil {
+			return err
+		}
+	}
+
+	header := eventHeader{TypeCode: eventType}
+	header.EventLength = headerSize + fixPartSize + int32(len(payload))
+	header.NextPosition = deHeader.NextPosition + header.EventLength
+	ed := eventDataFixPart{StartTimestamp: fixPart.StartTimestamp, EndTimestamp: fixPart.EndTimestamp}
+	for _, v := range []any{header, ed} {
+		if err := binary.Write(buf, endian, v); err != nil {
+			return err
+		}
+	}
+	buf.Write(payload)
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func writeParquetPayload(column arrow.Array) ([]byte, error) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "val", Type: column.DataType(), Nullable: false}}, nil)
+	record := array.NewRecord(schema, []arrow.Array{column}, int64(column.Len()))
+	defer record.Release()
+	table := array.NewTableFromRecords(schema, []arrow.Record{record})
+	defer table.Release()
+
+	buf := &bytes.Buffer{}
+	err := pqarrow.WriteTable(table, buf, table.NumRows(),
+		parquet.NewWriterProperties(parquet.WithAllocator(memory.DefaultAllocator)),
+		pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
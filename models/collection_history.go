@@ -0,0 +1,28 @@
+package models
+
+// CollectionEventType is the kind of change found between collection related snapshots.
+type CollectionEventType string
+
+const (
+	CollectionEventState     CollectionEventType = "State"
+	CollectionEventSchema    CollectionEventType = "Schema"
+	CollectionEventProperty  CollectionEventType = "Property"
+	CollectionEventPartition CollectionEventType = "Partition"
+	CollectionEventAlias     CollectionEventType = "Alias"
+	CollectionEventOther     CollectionEventType = "Other"
+)
+
+// CollectionHistoryEvent is one entry of collection timeline decoded from rootcoord snapshots.
+type CollectionHistoryEvent struct {
+	Ts   uint64
+	Type CollectionEventType
+	// DDL is the inferred ddl operation caused this change
+	DDL string
+	// Target is the changed object, partition/alias/field name for sub-objects
+	Target string
+	Detail string
+	// Changes is the diff against previous snapshot of same object
+	Changes []string
+	// Key is the snapshot key this event decoded from
+	Key string
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/indexpb"
+)
+
+type (
+	// StatsTask model for datacoord stats task(sort/text index/bm25 stats) meta.
+	StatsTask = ProtoWrapper[*indexpb.StatsTask]
+	// AnalyzeTask model for clustering compaction analyze task meta.
+	AnalyzeTask = ProtoWrapper[*indexpb.AnalyzeTask]
+	// PartitionStats model for partition stats info meta.
+	PartitionStats = ProtoWrapper[*datapb.PartitionStatsInfo]
+)
+
+func NewStatsTask(info *indexpb.StatsTask, key string) *StatsTask {
+	return NewProtoWrapper(info, key)
+}
+
+func NewAnalyzeTask(info *indexpb.AnalyzeTask, key string) *AnalyzeTask {
+	return NewProtoWrapper(info, key)
+}
+
+func NewPartitionStats(info *datapb.PartitionStatsInfo, key string) *PartitionStats {
+	return NewProtoWrapper(info, key)
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+)
+
+// Role model for rootcoord role meta.
+type Role struct {
+	Name   string
+	Tenant string
+
+	key string
+}
+
+func (r *Role) Key() string {
+	return r.key
+}
+
+func NewRole(name, tenant, key string) *Role {
+	return &Role{
+		Name:   name,
+		Tenant: tenant,
+		key:    key,
+	}
+}
+
+// UserRole model for user-role binding meta.
+type UserRole struct {
+	Username string
+	RoleName string
+	Tenant   string
+
+	key string
+}
+
+func (ur *UserRole) Key() string {
+	return ur.key
+}
+
+func NewUserRole(username, roleName, tenant, key string) *UserRole {
+	return &UserRole{
+		Username: username,
+		RoleName: roleName,
+		Tenant:   tenant,
+		key:      key,
+	}
+}
+
+// Grant model for grantee-privileges meta.
+// Privileges are stored separately under grantee-id prefix with GranteeID.
+type Grant struct {
+	RoleName   string
+	Object     string
+	DBName     string
+	ObjectName string
+	Tenant     string
+	GranteeID  string
+	Privileges []*GrantPrivilege
+
+	key string
+}
+
+func (g *Grant) Key() string {
+	return g.key
+}
+
+func NewGrant(roleName, object, dbName, objectName, tenant, granteeID, key string) *Grant {
+	return &Grant{
+		RoleName:   roleName,
+		Object:     object,
+		DBName:     dbName,
+		ObjectName: objectName,
+		Tenant:     tenant,
+		GranteeID:  granteeID,
+		key:        key,
+	}
+}
+
+// GrantPrivilege model for grantee-id privilege entry.
+type GrantPrivilege struct {
+	GranteeID string
+	Name      string
+	Grantor   string
+	Tenant    string
+
+	key string
+}
+
+func (gp *GrantPrivilege) Key() string {
+	return gp.key
+}
+
+func NewGrantPrivilege(granteeID, name, grantor, tenant, key string) *GrantPrivilege {
+	return &GrantPrivilege{
+		GranteeID: granteeID,
+		Name:      name,
+		Grantor:   grantor,
+		Tenant:    tenant,
+		key:       key,
+	}
+}
+
+type PrivilegeGroup = ProtoWrapper[*milvuspb.PrivilegeGroupInfo]
+
+func NewPrivilegeGroup(info *milvuspb.PrivilegeGroupInfo, key string) *PrivilegeGroup {
+	return NewProtoWrapper(info, key)
+}
+
+// RBACIssueType is the category of rbac meta inconsistency.
+type RBACIssueType string
+
+const (
+	RBACIssueGrantCollectionMissing RBACIssueType = "grant-collection-missing"
+	RBACIssueGrantDatabaseMissing   RBACIssueType = "grant-database-missing"
+	RBACIssueGrantRoleMissing       RBACIssueType = "grant-role-missing"
+	RBACIssueBindingRoleMissing     RBACIssueType = "binding-role-missing"
+	RBACIssueBindingUserMissing     RBACIssueType = "binding-user-missing"
+	RBACIssueGranteeIDOrphan        RBACIssueType = "grantee-id-orphan"
+	RBACIssueGrantGroupMissing      RBACIssueType = "grant-privilege-group-missing"
+	RBACIssuePrivilegeGroupOrphan   RBACIssueType = "privilege-group-orphan"
+)
+
+// RBACIssue describes one inconsistency found in rbac meta.
+// Keys & Prefixes contain the meta entries to clean when fixing this issue.
+// Warn issues may be intended by user and are only cleaned when their type is selected explicitly.
+type RBACIssue struct {
+	Type        RBACIssueType
+	Warn        bool
+	Description string
+	Keys        []string
+	Prefixes    []string
+}
@@ -18,6 +18,7 @@ type Segment struct {
 	binlogs   []*FieldBinlog
 	statslogs []*FieldBinlog
 	deltalogs []*FieldBinlog
+	bm25logs  []*FieldBinlog
 	// Semantic version
 	Version string
 
@@ -30,7 +31,7 @@ type Segment struct {
 }
 
 func NewSegment(segment *datapb.SegmentInfo, key string,
-	lazy func() ([]*datapb.FieldBinlog, []*datapb.FieldBinlog, []*datapb.FieldBinlog, error),
+	lazy func() ([]*datapb.FieldBinlog, []*datapb.FieldBinlog, []*datapb.FieldBinlog, []*datapb.FieldBinlog, error),
 ) *Segment {
 	s := &Segment{
 		SegmentInfo: segment,
@@ -46,7 +47,7 @@ func NewSegment(segment *datapb.SegmentInfo, key string,
 			}
 			return r
 		}
-		binlogs, statslogs, deltalogs, err := lazy()
+		binlogs, statslogs, deltalogs, bm25logs, err := lazy()
 		if err != nil {
 			fmt.Println("lazy load binlog failed", err.Error())
 			return
@@ -54,6 +55,7 @@ func NewSegment(segment *datapb.SegmentInfo, key string,
 		s.binlogs = lo.Map(binlogs, mFunc)
 		s.statslogs = lo.Map(statslogs, mFunc)
 		s.deltalogs = lo.Map(deltalogs, mFunc)
+		s.bm25logs = lo.Map(bm25logs, mFunc)
 	}
 
 	return s
@@ -114,6 +116,15 @@ func (s *Segment) GetDeltalogs() []*FieldBinlog {
 	return s.deltalogs
 }
 
+func (s *Segment) GetBm25Statslogs() []*FieldBinlog {
+	s.loadOnce.Do(func() {
+		if s.lazyLoad != nil {
+			s.lazyLoad(s)
+		}
+	})
+	return s.bm25logs
+}
+
 func (s *Segment) GetStartPosition() *msgpb.MsgPosition {
 	if s == nil {
 		return nil
@@ -0,0 +1,54 @@
+package models
+
+// SegmentLineageNode is one segment in lineage graph.
+type SegmentLineageNode struct {
+	SegmentID    int64
+	CollectionID int64
+	PartitionID  int64
+	Level        string
+	State        string
+	NumOfRows    int64
+	// DmlTs is the dml position timestamp of segment
+	DmlTs uint64
+	// DroppedAt is the drop time in unix nano
+	DroppedAt uint64
+	// Missing means segment only referenced by other meta but not found in segment meta
+	Missing bool
+}
+
+// SegmentLineageEdge is the compaction relation from source segment to result segment.
+type SegmentLineageEdge struct {
+	From           int64
+	To             int64
+	PlanID         int64
+	CompactionType string
+}
+
+// SegmentLineage is the compaction lineage graph around the root segment.
+type SegmentLineage struct {
+	Root  int64
+	Nodes map[int64]*SegmentLineageNode
+	Edges []*SegmentLineageEdge
+}
+
+// Parents returns edges point to provided segment.
+func (l *SegmentLineage) Parents(segmentID int64) []*SegmentLineageEdge {
+	var result []*SegmentLineageEdge
+	for _, edge := range l.Edges {
+		if edge.To == segmentID {
+			result = append(result, edge)
+		}
+	}
+	return result
+}
+
+// Children returns edges start from provided segment.
+func (l *SegmentLineage) Children(segmentID int64) []*SegmentLineageEdge {
+	var result []*SegmentLineageEdge
+	for _, edge := range l.Edges {
+		if edge.From == segmentID {
+			result = append(result, edge)
+		}
+	}
+	return result
+}
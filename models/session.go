@@ -14,9 +14,17 @@ type Session struct {
 	Exclusive  bool   `json:"Exclusive,omitempty"`
 	Version    string `json:"Version,omitempty"`
 
+	IndexEngineVersion IndexEngineVersion `json:"IndexEngineVersion,omitempty"`
+
 	key string
 }
 
+// IndexEngineVersion is the index engine version range supported by component, e.g. querynode.
+type IndexEngineVersion struct {
+	MinimalIndexVersion int32 `json:"MinimalIndexVersion,omitempty"`
+	CurrentIndexVersion int32 `json:"CurrentIndexVersion,omitempty"`
+}
+
 func (s *Session) SetKey(key string) {
 	s.key = key
 }
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"sort"
 
-	"github.com/cockroachdb/errors"
 	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 
@@ -59,20 +58,12 @@ func (app *ApplicationState) ListStatesCommand(ctx context.Context, p *ListState
 
 type DisconnectParam struct {
 	framework.ParamBase `use:"disconnect" desc:"disconnect online states"`
-	components          []string
-}
-
-func (p *DisconnectParam) ParseArgs(args []string) error {
-	if len(args) == 0 {
-		return errors.New("disconnect component not provided")
-	}
-	p.components = args
-	return nil
+	Components          []string `arg:"*" name:"component" required:"true"`
 }
 
 // DisconnectCommand implements disconnect sub state logic.
 func (app *ApplicationState) DisconnectCommand(ctx context.Context, p *DisconnectParam) error {
-	for _, comp := range p.components {
+	for _, comp := range p.Components {
 		state, ok := app.states[comp]
 		if !ok {
 			fmt.Printf("State %s not connected.\n", comp)
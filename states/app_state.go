@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cockroachdb/errors"
 	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 
@@ -24,6 +25,11 @@ type ApplicationState struct {
 
 	// config stores configuration items
 	config *configs.Config
+
+	// instances are the named milvus instance connections
+	instances map[string]*instanceHandle
+	// current is the name of instance commands run against
+	current string
 }
 
 func (app *ApplicationState) Ctx() (context.Context, context.CancelFunc) {
@@ -44,6 +50,12 @@ func (app *ApplicationState) Label() string {
 
 func (app *ApplicationState) Process(cmd string) (framework.State, error) {
 	app.config.Log("[INFO] begin to process command", cmd)
+	if name, rest, ok := strings.Cut(cmd, " "); ok && strings.HasPrefix(name, "@") {
+		return app, app.processOnInstance(strings.TrimPrefix(name, "@"), rest)
+	}
+	if rest, ok := strings.CutPrefix(cmd, "foreach-instance "); ok {
+		return app, app.processForeachInstance(strings.TrimSpace(rest))
+	}
 	app.core.Process(cmd)
 	// perform sub state transfer
 	for key, state := range app.states {
@@ -51,7 +63,7 @@ func (app *ApplicationState) Process(cmd string) (framework.State, error) {
 		if next != nil {
 			app.config.Log("[DEBUG] set next", key, next.Label())
 			state.SetNext(key, nil)
-			app.states[tag] = next
+			app.SetTagNext(tag, next)
 		}
 	}
 
@@ -62,6 +74,11 @@ func (app *ApplicationState) Close() {
 	for _, state := range app.states {
 		state.Close()
 	}
+	for _, handle := range app.instances {
+		if app.states[handle.tag] != handle.state {
+			handle.state.Close()
+		}
+	}
 }
 
 func (app *ApplicationState) SetNext(tag string, state framework.State) {
@@ -74,6 +91,9 @@ func (app *ApplicationState) NextState() (string, framework.State) {
 
 func (app *ApplicationState) SetTagNext(tag string, state framework.State) {
 	app.states[tag] = state
+	if tag == etcdTag || tag == tikvTag {
+		app.trackInstance(tag, state)
+	}
 }
 
 func (app *ApplicationState) Suggestions(input string) map[string]string {
@@ -110,6 +130,29 @@ func (app *ApplicationState) IsEnding() bool {
 	return false
 }
 
+// connectHints are the commands to connect states commands could require.
+var connectHints = map[string]string{
+	etcdTag:  "connect --etcd [address] --rootPath [rootPath]",
+	tikvTag:  "connect --use_tikv --tikv [address] --rootPath [rootPath]",
+	minioTag: "connect-minio --address [address] --bucket [bucket]",
+}
+
+// ResolveState implements framework.StateResolver, resolves connected states for commands with `require` tag.
+func (app *ApplicationState) ResolveState(name string) (framework.State, error) {
+	hint, ok := connectHints[name]
+	if !ok {
+		return nil, errors.Newf("unknown required state %s", name)
+	}
+	state, ok := app.states[name]
+	if !ok {
+		return nil, errors.Newf("%s not connected, please run `%s` first", name, hint)
+	}
+	if _, ok := state.(*kvConnectedState); ok {
+		return nil, errors.Newf("%s connected without milvus instance, please run `use [instance]` first", name)
+	}
+	return state, nil
+}
+
 func (app *ApplicationState) ConnectMinioCommand(ctx context.Context, p *storage.ConnectMinioParam) error {
 	state, err := storage.ConnectMinio(ctx, p, app.core)
 	if err != nil {
@@ -0,0 +1,52 @@
+package states
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/etcd/remove"
+	"github.com/milvus-io/birdwatcher/states/etcd/repair"
+	metakv "github.com/milvus-io/birdwatcher/states/kv"
+)
+
+// Plan implements framework.Planner, runs command with remove & repair components
+// reading and writing a copy-on-write overlay of meta, then saves the meta changes into planOut.
+func (s *InstanceState) Plan(ctx context.Context, command, planOut string, run func(state framework.State) error) error {
+	return common.SavePlan(ctx, s.client, s.basePath, command, planOut, func(cli metakv.MetaKV) error {
+		shadow := *s
+		shadow.client = cli
+		shadow.ComponentRemove = remove.NewComponent(cli, s.config, s.basePath)
+		shadow.ComponentRepair = repair.NewComponent(cli, s.config, s.basePath)
+		return run(&shadow)
+	})
+}
+
+type ApplyPlanParam struct {
+	framework.ParamBase `use:"apply [plan-file]" desc:"apply meta change plan generated with --plan-out after verifying meta not changed since then"`
+	PlanFile            string `arg:"0" name:"plan-file" required:"true"`
+}
+
+// ApplyPlanCommand applies change plan saved by repair & remove commands.
+func (s *InstanceState) ApplyPlanCommand(ctx context.Context, p *ApplyPlanParam) error {
+	plan, err := metakv.ReadPlanFile(p.PlanFile)
+	if err != nil {
+		return err
+	}
+	if plan.BasePath != s.basePath {
+		return errors.Newf("plan generated for %s, current instance is %s", plan.BasePath, s.basePath)
+	}
+
+	fmt.Println(plan.String())
+	if err := metakv.ApplyPlan(ctx, s.client, plan); err != nil {
+		if errors.Is(err, metakv.ErrPlanConflict) {
+			return errors.Wrap(err, "plan is stale, please generate it again")
+		}
+		return err
+	}
+	fmt.Printf("%d change(s) applied\n", len(plan.Changes))
+	return nil
+}
@@ -0,0 +1,57 @@
+package states
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/etcd/remove"
+	"github.com/milvus-io/birdwatcher/states/etcd/show"
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+	metakv "github.com/milvus-io/birdwatcher/states/kv"
+)
+
+func TestApplyPlan(t *testing.T) {
+	ctx := context.Background()
+	c := fakecluster.New(t)
+	// collection 200 meta is gone, segment 2001 is orphan
+	c.AddCollection(fakecluster.NewCollection(100, "coll").WithPrimaryKey(100, "pk").WithPartition(101, "_default")).
+		AddSegment(fakecluster.NewSegment(1001, 100, 101).WithRows(10)).
+		AddSegment(fakecluster.NewSegment(2001, 200, 201).WithRows(10))
+
+	s := &InstanceState{
+		CmdState:        framework.NewCmdState("test", nil),
+		ComponentShow:   show.NewComponent(c.KV(), nil, fakecluster.RootPath, fakecluster.MetaPath),
+		ComponentRemove: remove.NewComponent(c.KV(), nil, c.BasePath()),
+		client:          c.KV(),
+		basePath:        c.BasePath(),
+	}
+	s.UpdateState(&cobra.Command{}, s, nil)
+	planFile := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, s.Collect("remove segment-orphan --plan-out "+planFile, func(_ framework.ResultSet, err error) {
+		assert.NoError(t, err)
+	}))
+	plan, err := metakv.ReadPlanFile(planFile)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	assert.Contains(t, plan.Changes[0].Reason, "collection 200 not found")
+
+	segments, err := common.ListSegments(ctx, c.KV(), c.BasePath())
+	require.NoError(t, err)
+	assert.Len(t, segments, 2)
+
+	require.NoError(t, s.ApplyPlanCommand(ctx, &ApplyPlanParam{PlanFile: planFile}))
+	segments, err = common.ListSegments(ctx, c.KV(), c.BasePath())
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+	assert.EqualValues(t, 1001, segments[0].ID)
+
+	// stale plan rejected
+	assert.Error(t, s.ApplyPlanCommand(ctx, &ApplyPlanParam{PlanFile: planFile}))
+}
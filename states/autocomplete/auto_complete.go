@@ -9,6 +9,9 @@ import (
 	"github.com/spf13/pflag"
 )
 
+// FlagEnumAnnotation is the flag annotation key for enum values, which are suggested as flag value.
+const FlagEnumAnnotation = "birdwatcher_enum"
+
 var (
 	// TODO add flags
 	debugSuggestion = false
@@ -53,7 +56,7 @@ func (c *cmdCandidate) Match(input cComp) bool {
 	if debugSuggestion {
 		fmt.Printf("cmd: %s, cType: %d, cTag: %s\n", c.cmdName(), input.cType, input.cTag)
 	}
-	return input.cType == cmdCompCommand && c.cmdName() == input.cTag
+	return input.cType == cmdCompCommand && (c.cmdName() == input.cTag || c.HasAlias(input.cTag))
 }
 
 // NextCandidates implements acCandidate, returns all subCommand and flags.
@@ -77,11 +80,21 @@ func (c *cmdCandidate) NextCandidates(_ []acCandidate) []acCandidate {
 }
 
 func (c *cmdCandidate) Suggest(target cComp) map[string]string {
+	result := make(map[string]string)
+	if target.cType == cmdCompFlagValue {
+		return result
+	}
 	k := c.cmdName()
 	if strings.HasPrefix(k, target.cTag) || target.cType == cmdCompAll {
-		return map[string]string{c.cmdName(): c.Short}
+		result[k] = c.Short
 	}
-	return map[string]string{}
+	for _, alias := range c.Aliases {
+		// alias suggested only when input does not match command name, unless alias suggestion enabled
+		if strings.HasPrefix(alias, target.cTag) && (len(result) == 0 || aliasSuggestion) {
+			result[alias] = c.Short
+		}
+	}
+	return result
 }
 
 // flagCandidate wraps pflag.Flag as acCandidate.
@@ -103,6 +116,10 @@ func (c *flagCandidate) Match(input cComp) bool {
 }
 
 func (c *flagCandidate) Suggest(target cComp) map[string]string {
+	// --flag value or --flag=value
+	if target.cTag == c.Name && (target.cType == cmdCompFlagValue || (target.cType == cmdCompFlag && target.cValue != "")) {
+		return c.suggestValues(target)
+	}
 	k := fmt.Sprintf("--%s", c.Name)
 	if (strings.HasPrefix(k, target.raw) && strings.HasPrefix(target.raw, "--")) || target.cType == cmdCompAll {
 		return map[string]string{k: c.Usage}
@@ -110,6 +127,21 @@ func (c *flagCandidate) Suggest(target cComp) map[string]string {
 	return map[string]string{}
 }
 
+// suggestValues returns enum values with input value prefix.
+func (c *flagCandidate) suggestValues(target cComp) map[string]string {
+	result := make(map[string]string)
+	prefix := ""
+	if strings.Contains(target.raw, "=") {
+		prefix = fmt.Sprintf("--%s=", c.Name)
+	}
+	for _, value := range c.Annotations[FlagEnumAnnotation] {
+		if strings.HasPrefix(strings.ToLower(value), strings.ToLower(target.cValue)) {
+			result[prefix+value] = c.Usage
+		}
+	}
+	return result
+}
+
 // NextCandidates iomplement acCandidate.
 func (c *flagCandidate) NextCandidates(current []acCandidate) []acCandidate {
 	// TODO add next value match all candidate
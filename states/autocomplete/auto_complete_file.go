@@ -24,6 +24,9 @@ func (c *fileCandidate) NextCandidates(_ []acCandidate) []acCandidate {
 }
 
 func (c *fileCandidate) Suggest(target cComp) map[string]string {
+	if target.cType == cmdCompFlagValue {
+		return map[string]string{}
+	}
 	ctag := target.cTag
 	var err error
 	if strings.HasPrefix(ctag, "~") {
@@ -6,6 +6,8 @@ const (
 	cmdCompAll cmdCompType = iota
 	cmdCompCommand
 	cmdCompFlag
+	// cmdCompFlagValue is the pending value of flag, cTag is the flag name
+	cmdCompFlagValue
 )
 
 type inputState int
@@ -69,7 +69,11 @@ func parseInput(input string) inputResult {
 
 	// add empty comp if end with space
 	if isEndBlank {
-		comps = append(comps, cComp{cType: cmdCompCommand})
+		if currentFlagValue {
+			comps = append(comps, cComp{cTag: comps[len(comps)-1].cTag, cType: cmdCompFlagValue})
+		} else {
+			comps = append(comps, cComp{cType: cmdCompCommand})
+		}
 	}
 
 	return inputResult{
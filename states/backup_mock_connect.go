@@ -186,8 +186,12 @@ func getEmbedEtcdInstance(server *embed.Etcd, cli kv.MetaKV, instanceName string
 	return state
 }
 
+// getEmbedEtcdInstanceV2 returns backup mock state, in-memory kv is used if embed etcd server is nil.
 func getEmbedEtcdInstanceV2(parent *framework.CmdState, server *embed.Etcd, config *configs.Config) *embedEtcdMockState {
-	client := kv.NewEtcdKV(v3client.New(server.Server))
+	var client kv.MetaKV = kv.NewMemoryKV()
+	if server != nil {
+		client = kv.NewEtcdKV(v3client.New(server.Server))
+	}
 	state := &embedEtcdMockState{
 		CmdState:       parent.Spawn(""),
 		server:         server,
@@ -135,18 +135,31 @@ func (s *InstanceState) TestScanBinlogsCommand(ctx context.Context, p *ScanBinlo
 func (s *InstanceState) ScanBinlogs(ctx context.Context, minioClient *minio.Client, bucketName string, rootPath string, collection *models.Collection, segment *models.Segment,
 	selectField func(fieldID int64) bool, fn func(map[int64]*storage.BinlogReader),
 ) {
+	err := scanSegmentBinlogs(ctx, minioClient, bucketName, rootPath, collection, segment, selectField, func(readers map[int64]*storage.BinlogReader) error {
+		fn(readers)
+		return nil
+	})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+}
+
+// scanSegmentBinlogs opens binlog readers of each binlog batch of segment, row id, timestamp & pk fields are always selected.
+func scanSegmentBinlogs(ctx context.Context, minioClient *minio.Client, bucketName string, rootPath string, collection *models.Collection, segment *models.Segment,
+	selectField func(fieldID int64) bool, fn func(map[int64]*storage.BinlogReader) error,
+) error {
 	pkField, has := lo.Find(collection.GetProto().Schema.Fields, func(field *schemapb.FieldSchema) bool {
 		return field.IsPrimaryKey
 	})
 	if !has {
-		return
+		return nil
 	}
 
 	pkFieldData, has := lo.Find(segment.GetBinlogs(), func(fieldBinlog *models.FieldBinlog) bool {
 		return fieldBinlog.FieldID == pkField.FieldID
 	})
 	if !has {
-		return
+		return nil
 	}
 
 	for idx := range pkFieldData.Binlogs {
@@ -155,25 +168,29 @@ func (s *InstanceState) ScanBinlogs(ctx context.Context, minioClient *minio.Clie
 			if fieldBinlog.FieldID != 0 && fieldBinlog.FieldID != 1 && fieldBinlog.FieldID != pkField.FieldID && !selectField(fieldBinlog.FieldID) {
 				continue
 			}
+			if idx >= len(fieldBinlog.Binlogs) {
+				return errors.Newf("segment %d field %d has %d binlogs, less than pk field", segment.ID, fieldBinlog.FieldID, len(fieldBinlog.Binlogs))
+			}
 			binlog := fieldBinlog.Binlogs[idx]
 			filePath := strings.ReplaceAll(binlog.LogPath, "ROOT_PATH", rootPath)
 			object, err := minioClient.GetObject(ctx, bucketName, filePath, minio.GetObjectOptions{})
 			if err != nil {
-				fmt.Println(err.Error())
-				return
+				return err
 			}
 
 			reader, _, err := storage.NewBinlogReader(object)
 			if err != nil {
-				fmt.Println(err.Error())
-				return
+				return errors.Wrapf(err, "failed to read binlog %s", filePath)
 			}
 
 			field2Binlog[fieldBinlog.FieldID] = reader
 		}
 
-		fn(field2Binlog)
+		if err := fn(field2Binlog); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 type BinlogIterator struct {
@@ -341,27 +358,27 @@ func writeParquetField(pqWriter *storage.ParquetWriter, field models.FieldSchema
 	var err error
 	switch field.DataType {
 	case models.DataTypeBool:
-		pqWriter.AppendBool(field.Name, v.(bool))
+		err = pqWriter.AppendBool(field.Name, v.(bool))
 	case models.DataTypeInt8:
-		pqWriter.AppendInt8(field.Name, v.(int8))
+		err = pqWriter.AppendInt8(field.Name, v.(int8))
 	case models.DataTypeInt16:
-		pqWriter.AppendInt16(field.Name, v.(int16))
+		err = pqWriter.AppendInt16(field.Name, v.(int16))
 	case models.DataTypeInt32:
-		pqWriter.AppendInt32(field.Name, v.(int32))
+		err = pqWriter.AppendInt32(field.Name, v.(int32))
 	case models.DataTypeInt64:
-		pqWriter.AppendInt64(field.Name, v.(int64))
+		err = pqWriter.AppendInt64(field.Name, v.(int64))
 	case models.DataTypeFloat:
-		pqWriter.AppendFloat32(field.Name, v.(float32))
+		err = pqWriter.AppendFloat32(field.Name, v.(float32))
 	case models.DataTypeDouble:
-		pqWriter.AppendFloat64(field.Name, v.(float64))
+		err = pqWriter.AppendFloat64(field.Name, v.(float64))
 	case models.DataTypeVarChar, models.DataTypeString:
-		pqWriter.AppendString(field.Name, v.(string))
+		err = pqWriter.AppendString(field.Name, v.(string))
 	case models.DataTypeJSON:
-		pqWriter.AppendBytes(field.Name, v.([]byte))
+		err = pqWriter.AppendBytes(field.Name, v.([]byte))
 	case models.DataTypeFloatVector:
-		pqWriter.AppendFloatVector(field.Name, v.([]float32))
+		err = pqWriter.AppendFloatVector(field.Name, v.([]float32))
 	case models.DataTypeBinaryVector:
-		pqWriter.AppendBinaryVector(field.Name, v.([]byte))
+		err = pqWriter.AppendBinaryVector(field.Name, v.([]byte))
 	default:
 		return errors.Newf("data type %v not supported", field.DataType)
 	}
@@ -0,0 +1,454 @@
+package states
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
+)
+
+// distribution check types, recorded as "check" in report extra.
+const (
+	distCheckLoadedDropped     = "loaded-dropped"
+	distCheckMissingInReplica  = "missing-in-replica"
+	distCheckDuplicateLoaded   = "duplicate-loaded"
+	distCheckLeaderViewUnsync  = "leader-view-unsync"
+	distCheckGrowingNonLeader  = "growing-on-non-leader"
+	distCheckTargetSegmentGone = "target-segment-gone"
+	distCheckPendingInReplica  = "pending-in-replica"
+	distCheckNodeUnreachable   = "node-unreachable"
+)
+
+// querycoord target scope, see querycoord meta.TargetScope.
+const (
+	targetScopeCurrent = 1
+	targetScopeNext    = 2
+)
+
+type CheckDistributionParam struct {
+	framework.ParamBase `use:"check distribution" desc:"check consistency between datacoord segments, querycoord targets and querynode distribution" readonly:"true"`
+	CollectionID        int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id to check, check all loaded collections if not provided"`
+	Format              string `name:"format" default:"default" desc:"output format, [default, json]"`
+}
+
+// CheckDistributionCommand implements `check distribution` command.
+func (c *InstanceState) CheckDistributionCommand(ctx context.Context, p *CheckDistributionParam) (*framework.PresetResultSet, error) {
+	results, err := c.checkDistribution(ctx, p.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	return framework.NewPresetResultSet(framework.NewListResult[HealthzCheckReports](results), framework.NameFormat(p.Format)), nil
+}
+
+// nodeDistribution is the distribution reported by one querynode.
+type nodeDistribution struct {
+	nodeID   int64
+	segments []*querypb.SegmentVersionInfo
+	channels map[string]struct{}
+	views    []*querypb.LeaderView
+}
+
+func (c *InstanceState) checkDistribution(ctx context.Context, collectionID int64) ([]*HealthzCheckReport, error) {
+	collFilter := func(id int64) bool { return collectionID == 0 || id == collectionID }
+
+	segments, err := common.ListSegments(ctx, c.client, c.basePath, func(s *models.Segment) bool {
+		return collFilter(s.CollectionID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	id2Segment := lo.SliceToMap(segments, func(s *models.Segment) (int64, *models.Segment) { return s.ID, s })
+
+	replicas, err := common.ListReplicas(ctx, c.client, c.basePath, func(r *models.Replica) bool {
+		return collFilter(r.GetProto().GetCollectionID())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := common.ListSessions(ctx, c.client, c.basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	dists, unreachable := fetchDistributions(ctx, sessions, collFilter)
+	targets := fetchSegmentTargets(ctx, sessions, collectionID)
+
+	var results []*HealthzCheckReport
+	for _, nodeID := range sortedKeys(unreachable) {
+		results = append(results, &HealthzCheckReport{
+			Msg: fmt.Sprintf("Node %d distribution not available, skip missing segment check of its replicas: %s", nodeID, unreachable[nodeID]),
+			Extra: map[string]any{
+				"check":   distCheckNodeUnreachable,
+				"node_id": nodeID,
+				"error":   unreachable[nodeID],
+			},
+		})
+	}
+	results = append(results, checkLoadedDropped(dists, id2Segment)...)
+	results = append(results, checkReplicaSegments(dists, unreachable, replicas, id2Segment, targets)...)
+	results = append(results, checkLeaderViews(dists)...)
+	results = append(results, checkTargetSegments(targets, id2Segment)...)
+	return results, nil
+}
+
+// fetchDistributions returns distributions of querynodes in sessions,
+// along with error message of nodes not connected or failed to report distribution.
+func fetchDistributions(ctx context.Context, sessions []*models.Session, collFilter func(int64) bool) (map[int64]*nodeDistribution, map[int64]string) {
+	unreachable := make(map[int64]string)
+	qns, err := getQueryNodeClients(sessions)
+	if err != nil {
+		fmt.Println("failed to connect querynodes", err.Error())
+	}
+	for _, session := range sessions {
+		if strings.ToLower(session.ServerName) != "querynode" {
+			continue
+		}
+		if _, ok := qns[session.ServerID]; !ok {
+			unreachable[session.ServerID] = fmt.Sprintf("failed to connect %s", session.Address)
+		}
+	}
+
+	result := make(map[int64]*nodeDistribution)
+	for nodeID, qn := range qns {
+		resp, err := qn.GetDataDistribution(ctx, &querypb.GetDataDistributionRequest{
+			Base: &commonpb.MsgBase{
+				SourceID: -1,
+				TargetID: nodeID,
+			},
+		})
+		if err == nil && resp.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
+			err = errors.New(resp.GetStatus().GetReason())
+		}
+		if err != nil {
+			unreachable[nodeID] = fmt.Sprintf("failed to get data distribution: %s", err.Error())
+			continue
+		}
+		result[nodeID] = &nodeDistribution{
+			nodeID: nodeID,
+			segments: lo.Filter(resp.GetSegments(), func(s *querypb.SegmentVersionInfo, _ int) bool {
+				return collFilter(s.GetCollection())
+			}),
+			channels: lo.SliceToMap(resp.GetChannels(), func(ch *querypb.ChannelVersionInfo) (string, struct{}) {
+				return ch.GetChannel(), struct{}{}
+			}),
+			views: lo.Filter(resp.GetLeaderViews(), func(lv *querypb.LeaderView, _ int) bool {
+				return collFilter(lv.GetCollection())
+			}),
+		}
+	}
+	return result, unreachable
+}
+
+// segmentTargets holds sealed segment ids in querycoord targets, keyed by collection id.
+type segmentTargets struct {
+	current map[int64]map[int64]struct{}
+	next    map[int64]map[int64]struct{}
+}
+
+// currentOf returns current target segments of collection, false if target not available.
+func (t *segmentTargets) currentOf(collectionID int64) (map[int64]struct{}, bool) {
+	if t == nil {
+		return nil, false
+	}
+	target, ok := t.current[collectionID]
+	return target, ok
+}
+
+// contains returns whether segment is in current or next target of collection.
+func (t *segmentTargets) contains(collectionID, segmentID int64) bool {
+	if t == nil {
+		return false
+	}
+	_, inCurrent := t.current[collectionID][segmentID]
+	_, inNext := t.next[collectionID][segmentID]
+	return inCurrent || inNext
+}
+
+// fetchSegmentTargets returns sealed segment ids in querycoord current & next target.
+// nil is returned when querycoord is not reachable or does not support target metrics.
+func fetchSegmentTargets(ctx context.Context, sessions []*models.Session, collectionID int64) *segmentTargets {
+	qc, err := getQueryCoordClient(sessions)
+	if err != nil {
+		fmt.Println("failed to connect querycoord, skip target check:", err.Error())
+		return nil
+	}
+
+	result := &segmentTargets{
+		current: make(map[int64]map[int64]struct{}),
+		next:    make(map[int64]map[int64]struct{}),
+	}
+	for _, scope := range []int{targetScopeCurrent, targetScopeNext} {
+		scoped := result.current
+		if scope == targetScopeNext {
+			scoped = result.next
+		}
+		m := map[string]any{
+			metricsinfo.MetricTypeKey:                    metricsinfo.TargetKey,
+			metricsinfo.MetricRequestParamTargetScopeKey: scope,
+		}
+		if collectionID > 0 {
+			m[metricsinfo.MetricRequestParamCollectionIDKey] = collectionID
+		}
+		req, err := metricsinfo.ConstructGetMetricsRequest(m)
+		if err != nil {
+			return nil
+		}
+		resp, err := qc.GetMetrics(ctx, req)
+		if err != nil || resp.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
+			fmt.Println("failed to fetch querycoord target, skip target check")
+			return nil
+		}
+		var targets []*metricsinfo.QueryCoordTarget
+		if err := json.Unmarshal([]byte(resp.GetResponse()), &targets); err != nil {
+			fmt.Println("failed to parse querycoord target, skip target check:", err.Error())
+			return nil
+		}
+		for _, target := range targets {
+			ids, ok := scoped[target.CollectionID]
+			if !ok {
+				ids = make(map[int64]struct{})
+				scoped[target.CollectionID] = ids
+			}
+			for _, segment := range target.Segments {
+				ids[segment.SegmentID] = struct{}{}
+			}
+		}
+	}
+	return result
+}
+
+func checkLoadedDropped(dists map[int64]*nodeDistribution, id2Segment map[int64]*models.Segment) []*HealthzCheckReport {
+	var results []*HealthzCheckReport
+	for _, dist := range sortedDistributions(dists) {
+		for _, segment := range dist.segments {
+			info, ok := id2Segment[segment.GetID()]
+			state := "gc-ed"
+			if ok {
+				state = info.GetState().String()
+			}
+			if ok && info.GetState() != commonpb.SegmentState_Dropped {
+				continue
+			}
+			results = append(results, &HealthzCheckReport{
+				Msg: fmt.Sprintf("Segment %d loaded on node %d while %s in meta", segment.GetID(), dist.nodeID, state),
+				Extra: map[string]any{
+					"check":         distCheckLoadedDropped,
+					"collection_id": segment.GetCollection(),
+					"segment_id":    segment.GetID(),
+					"node_id":       dist.nodeID,
+					"meta_state":    state,
+				},
+			})
+		}
+	}
+	return results
+}
+
+func checkReplicaSegments(dists map[int64]*nodeDistribution, unreachable map[int64]string, replicas []*models.Replica, id2Segment map[int64]*models.Segment, targets *segmentTargets) []*HealthzCheckReport {
+	var results []*HealthzCheckReport
+	for _, replica := range replicas {
+		r := replica.GetProto()
+		// segment id => nodes in this replica which loaded it
+		loaded := make(map[int64][]int64)
+		for _, nodeID := range lo.Union(r.GetNodes(), r.GetRoNodes()) {
+			dist, ok := dists[nodeID]
+			if !ok {
+				continue
+			}
+			for _, segment := range dist.segments {
+				if segment.GetCollection() != r.GetCollectionID() {
+					continue
+				}
+				loaded[segment.GetID()] = append(loaded[segment.GetID()], nodeID)
+			}
+		}
+
+		for _, segmentID := range sortedKeys(loaded) {
+			nodes := loaded[segmentID]
+			if len(nodes) <= 1 {
+				continue
+			}
+			results = append(results, &HealthzCheckReport{
+				Msg: fmt.Sprintf("Segment %d loaded %d times in replica %d, nodes: %v", segmentID, len(nodes), r.GetID(), nodes),
+				Extra: map[string]any{
+					"check":         distCheckDuplicateLoaded,
+					"collection_id": r.GetCollectionID(),
+					"replica_id":    r.GetID(),
+					"segment_id":    segmentID,
+					"node_ids":      nodes,
+				},
+			})
+		}
+
+		// segments served by unreachable nodes are unknown, missing ones cannot be told apart
+		if lo.ContainsBy(lo.Union(r.GetNodes(), r.GetRoNodes()), func(nodeID int64) bool {
+			_, ok := unreachable[nodeID]
+			return ok
+		}) {
+			continue
+		}
+
+		// expected sealed segments: current target if available, otherwise flushed segments in meta.
+		// segments only in next target are not loaded yet, which are reported as pending
+		var expected, pending []int64
+		if target, ok := targets.currentOf(r.GetCollectionID()); ok {
+			expected = lo.Keys(target)
+			for id := range targets.next[r.GetCollectionID()] {
+				if _, ok := target[id]; !ok {
+					pending = append(pending, id)
+				}
+			}
+		} else {
+			for id, segment := range id2Segment {
+				if segment.CollectionID == r.GetCollectionID() && segment.GetState() == commonpb.SegmentState_Flushed &&
+					segment.GetLevel() != datapb.SegmentLevel_L0 {
+					expected = append(expected, id)
+				}
+			}
+		}
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		for _, segmentID := range expected {
+			if _, ok := loaded[segmentID]; ok {
+				continue
+			}
+			results = append(results, &HealthzCheckReport{
+				Msg: fmt.Sprintf("Segment %d of collection %d not loaded in replica %d", segmentID, r.GetCollectionID(), r.GetID()),
+				Extra: map[string]any{
+					"check":         distCheckMissingInReplica,
+					"collection_id": r.GetCollectionID(),
+					"replica_id":    r.GetID(),
+					"segment_id":    segmentID,
+				},
+			})
+		}
+		sort.Slice(pending, func(i, j int) bool { return pending[i] < pending[j] })
+		for _, segmentID := range pending {
+			if _, ok := loaded[segmentID]; ok {
+				continue
+			}
+			results = append(results, &HealthzCheckReport{
+				Msg: fmt.Sprintf("Segment %d of collection %d only in next target, pending to load in replica %d", segmentID, r.GetCollectionID(), r.GetID()),
+				Extra: map[string]any{
+					"check":         distCheckPendingInReplica,
+					"collection_id": r.GetCollectionID(),
+					"replica_id":    r.GetID(),
+					"segment_id":    segmentID,
+				},
+			})
+		}
+	}
+	return results
+}
+
+func checkLeaderViews(dists map[int64]*nodeDistribution) []*HealthzCheckReport {
+	// segment id => nodes actually loaded it
+	loaded := make(map[int64]map[int64]struct{})
+	for _, dist := range dists {
+		for _, segment := range dist.segments {
+			if _, ok := loaded[segment.GetID()]; !ok {
+				loaded[segment.GetID()] = make(map[int64]struct{})
+			}
+			loaded[segment.GetID()][dist.nodeID] = struct{}{}
+		}
+	}
+
+	var results []*HealthzCheckReport
+	for _, dist := range sortedDistributions(dists) {
+		for _, lv := range dist.views {
+			_, isLeader := dist.channels[lv.GetChannel()]
+			growings := lo.Uniq(lo.Union(lv.GetGrowingSegmentIDs(), lo.Keys(lv.GetGrowingSegments())))
+			if !isLeader && len(growings) > 0 {
+				results = append(results, &HealthzCheckReport{
+					Msg: fmt.Sprintf("Node %d holds %d growing segment(s) of channel %s without watching it", dist.nodeID, len(growings), lv.GetChannel()),
+					Extra: map[string]any{
+						"check":         distCheckGrowingNonLeader,
+						"collection_id": lv.GetCollection(),
+						"channel":       lv.GetChannel(),
+						"node_id":       dist.nodeID,
+						"segment_ids":   growings,
+					},
+				})
+			}
+
+			for _, segmentID := range sortedKeys(lv.GetSegmentDist()) {
+				sd := lv.GetSegmentDist()[segmentID]
+				if _, ok := loaded[segmentID][sd.GetNodeID()]; ok {
+					continue
+				}
+				results = append(results, &HealthzCheckReport{
+					Msg: fmt.Sprintf("Leader view of channel %s on node %d routes segment %d to node %d, which does not load it", lv.GetChannel(), dist.nodeID, segmentID, sd.GetNodeID()),
+					Extra: map[string]any{
+						"check":         distCheckLeaderViewUnsync,
+						"collection_id": lv.GetCollection(),
+						"channel":       lv.GetChannel(),
+						"leader_id":     dist.nodeID,
+						"segment_id":    segmentID,
+						"node_id":       sd.GetNodeID(),
+					},
+				})
+			}
+		}
+	}
+	return results
+}
+
+func checkTargetSegments(targets *segmentTargets, id2Segment map[int64]*models.Segment) []*HealthzCheckReport {
+	if targets == nil {
+		return nil
+	}
+	// segments in both current & next target
+	merged := make(map[int64]map[int64]struct{})
+	for _, scoped := range []map[int64]map[int64]struct{}{targets.current, targets.next} {
+		for collectionID, ids := range scoped {
+			if _, ok := merged[collectionID]; !ok {
+				merged[collectionID] = make(map[int64]struct{})
+			}
+			for id := range ids {
+				merged[collectionID][id] = struct{}{}
+			}
+		}
+	}
+	var results []*HealthzCheckReport
+	for _, collectionID := range sortedKeys(merged) {
+		for _, segmentID := range sortedKeys(merged[collectionID]) {
+			info, ok := id2Segment[segmentID]
+			if ok && info.GetState() != commonpb.SegmentState_Dropped {
+				continue
+			}
+			results = append(results, &HealthzCheckReport{
+				Msg: fmt.Sprintf("Segment %d in querycoord target of collection %d is dropped or gc-ed in meta", segmentID, collectionID),
+				Extra: map[string]any{
+					"check":         distCheckTargetSegmentGone,
+					"collection_id": collectionID,
+					"segment_id":    segmentID,
+				},
+			})
+		}
+	}
+	return results
+}
+
+func sortedDistributions(dists map[int64]*nodeDistribution) []*nodeDistribution {
+	result := lo.Values(dists)
+	sort.Slice(result, func(i, j int) bool { return result[i].nodeID < result[j].nodeID })
+	return result
+}
+
+func sortedKeys[V any](m map[int64]V) []int64 {
+	keys := lo.Keys(m)
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
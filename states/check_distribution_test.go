@@ -0,0 +1,98 @@
+package states
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
+)
+
+func TestCheckDistribution(t *testing.T) {
+	type nodeSegments map[int64][]int64
+
+	cases := []struct {
+		tag    string
+		loaded nodeSegments
+		// nodes failing to report distribution
+		failed []int64
+		// current & next target segments, querycoord not started if nil
+		targets [][]int64
+		checks  []string
+	}{
+		{tag: "healthy", loaded: nodeSegments{1: {1001}, 2: {1002}}, checks: nil},
+		{tag: "dropped_loaded", loaded: nodeSegments{1: {1001, 1003}, 2: {1002}}, checks: []string{distCheckLoadedDropped}},
+		{tag: "duplicate_loaded", loaded: nodeSegments{1: {1001, 1002}, 2: {1002}}, checks: []string{distCheckDuplicateLoaded}},
+		{tag: "missing_in_replica", loaded: nodeSegments{1: {1001}}, checks: []string{distCheckMissingInReplica}},
+		{tag: "node_unreachable", loaded: nodeSegments{1: {1001}}, failed: []int64{2}, checks: []string{distCheckNodeUnreachable}},
+		{tag: "target_healthy", loaded: nodeSegments{1: {1001}}, targets: [][]int64{{1001}, {1001}}, checks: nil},
+		{tag: "target_missing", loaded: nodeSegments{1: {1001}}, targets: [][]int64{{1001, 1002}, {1001, 1002}}, checks: []string{distCheckMissingInReplica}},
+		{tag: "next_target_pending", loaded: nodeSegments{1: {1001}}, targets: [][]int64{{1001}, {1001, 1002}}, checks: []string{distCheckPendingInReplica}},
+		{tag: "target_gone", loaded: nodeSegments{1: {1001}}, targets: [][]int64{{1001}, {1001, 1003}}, checks: []string{distCheckPendingInReplica, distCheckTargetSegmentGone}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tag, func(t *testing.T) {
+			c := fakecluster.New(t)
+			c.AddCollection(fakecluster.NewCollection(100, "coll").WithPrimaryKey(100, "pk").WithPartition(101, "_default")).
+				AddSegment(fakecluster.NewSegment(1001, 100, 101).WithRows(10)).
+				AddSegment(fakecluster.NewSegment(1002, 100, 101).WithRows(10)).
+				AddSegment(fakecluster.NewSegment(1003, 100, 101).WithState(commonpb.SegmentState_Dropped)).
+				AddReplica(fakecluster.NewReplica(1, 100).WithNodes(1, 2))
+
+			for _, nodeID := range []int64{1, 2} {
+				qn := c.StartServer(fakecluster.RoleQueryNode, nodeID)
+				if lo.Contains(tc.failed, nodeID) {
+					qn.Handle("GetDataDistribution", func(ctx context.Context, req any) (any, error) {
+						return nil, errors.New("mock error")
+					})
+					continue
+				}
+				qn.Respond("GetDataDistribution", &querypb.GetDataDistributionResponse{
+					Status: &commonpb.Status{},
+					NodeID: nodeID,
+					Segments: lo.Map(tc.loaded[nodeID], func(id int64, _ int) *querypb.SegmentVersionInfo {
+						return &querypb.SegmentVersionInfo{ID: id, Collection: 100, Partition: 101}
+					}),
+				})
+			}
+
+			if tc.targets != nil {
+				qc := c.StartServer(fakecluster.RoleQueryCoord, 10)
+				qc.Handle("GetMetrics", func(ctx context.Context, req any) (any, error) {
+					params := make(map[string]any)
+					if err := json.Unmarshal([]byte(req.(*milvuspb.GetMetricsRequest).GetRequest()), &params); err != nil {
+						return nil, err
+					}
+					segments := tc.targets[0]
+					if params[metricsinfo.MetricRequestParamTargetScopeKey] == float64(targetScopeNext) {
+						segments = tc.targets[1]
+					}
+					bs, _ := json.Marshal([]*metricsinfo.QueryCoordTarget{{
+						CollectionID: 100,
+						Segments: lo.Map(segments, func(id int64, _ int) *metricsinfo.Segment {
+							return &metricsinfo.Segment{SegmentID: id}
+						}),
+					}})
+					return &milvuspb.GetMetricsResponse{Status: &commonpb.Status{}, Response: string(bs)}, nil
+				})
+			}
+
+			s := &InstanceState{client: c.KV(), basePath: c.BasePath()}
+			reports, err := s.checkDistribution(context.Background(), 100)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.checks, lo.Map(reports, func(r *HealthzCheckReport, _ int) string {
+				return r.Extra["check"].(string)
+			}))
+		})
+	}
+}
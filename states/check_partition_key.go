@@ -31,7 +31,7 @@ type CheckPartitionKeyParam struct {
 	MinioAddress        string `name:"minioAddr" default:"" desc:"the minio address to override, leave empty to use milvus.yaml value"`
 	OutputFormat        string `name:"outputFmt" default:"stdout"`
 
-	CollectionID int64 `name:"collection" default:"0" desc:"target collection to scan, default scan all partition key collections"`
+	CollectionID int64 `name:"collection" resolve:"collection" default:"0" desc:"target collection to scan, default scan all partition key collections"`
 }
 
 var errQuickExit = errors.New("quick exit")
@@ -22,7 +22,7 @@ import (
 )
 
 type GetConfigurationParam struct {
-	framework.ParamBase `use:"show configurations" desc:"iterate all online components and inspect configuration"`
+	framework.ParamBase `use:"show configurations" desc:"iterate all online components and inspect configuration" readonly:"true"`
 	Format              string `name:"format" default:"line" desc:"output format"`
 	DialTimeout         int64  `name:"dialTimeout" default:"2" desc:"grpc dial timeout in seconds"`
 	Filter              string `name:"filter" default:"" desc:"configuration key filter sub string"`
@@ -26,9 +26,33 @@ type ConsumeParam struct {
 	ShardName           string `name:"shard_name" default:"" desc:"shard name(vchannel name) to filter with"`
 	Detail              bool   `name:"detail" default:"false" desc:"print msg detail"`
 	ManualID            int64  `name:"manual_id" default:"0" desc:"manual id"`
+
+	// flags explicitly provided in command line
+	changed map[string]bool
+}
+
+// RecordFlags implements framework.FlagRecorder.
+func (p *ConsumeParam) RecordFlags(changed map[string]bool) {
+	p.changed = changed
+}
+
+// fillConsumeMQ uses mq settings from milvus config unless flags are provided in command line,
+// mq address of config is only used when mq type matches config one.
+func (s *InstanceState) fillConsumeMQ(p *ConsumeParam) {
+	if s.milvusConfig == nil {
+		return
+	}
+	if !p.changed["mq_type"] {
+		p.MqType = s.milvusConfig.MQType()
+	}
+	if !p.changed["mq_addr"] && p.MqType == s.milvusConfig.MQType() {
+		p.MqAddress = s.milvusConfig.MQAddress()
+	}
+	fmt.Printf("using mq %s(%s)\n", p.MqType, p.MqAddress)
 }
 
 func (s *InstanceState) ConsumeCommand(ctx context.Context, p *ConsumeParam) error {
+	s.fillConsumeMQ(p)
 	var messageID ifc.MessageID
 	switch p.StartPosition {
 	case "cp":
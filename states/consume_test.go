@@ -0,0 +1,37 @@
+package states
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/configs"
+)
+
+func TestFillConsumeMQ(t *testing.T) {
+	mc, err := configs.ParseMilvusConfig([]byte(`
+pulsar:
+  address: pulsar-0
+  port: 6650
+`), nil)
+	require.NoError(t, err)
+	s := &InstanceState{milvusConfig: mc}
+
+	p := &ConsumeParam{MqType: "pulsar", MqAddress: "pulsar://127.0.0.1:6650"}
+	s.fillConsumeMQ(p)
+	assert.Equal(t, "pulsar://pulsar-0:6650", p.MqAddress)
+
+	// explicitly provided value equal to default is kept
+	p = &ConsumeParam{MqType: "pulsar", MqAddress: "pulsar://127.0.0.1:6650"}
+	p.RecordFlags(map[string]bool{"mq_addr": true})
+	s.fillConsumeMQ(p)
+	assert.Equal(t, "pulsar://127.0.0.1:6650", p.MqAddress)
+
+	// config address does not apply to other mq type
+	p = &ConsumeParam{MqType: "kafka", MqAddress: "pulsar://127.0.0.1:6650"}
+	p.RecordFlags(map[string]bool{"mq_type": true})
+	s.fillConsumeMQ(p)
+	assert.Equal(t, "kafka", p.MqType)
+	assert.Equal(t, "pulsar://127.0.0.1:6650", p.MqAddress)
+}
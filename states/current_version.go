@@ -4,15 +4,13 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/cockroachdb/errors"
-
 	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
 	etcdversion "github.com/milvus-io/birdwatcher/states/etcd/version"
 )
 
 type ShowCurrentVersionParam struct {
-	framework.ParamBase `use:"show current-version" desc:"display current Milvus Meta data version"`
+	framework.ParamBase `use:"show current-version" desc:"display current Milvus Meta data version" readonly:"true"`
 }
 
 // ShowCurrentVersionCommand returns command for show current-version.
@@ -22,19 +20,11 @@ func (app *ApplicationState) ShowCurrentVersionCommand(ctx context.Context, p *S
 
 type SetCurrentVersionParam struct {
 	framework.ParamBase `use:"set current-version" desc:"set current version for etcd meta parsing"`
-	newVersion          string
-}
-
-func (p *SetCurrentVersionParam) ParseArgs(args []string) error {
-	if len(args) != 1 {
-		return errors.New("invalid parameter number")
-	}
-	p.newVersion = args[0]
-	return nil
+	NewVersion          string `arg:"0" name:"version" required:"true"`
 }
 
 func (app *ApplicationState) SetCurrentVersionCommand(ctx context.Context, param *SetCurrentVersionParam) error {
-	switch param.newVersion {
+	switch param.NewVersion {
 	case models.LTEVersion2_1:
 		fallthrough
 	case "LTEVersion2_1":
@@ -44,7 +34,7 @@ func (app *ApplicationState) SetCurrentVersionCommand(ctx context.Context, param
 	case "GTEVersion2_2":
 		etcdversion.SetVersion(models.GTEVersion2_2)
 	default:
-		fmt.Println("Invalid version string:", param.newVersion)
+		fmt.Println("Invalid version string:", param.NewVersion)
 	}
 	return nil
 }
@@ -18,8 +18,8 @@ import (
 )
 
 type GetDistributionParam struct {
-	framework.ParamBase `use:"show segment-loaded-grpc" desc:"list segments loaded information"`
-	CollectionID        int64 `name:"collection" default:"0" desc:"collection id to filter with"`
+	framework.ParamBase `use:"show segment-loaded-grpc" desc:"list segments loaded information" readonly:"true"`
+	CollectionID        int64 `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter with"`
 	NodeID              int64 `name:"node" default:"0" desc:"node id to check"`
 }
 
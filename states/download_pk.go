@@ -25,7 +25,7 @@ import (
 type DownloadPKParam struct {
 	framework.ParamBase `use:"download-pk" desc:"download segment pk with provided collection/segment id"`
 	MinioAddress        string `name:"minioAddr" default:"" desc:"the minio address to override, leave empty to use milvus.yaml value"`
-	CollectionID        int64  `name:"collection" default:"0" desc:"collection id to download"`
+	CollectionID        int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id to download"`
 	SegmentID           int64  `name:"segment" default:"0" desc:"segment id to download"`
 }
 
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
 	"github.com/milvus-io/birdwatcher/states/etcd/download"
 	"github.com/milvus-io/birdwatcher/states/etcd/remove"
 	"github.com/milvus-io/birdwatcher/states/etcd/repair"
@@ -33,8 +36,8 @@ func RepairCommand(cli kv.MetaKV, basePath string) *cobra.Command {
 
 	repairCmd.AddCommand(
 		// repair miss index metric_type
-		repair.IndexMetricCommand(cli, basePath),
-		repair.DiskAnnIndexParamsCommand(cli, basePath),
+		plannable(repair.IndexMetricCommand, cli, basePath),
+		plannable(repair.DiskAnnIndexParamsCommand, cli, basePath),
 		// check querynode collection leak
 		repair.CheckQNCollectionLeak(cli, basePath),
 	)
@@ -66,22 +69,68 @@ func RemoveCommand(cli kv.MetaKV, instanceName, basePath string) *cobra.Command
 
 	removeCmd.AddCommand(
 		// remove segment
-		remove.SegmentCommand(cli, basePath),
+		plannable(remove.SegmentCommand, cli, basePath),
 		// remove binlog file
-		remove.BinlogCommand(cli, basePath),
+		plannable(remove.BinlogCommand, cli, basePath),
 		// remove collection-drop
-		remove.CollectionDropCommand(cli, basePath),
+		plannable(remove.CollectionDropCommand, cli, basePath),
 		// remove sgements with collection dropped
-		remove.SegmentCollectionDroppedCommand(cli, basePath),
+		plannable(remove.SegmentCollectionDroppedCommand, cli, basePath),
 		// remove etcd-config
 		remove.EtcdConfigCommand(cli, instanceName),
 		// remove collection has been dropped
-		remove.CollectionCleanCommand(cli, basePath),
+		plannable(remove.CollectionCleanCommand, cli, basePath),
 	)
 
 	return removeCmd
 }
 
+// plannable adds `--plan-out` flag to command built by factory.
+// When plan-out provided, the command is executed with "--run" if supported against a copy-on-write
+// overlay of meta and the meta changes are saved as plan instead of modifying meta.
+func plannable(factory func(cli kv.MetaKV, basePath string) *cobra.Command, cli kv.MetaKV, basePath string) *cobra.Command {
+	cmd := factory(cli, basePath)
+	cmd.Flags().String("plan-out", "", "save meta change plan to file instead of modifying meta, execute it with apply command")
+	run := cmd.Run
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		planOut, err := cmd.Flags().GetString("plan-out")
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+		if planOut == "" {
+			run(cmd, args)
+			return
+		}
+
+		command := strings.Join(append([]string{cmd.CommandPath()}, args...), " ")
+		err = common.SavePlan(context.Background(), cli, basePath, command, planOut, func(cli kv.MetaKV) error {
+			shadow := factory(cli, basePath)
+			var err error
+			cmd.Flags().Visit(func(flag *pflag.Flag) {
+				if flag.Name == "plan-out" || err != nil {
+					return
+				}
+				err = shadow.Flags().Set(flag.Name, flag.Value.String())
+			})
+			if err != nil {
+				return err
+			}
+			if shadow.Flags().Lookup("run") != nil {
+				if err := shadow.Flags().Set("run", "true"); err != nil {
+					return err
+				}
+			}
+			shadow.Run(shadow, args)
+			return nil
+		})
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+	return cmd
+}
+
 // RawCommands provides raw "get" command to list kv in etcd
 func RawCommands(cli kv.MetaKV) []*cobra.Command {
 	cmd := &cobra.Command{
@@ -145,7 +145,7 @@ func GetCollectionByIDVersion(ctx context.Context, cli kv.MetaKV, basePath strin
 	}
 
 	if len(colls) == 0 {
-		return nil, errors.Newf("collection with id %d not found", collID)
+		return nil, errors.Wrapf(ErrCollectionNotFound, "collection with id %d", collID)
 	}
 
 	return colls[0], nil
@@ -3,15 +3,20 @@ package common
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/samber/lo"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/states/kv"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/pkg/v2/proto/etcdpb"
 )
 
@@ -92,3 +97,454 @@ func RemoveCollectionHistory(ctx context.Context, cli kv.MetaKV, basePath string
 	}
 	return nil
 }
+
+// snapshotEntry is one rootcoord snapshot kv, key layout `{basePath}/snapshots/{origin key}_ts{ts}`.
+type snapshotEntry struct {
+	key       string
+	origin    string
+	ts        uint64
+	value     []byte
+	tombstone bool
+}
+
+// listSnapshotEntries returns snapshot entries under provided origin key prefix sorted by ts.
+// match is applied with origin key(without basePath & snapshot prefix) to filter entries.
+func listSnapshotEntries(ctx context.Context, cli kv.MetaKV, basePath string, originPrefix string, match func(origin string) bool) ([]*snapshotEntry, error) {
+	snapshotBase := path.Join(basePath, SnapshotPrefix) + "/"
+	keys, values, err := cli.LoadWithPrefix(ctx, snapshotBase+originPrefix)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*snapshotEntry
+	for idx, key := range keys {
+		origin, ts, ok := SplitSnapshotKey(strings.TrimPrefix(key, snapshotBase))
+		if !ok || !match(origin) {
+			continue
+		}
+		entries = append(entries, &snapshotEntry{
+			key:       key,
+			origin:    origin,
+			ts:        ts,
+			value:     []byte(values[idx]),
+			tombstone: bytes.Equal([]byte(values[idx]), CollectionTombstone),
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].ts < entries[j].ts
+	})
+	return entries, nil
+}
+
+// listSnapshotOrigins returns distinct origin keys of snapshots under provided prefix, values are not loaded.
+func listSnapshotOrigins(ctx context.Context, cli kv.MetaKV, basePath string, originPrefix string, match func(origin string) bool) ([]string, error) {
+	snapshotBase := path.Join(basePath, SnapshotPrefix) + "/"
+	keys, _, err := cli.LoadWithPrefix(ctx, snapshotBase+originPrefix, kv.WithKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+	var origins []string
+	for _, key := range keys {
+		origin, _, ok := SplitSnapshotKey(strings.TrimPrefix(key, snapshotBase))
+		if ok && match(origin) {
+			origins = append(origins, origin)
+		}
+	}
+	return lo.Uniq(origins), nil
+}
+
+// SplitSnapshotKey splits snapshot key without basePath & snapshot prefix into origin key and ts.
+func SplitSnapshotKey(key string) (string, uint64, bool) {
+	idx := strings.LastIndex(key, "_ts")
+	if idx < 0 {
+		return "", 0, false
+	}
+	ts, err := strconv.ParseUint(key[idx+3:], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return key[:idx], ts, true
+}
+
+// ListCollectionTimeline returns the change timeline of collection decoded from rootcoord snapshots,
+// including collection state/schema/property, partition, field and alias changes.
+// Snapshots failed to decode are skipped and returned as warnings.
+func ListCollectionTimeline(ctx context.Context, cli kv.MetaKV, basePath string, collectionID int64) ([]*models.CollectionHistoryEvent, []string, error) {
+	idStr := strconv.FormatInt(collectionID, 10)
+	// root-coord/collection/{collID} or root-coord/database/collection-info/{dbID}/{collID}
+	origins := []string{path.Join(RCPrefix, CollectionPrefix, idStr)}
+	dbOrigins, err := listSnapshotOrigins(ctx, cli, basePath, path.Join(RCPrefix, DBPrefix, CollectionInfoPrefix)+"/", func(origin string) bool {
+		return path.Base(origin) == idStr
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	origins = append(origins, dbOrigins...)
+	var collEntries []*snapshotEntry
+	for _, origin := range origins {
+		entries, err := listSnapshotEntries(ctx, cli, basePath, origin+"_ts", func(o string) bool { return o == origin })
+		if err != nil {
+			return nil, nil, err
+		}
+		collEntries = append(collEntries, entries...)
+	}
+	sort.SliceStable(collEntries, func(i, j int) bool {
+		return collEntries[i].ts < collEntries[j].ts
+	})
+	partEntries, err := listSnapshotEntries(ctx, cli, basePath, path.Join(RCPrefix, PartitionPrefix, idStr)+"/", func(string) bool { return true })
+	if err != nil {
+		return nil, nil, err
+	}
+	fieldEntries, err := listSnapshotEntries(ctx, cli, basePath, path.Join(RCPrefix, FieldPrefix, idStr)+"/", func(string) bool { return true })
+	if err != nil {
+		return nil, nil, err
+	}
+	var aliasEntries []*snapshotEntry
+	for _, prefix := range []string{AliasPrefixWithoutDB, AliasPrefixDB} {
+		entries, err := listSnapshotEntries(ctx, cli, basePath, prefix+"/", func(string) bool { return true })
+		if err != nil {
+			return nil, nil, err
+		}
+		aliasEntries = append(aliasEntries, entries...)
+	}
+
+	d := &snapshotDecoder{}
+	events, createTs := collectionEvents(d, collEntries)
+	events = append(events, partitionEvents(d, partEntries)...)
+	events = append(events, fieldEvents(d, fieldEntries, createTs)...)
+	events = append(events, aliasEvents(d, aliasEntries, collectionID)...)
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Ts < events[j].Ts
+	})
+	return events, d.warnings, nil
+}
+
+// snapshotDecoder unmarshals snapshot values, failures are recorded as warnings.
+type snapshotDecoder struct {
+	warnings []string
+}
+
+func (d *snapshotDecoder) decode(entry *snapshotEntry, msg proto.Message) bool {
+	if err := proto.Unmarshal(entry.value, msg); err != nil {
+		d.warnings = append(d.warnings, fmt.Sprintf("failed to unmarshal snapshot %s, err: %s", entry.key, err.Error()))
+		return false
+	}
+	return true
+}
+
+func collectionEvents(d *snapshotDecoder, entries []*snapshotEntry) ([]*models.CollectionHistoryEvent, uint64) {
+	var events []*models.CollectionHistoryEvent
+	var prev *etcdpb.CollectionInfo
+	var createTs uint64
+	for _, entry := range entries {
+		event := &models.CollectionHistoryEvent{Ts: entry.ts, Key: entry.key}
+		if entry.tombstone {
+			event.Type = models.CollectionEventState
+			event.DDL = "DropCollection"
+			event.Detail = "collection meta removed(tombstone)"
+			events = append(events, event)
+			prev = nil
+			continue
+		}
+		info := &etcdpb.CollectionInfo{}
+		if !d.decode(entry, info) {
+			continue
+		}
+		event.Target = info.GetSchema().GetName()
+		if prev == nil {
+			createTs = entry.ts
+			event.Type = models.CollectionEventState
+			event.DDL = "CreateCollection"
+			event.Detail = fmt.Sprintf("collection %s state %s", info.GetSchema().GetName(), info.GetState().String())
+			events = append(events, event)
+			prev = info
+			continue
+		}
+
+		switch {
+		case prev.GetState() != info.GetState():
+			event.Type = models.CollectionEventState
+			event.DDL = collectionStateDDL(info.GetState())
+			event.Detail = fmt.Sprintf("state %s -> %s", prev.GetState().String(), info.GetState().String())
+		case !kvPairsEqual(prev.GetProperties(), info.GetProperties()):
+			event.Type = models.CollectionEventProperty
+			event.DDL = "AlterCollection"
+			event.Detail = "collection properties changed"
+		case prev.GetSchema().GetName() != info.GetSchema().GetName():
+			event.Type = models.CollectionEventSchema
+			event.DDL = "RenameCollection"
+			event.Detail = fmt.Sprintf("renamed %s -> %s", prev.GetSchema().GetName(), info.GetSchema().GetName())
+		case !proto.Equal(prev.GetSchema(), info.GetSchema()):
+			event.Type = models.CollectionEventSchema
+			event.DDL = "AlterCollection"
+			event.Detail = "collection schema changed"
+		case !sameIDs(prev.GetPartitionIDs(), info.GetPartitionIDs()):
+			// legacy meta, partitions embedded in collection info
+			event.Type = models.CollectionEventPartition
+			event.DDL = "CreatePartition/DropPartition"
+			event.Detail = fmt.Sprintf("partitions %v -> %v", prev.GetPartitionNames(), info.GetPartitionNames())
+		default:
+			event.Type = models.CollectionEventOther
+			event.DDL = "UpdateCollection"
+			event.Detail = "collection meta updated"
+		}
+		event.Changes = DiffCollectionInfo(prev, info)
+		events = append(events, event)
+		prev = info
+	}
+	return events, createTs
+}
+
+func collectionStateDDL(state etcdpb.CollectionState) string {
+	switch state {
+	case etcdpb.CollectionState_CollectionCreating, etcdpb.CollectionState_CollectionCreated:
+		return "CreateCollection"
+	case etcdpb.CollectionState_CollectionDropping, etcdpb.CollectionState_CollectionDropped:
+		return "DropCollection"
+	default:
+		return "UpdateCollection"
+	}
+}
+
+func partitionEvents(d *snapshotDecoder, entries []*snapshotEntry) []*models.CollectionHistoryEvent {
+	var events []*models.CollectionHistoryEvent
+	prevs := make(map[string]*etcdpb.PartitionInfo)
+	for _, entry := range entries {
+		event := &models.CollectionHistoryEvent{Ts: entry.ts, Key: entry.key, Type: models.CollectionEventPartition}
+		prev, has := prevs[entry.origin]
+		if entry.tombstone {
+			event.DDL = "DropPartition"
+			event.Target = path.Base(entry.origin)
+			if has {
+				event.Target = prev.GetPartitionName()
+			}
+			event.Detail = "partition meta removed(tombstone)"
+			events = append(events, event)
+			delete(prevs, entry.origin)
+			continue
+		}
+		info := &etcdpb.PartitionInfo{}
+		if !d.decode(entry, info) {
+			continue
+		}
+		event.Target = info.GetPartitionName()
+		switch {
+		case !has:
+			event.DDL = "CreatePartition"
+			event.Detail = fmt.Sprintf("partition %d state %s", info.GetPartitionID(), info.GetState().String())
+		case prev.GetState() != info.GetState():
+			event.DDL = partitionStateDDL(info.GetState())
+			event.Detail = fmt.Sprintf("partition %d state %s -> %s", info.GetPartitionID(), prev.GetState().String(), info.GetState().String())
+		default:
+			event.DDL = "UpdatePartition"
+			event.Detail = fmt.Sprintf("partition %d meta updated", info.GetPartitionID())
+		}
+		if has {
+			event.Changes = diffProto(prev, info)
+		}
+		events = append(events, event)
+		prevs[entry.origin] = info
+	}
+	return events
+}
+
+func partitionStateDDL(state etcdpb.PartitionState) string {
+	switch state {
+	case etcdpb.PartitionState_PartitionCreating, etcdpb.PartitionState_PartitionCreated:
+		return "CreatePartition"
+	case etcdpb.PartitionState_PartitionDropping, etcdpb.PartitionState_PartitionDropped:
+		return "DropPartition"
+	default:
+		return "UpdatePartition"
+	}
+}
+
+// fieldEvents returns field changes, fields written within collection creation are ignored.
+func fieldEvents(d *snapshotDecoder, entries []*snapshotEntry, createTs uint64) []*models.CollectionHistoryEvent {
+	var events []*models.CollectionHistoryEvent
+	prevs := make(map[string]*schemapb.FieldSchema)
+	for _, entry := range entries {
+		event := &models.CollectionHistoryEvent{Ts: entry.ts, Key: entry.key, Type: models.CollectionEventSchema}
+		prev, has := prevs[entry.origin]
+		if entry.tombstone {
+			// fields removed along with collection drop
+			delete(prevs, entry.origin)
+			continue
+		}
+		field := &schemapb.FieldSchema{}
+		if !d.decode(entry, field) {
+			continue
+		}
+		prevs[entry.origin] = field
+		event.Target = field.GetName()
+		switch {
+		case !has && entry.ts <= createTs:
+			continue
+		case !has:
+			event.DDL = "AddCollectionField"
+			event.Detail = fmt.Sprintf("field %d(%s) added", field.GetFieldID(), field.GetDataType().String())
+		case proto.Equal(prev, field):
+			continue
+		default:
+			event.DDL = "AlterCollectionField"
+			event.Detail = fmt.Sprintf("field %d changed", field.GetFieldID())
+			event.Changes = diffProto(prev, field)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// aliasEvents returns alias changes related to provided collection.
+func aliasEvents(d *snapshotDecoder, entries []*snapshotEntry, collectionID int64) []*models.CollectionHistoryEvent {
+	var events []*models.CollectionHistoryEvent
+	// current collection id alias pointed to
+	current := make(map[string]int64)
+	for _, entry := range entries {
+		name := path.Base(entry.origin)
+		prevColl, has := current[entry.origin]
+		event := &models.CollectionHistoryEvent{Ts: entry.ts, Key: entry.key, Type: models.CollectionEventAlias, Target: name}
+		if entry.tombstone {
+			delete(current, entry.origin)
+			if has && prevColl == collectionID {
+				event.DDL = "DropAlias"
+				event.Detail = "alias meta removed(tombstone)"
+				events = append(events, event)
+			}
+			continue
+		}
+		info := &etcdpb.AliasInfo{}
+		if !d.decode(entry, info) {
+			continue
+		}
+		current[entry.origin] = info.GetCollectionId()
+		dropped := info.GetState() == etcdpb.AliasState_AliasDropping || info.GetState() == etcdpb.AliasState_AliasDropped
+		switch {
+		case info.GetCollectionId() == collectionID && dropped:
+			event.DDL = "DropAlias"
+			event.Detail = fmt.Sprintf("alias state %s", info.GetState().String())
+		case info.GetCollectionId() == collectionID && !has:
+			event.DDL = "CreateAlias"
+			event.Detail = fmt.Sprintf("alias %s created", name)
+		case info.GetCollectionId() == collectionID && prevColl != collectionID:
+			event.DDL = "AlterAlias"
+			event.Detail = fmt.Sprintf("alias moved from collection %d", prevColl)
+		case has && prevColl == collectionID && info.GetCollectionId() != collectionID:
+			event.DDL = "AlterAlias"
+			event.Detail = fmt.Sprintf("alias moved to collection %d", info.GetCollectionId())
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// DiffCollectionInfo returns human readable diff lines between two collection info snapshots.
+func DiffCollectionInfo(prev, curr *etcdpb.CollectionInfo) []string {
+	var changes []string
+	if prev.GetState() != curr.GetState() {
+		changes = append(changes, fmt.Sprintf("State: %s -> %s", prev.GetState().String(), curr.GetState().String()))
+	}
+	if prev.GetSchema().GetName() != curr.GetSchema().GetName() {
+		changes = append(changes, fmt.Sprintf("Name: %s -> %s", prev.GetSchema().GetName(), curr.GetSchema().GetName()))
+	}
+	if prev.GetSchema().GetDescription() != curr.GetSchema().GetDescription() {
+		changes = append(changes, fmt.Sprintf("Description: %q -> %q", prev.GetSchema().GetDescription(), curr.GetSchema().GetDescription()))
+	}
+	if prev.GetConsistencyLevel() != curr.GetConsistencyLevel() {
+		changes = append(changes, fmt.Sprintf("ConsistencyLevel: %s -> %s", prev.GetConsistencyLevel().String(), curr.GetConsistencyLevel().String()))
+	}
+	if prev.GetShardsNum() != curr.GetShardsNum() {
+		changes = append(changes, fmt.Sprintf("ShardsNum: %d -> %d", prev.GetShardsNum(), curr.GetShardsNum()))
+	}
+	if prev.GetSchema().GetEnableDynamicField() != curr.GetSchema().GetEnableDynamicField() {
+		changes = append(changes, fmt.Sprintf("EnableDynamicField: %t -> %t", prev.GetSchema().GetEnableDynamicField(), curr.GetSchema().GetEnableDynamicField()))
+	}
+	changes = append(changes, diffKVPairs("Property", prev.GetProperties(), curr.GetProperties())...)
+
+	prevFields := lo.SliceToMap(prev.GetSchema().GetFields(), func(f *schemapb.FieldSchema) (int64, *schemapb.FieldSchema) { return f.GetFieldID(), f })
+	for _, field := range curr.GetSchema().GetFields() {
+		pf, ok := prevFields[field.GetFieldID()]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("+Field %d: %s(%s)", field.GetFieldID(), field.GetName(), field.GetDataType().String()))
+		case !proto.Equal(pf, field):
+			for _, line := range diffProto(pf, field) {
+				changes = append(changes, fmt.Sprintf("Field %d %s", field.GetFieldID(), line))
+			}
+		}
+		delete(prevFields, field.GetFieldID())
+	}
+	for id, field := range prevFields {
+		changes = append(changes, fmt.Sprintf("-Field %d: %s(%s)", id, field.GetName(), field.GetDataType().String()))
+	}
+
+	if !sameIDs(prev.GetPartitionIDs(), curr.GetPartitionIDs()) {
+		changes = append(changes, fmt.Sprintf("PartitionIDs: %v -> %v", prev.GetPartitionIDs(), curr.GetPartitionIDs()))
+	}
+	return changes
+}
+
+func sameIDs(a, b []int64) bool {
+	l, r := lo.Difference(a, b)
+	return len(l) == 0 && len(r) == 0
+}
+
+func kvPairsEqual(a, b []*commonpb.KeyValuePair) bool {
+	return len(diffKVPairs("", a, b)) == 0
+}
+
+func diffKVPairs(name string, prev, curr []*commonpb.KeyValuePair) []string {
+	var changes []string
+	pm := lo.SliceToMap(prev, func(kv *commonpb.KeyValuePair) (string, string) { return kv.GetKey(), kv.GetValue() })
+	cm := lo.SliceToMap(curr, func(kv *commonpb.KeyValuePair) (string, string) { return kv.GetKey(), kv.GetValue() })
+	for _, kv := range curr {
+		pv, ok := pm[kv.GetKey()]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("+%s %s=%s", name, kv.GetKey(), kv.GetValue()))
+		case pv != kv.GetValue():
+			changes = append(changes, fmt.Sprintf("~%s %s: %s -> %s", name, kv.GetKey(), pv, kv.GetValue()))
+		}
+	}
+	for _, kv := range prev {
+		if _, ok := cm[kv.GetKey()]; !ok {
+			changes = append(changes, fmt.Sprintf("-%s %s=%s", name, kv.GetKey(), kv.GetValue()))
+		}
+	}
+	return changes
+}
+
+// diffProto returns top-level field diffs of two proto messages of same type.
+func diffProto(prev, curr proto.Message) []string {
+	var changes []string
+	pr, cr := prev.ProtoReflect(), curr.ProtoReflect()
+	fields := cr.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		pv, cv := pr.Get(fd), cr.Get(fd)
+		if pv.Equal(cv) {
+			continue
+		}
+		switch {
+		case fd.IsList() && fd.Message() != nil && fd.Message().FullName() == "milvus.proto.common.KeyValuePair":
+			changes = append(changes, diffKVPairs(string(fd.Name()), kvPairsFromList(pv.List()), kvPairsFromList(cv.List()))...)
+		case fd.IsList() || fd.IsMap() || fd.Message() != nil:
+			changes = append(changes, fmt.Sprintf("%s changed", fd.Name()))
+		default:
+			changes = append(changes, fmt.Sprintf("%s: %v -> %v", fd.Name(), pv.Interface(), cv.Interface()))
+		}
+	}
+	return changes
+}
+
+func kvPairsFromList(list protoreflect.List) []*commonpb.KeyValuePair {
+	result := make([]*commonpb.KeyValuePair, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		if kv, ok := list.Get(i).Message().Interface().(*commonpb.KeyValuePair); ok {
+			result = append(result, kv)
+		}
+	}
+	return result
+}
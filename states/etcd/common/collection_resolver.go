@@ -0,0 +1,192 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/kv"
+	"github.com/milvus-io/milvus/pkg/v2/proto/etcdpb"
+)
+
+const defaultDatabaseName = "default"
+
+// ErrCollectionAmbiguous is the error for collection name matching collections in multiple databases.
+var ErrCollectionAmbiguous = errors.New("collection name ambiguous")
+
+// CollectionResolver resolves collection reference, which could be collection id, name, `db.name` or alias.
+type CollectionResolver struct {
+	collections []*models.Collection
+	aliases     []*models.Alias
+	dbNames     map[int64]string
+}
+
+// NewCollectionResolver loads collection, alias & database meta for resolving collection references.
+// Field schemas are not loaded, use GetCollectionByIDVersion for complete collection info.
+func NewCollectionResolver(ctx context.Context, cli kv.MetaKV, basePath string) (*CollectionResolver, error) {
+	r := &CollectionResolver{}
+	for _, prefix := range []string{CollectionMetaPrefix, DBCollectionMetaPrefix} {
+		collections, err := ListObj2Models(ctx, cli, path.Join(basePath, prefix), models.NewCollection)
+		if err != nil {
+			return nil, err
+		}
+		r.collections = append(r.collections, collections...)
+	}
+
+	aliases, err := ListAlias(ctx, cli, basePath, "")
+	if err != nil {
+		return nil, err
+	}
+	r.aliases = aliases
+
+	dbs, err := ListDatabase(ctx, cli, basePath)
+	if err != nil {
+		return nil, err
+	}
+	r.dbNames = lo.SliceToMap(dbs, func(db *models.Database) (int64, string) {
+		return db.GetProto().GetId(), db.GetProto().GetName()
+	})
+	return r, nil
+}
+
+// ResolveID returns collection id of reference, 0 for empty reference.
+// Numeric id is returned as is, so that meta of dropped collection could still be inspected.
+func (r *CollectionResolver) ResolveID(ref string) (int64, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return 0, nil
+	}
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		return id, nil
+	}
+	collection, err := r.Resolve(ref)
+	if err != nil {
+		return 0, err
+	}
+	return collection.GetProto().GetID(), nil
+}
+
+// Resolve returns collection matching reference. Name shall be unique among databases unless database
+// is specified with `db.name`, and name of dropped collection is reported with ErrCollectionDropped.
+func (r *CollectionResolver) Resolve(ref string) (*models.Collection, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		collection, ok := lo.Find(r.collections, func(collection *models.Collection) bool {
+			return collection.GetProto().GetID() == id
+		})
+		if !ok {
+			return nil, errors.Wrapf(ErrCollectionNotFound, "collection with id %d", id)
+		}
+		return collection, nil
+	}
+
+	db, name, hasDB := strings.Cut(ref, ".")
+	if !hasDB {
+		db, name = "", ref
+	}
+
+	candidates := lo.Filter(r.collections, func(collection *models.Collection, _ int) bool {
+		return collection.GetProto().GetSchema().GetName() == name &&
+			(db == "" || r.DBName(collection.GetProto().GetDbId()) == db)
+	})
+	if len(candidates) == 0 {
+		// resolve alias
+		aliases := lo.Filter(r.aliases, func(alias *models.Alias, _ int) bool {
+			return alias.Name == name && alias.State == models.AliasStateAliasCreated &&
+				(db == "" || r.DBName(alias.DBID) == db)
+		})
+		candidates = lo.Filter(r.collections, func(collection *models.Collection, _ int) bool {
+			return lo.ContainsBy(aliases, func(alias *models.Alias) bool {
+				return alias.CollectionID == collection.GetProto().GetID()
+			})
+		})
+	}
+
+	alive := lo.Filter(candidates, func(collection *models.Collection, _ int) bool {
+		return !isCollectionDropped(collection)
+	})
+	switch {
+	case len(alive) == 1:
+		return alive[0], nil
+	case len(alive) > 1:
+		return nil, errors.Wrapf(ErrCollectionAmbiguous, "%s matches %s, use db.name or collection id instead",
+			ref, strings.Join(lo.Map(alive, func(collection *models.Collection, _ int) string {
+				return fmt.Sprintf("%s(%d)", r.Name(collection), collection.GetProto().GetID())
+			}), ", "))
+	case len(candidates) > 0:
+		return nil, errors.Wrapf(ErrCollectionDropped, "collection %s(%d) is %s", ref,
+			candidates[0].GetProto().GetID(), candidates[0].GetProto().GetState().String())
+	default:
+		return nil, errors.Wrapf(ErrCollectionNotFound, "collection or alias %s", ref)
+	}
+}
+
+// DBName returns database name of id, legacy collection without database belongs to default database.
+func (r *CollectionResolver) DBName(dbID int64) string {
+	if name, ok := r.dbNames[dbID]; ok {
+		return name
+	}
+	if dbID == 0 {
+		return defaultDatabaseName
+	}
+	return strconv.FormatInt(dbID, 10)
+}
+
+// Name returns collection name with database, e.g. `default.coll`.
+func (r *CollectionResolver) Name(collection *models.Collection) string {
+	return fmt.Sprintf("%s.%s", r.DBName(collection.GetProto().GetDbId()), collection.GetProto().GetSchema().GetName())
+}
+
+// Names returns collection id to `db.name` mapping for output.
+func (r *CollectionResolver) Names() map[int64]string {
+	return lo.SliceToMap(r.collections, func(collection *models.Collection) (int64, string) {
+		return collection.GetProto().GetID(), r.Name(collection)
+	})
+}
+
+// ResolveCollectionID resolves collection reference into collection id, see CollectionResolver.ResolveID.
+func ResolveCollectionID(ctx context.Context, cli kv.MetaKV, basePath string, ref string) (int64, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return 0, nil
+	}
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		return id, nil
+	}
+	r, err := NewCollectionResolver(ctx, cli, basePath)
+	if err != nil {
+		return 0, err
+	}
+	return r.ResolveID(ref)
+}
+
+// CollectionNames returns collection id to `db.name` mapping, empty mapping returned if meta not available.
+func CollectionNames(ctx context.Context, cli kv.MetaKV, basePath string) map[int64]string {
+	r, err := NewCollectionResolver(ctx, cli, basePath)
+	if err != nil {
+		return map[int64]string{}
+	}
+	return r.Names()
+}
+
+// CollectionLabel returns `db.name(id)` of collection for output, id only if name not found.
+func CollectionLabel(names map[int64]string, collectionID int64) string {
+	if name, ok := names[collectionID]; ok {
+		return fmt.Sprintf("%s(%d)", name, collectionID)
+	}
+	return strconv.FormatInt(collectionID, 10)
+}
+
+func isCollectionDropped(collection *models.Collection) bool {
+	switch collection.GetProto().GetState() {
+	case etcdpb.CollectionState_CollectionDropping, etcdpb.CollectionState_CollectionDropped:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package common_test
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+	"github.com/milvus-io/milvus/pkg/v2/proto/etcdpb"
+)
+
+func TestCollectionResolver(t *testing.T) {
+	c := fakecluster.New(t)
+	c.PutProto(path.Join(common.RCPrefix, common.DBPrefix, common.DBInfoPrefix, "1"), &etcdpb.DatabaseInfo{Id: 1, Name: "default"})
+	c.PutProto(path.Join(common.RCPrefix, common.DBPrefix, common.DBInfoPrefix, "2"), &etcdpb.DatabaseInfo{Id: 2, Name: "db2"})
+	c.AddCollection(fakecluster.NewCollection(100, "coll").WithDB(1)).
+		AddCollection(fakecluster.NewCollection(200, "coll").WithDB(2)).
+		AddCollection(fakecluster.NewCollection(300, "other").WithDB(2)).
+		AddCollection(fakecluster.NewCollection(400, "gone").WithDB(1).WithState(etcdpb.CollectionState_CollectionDropped))
+	c.PutProto(path.Join(common.AliasPrefixDB, "2/alias"), &etcdpb.AliasInfo{AliasName: "alias", CollectionId: 300, DbId: 2})
+
+	ctx := context.Background()
+	r, err := common.NewCollectionResolver(ctx, c.KV(), c.BasePath())
+	require.NoError(t, err)
+
+	cases := []struct {
+		ref    string
+		id     int64
+		expect error
+	}{
+		{ref: "", id: 0},
+		{ref: "400", id: 400},
+		{ref: "default.coll", id: 100},
+		{ref: "db2.coll", id: 200},
+		{ref: "other", id: 300},
+		{ref: "alias", id: 300},
+		{ref: "db2.alias", id: 300},
+		{ref: "coll", expect: common.ErrCollectionAmbiguous},
+		{ref: "gone", expect: common.ErrCollectionDropped},
+		{ref: "default.other", expect: common.ErrCollectionNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.ref, func(t *testing.T) {
+			id, err := r.ResolveID(tc.ref)
+			if tc.expect != nil {
+				assert.ErrorIs(t, err, tc.expect)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.id, id)
+		})
+	}
+
+	names := common.CollectionNames(ctx, c.KV(), c.BasePath())
+	assert.Equal(t, "db2.coll(200)", common.CollectionLabel(names, 200))
+	assert.Equal(t, "500", common.CollectionLabel(names, 500))
+}
@@ -24,6 +24,11 @@ const (
 	SegmentStatsMetaPrefix = "datacoord-meta/statslog"
 
 	CompactionTaskPrefix = `compaction-task`
+
+	StatsTaskPrefix                    = `stats-task`
+	AnalyzeTaskPrefix                  = `analyze-task`
+	PartitionStatsPrefix               = `partition-stats`
+	PartitionStatsCurrentVersionPrefix = `current-partition-stats-version`
 )
 
 const (
@@ -0,0 +1,40 @@
+package common
+
+import (
+	"context"
+	"path"
+	"strconv"
+
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/kv"
+)
+
+// ListStatsTask returns stats task meta as provided filters.
+func ListStatsTask(ctx context.Context, cli kv.MetaKV, basePath string, filters ...func(task *models.StatsTask) bool) ([]*models.StatsTask, error) {
+	prefix := path.Join(basePath, DCPrefix, StatsTaskPrefix) + "/"
+	return ListObj2Models(ctx, cli, prefix, models.NewStatsTask, filters...)
+}
+
+// ListAnalyzeTask returns analyze task meta as provided filters.
+func ListAnalyzeTask(ctx context.Context, cli kv.MetaKV, basePath string, filters ...func(task *models.AnalyzeTask) bool) ([]*models.AnalyzeTask, error) {
+	prefix := path.Join(basePath, DCPrefix, AnalyzeTaskPrefix) + "/"
+	return ListObj2Models(ctx, cli, prefix, models.NewAnalyzeTask, filters...)
+}
+
+// ListPartitionStats returns partition stats info meta as provided filters.
+func ListPartitionStats(ctx context.Context, cli kv.MetaKV, basePath string, filters ...func(info *models.PartitionStats) bool) ([]*models.PartitionStats, error) {
+	prefix := path.Join(basePath, DCPrefix, PartitionStatsPrefix) + "/"
+	return ListObj2Models(ctx, cli, prefix, models.NewPartitionStats, filters...)
+}
+
+// GetCurrentPartitionStatsVersion returns current partition stats version of collection/partition/channel.
+// "0" returned if no version recorded.
+func GetCurrentPartitionStatsVersion(ctx context.Context, cli kv.MetaKV, basePath string, info *models.PartitionStats) string {
+	p := info.GetProto()
+	key := path.Join(basePath, DCPrefix, PartitionStatsCurrentVersionPrefix, strconv.FormatInt(p.GetCollectionID(), 10), strconv.FormatInt(p.GetPartitionID(), 10), p.GetVChannel())
+	val, err := cli.Load(ctx, key)
+	if err != nil {
+		return "0"
+	}
+	return val
+}
@@ -0,0 +1,75 @@
+package common
+
+import (
+	"path"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/etcdpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/indexpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+)
+
+// metaType is the proto message type stored under meta key prefix.
+type metaType struct {
+	prefix string
+	newMsg func() proto.Message
+}
+
+// metaTypes lists known meta prefixes, longer prefixes shall be placed before their parents.
+var metaTypes = []metaType{
+	{prefix: DBCollectionMetaPrefix, newMsg: func() proto.Message { return &etcdpb.CollectionInfo{} }},
+	{prefix: DataBaseMetaPrefix, newMsg: func() proto.Message { return &etcdpb.DatabaseInfo{} }},
+	{prefix: AliasPrefixDB, newMsg: func() proto.Message { return &etcdpb.AliasInfo{} }},
+	{prefix: AliasPrefixWithoutDB, newMsg: func() proto.Message { return &etcdpb.AliasInfo{} }},
+	{prefix: CollectionMetaPrefix, newMsg: func() proto.Message { return &etcdpb.CollectionInfo{} }},
+	{prefix: path.Join(RCPrefix, PartitionPrefix), newMsg: func() proto.Message { return &etcdpb.PartitionInfo{} }},
+	{prefix: FieldMetaPrefix, newMsg: func() proto.Message { return &schemapb.FieldSchema{} }},
+	{prefix: path.Join(DCPrefix, SegmentMetaPrefix), newMsg: func() proto.Message { return &datapb.SegmentInfo{} }},
+	{prefix: path.Join(DCPrefix, "binlog"), newMsg: func() proto.Message { return &datapb.FieldBinlog{} }},
+	{prefix: path.Join(DCPrefix, "deltalog"), newMsg: func() proto.Message { return &datapb.FieldBinlog{} }},
+	{prefix: SegmentStatsMetaPrefix, newMsg: func() proto.Message { return &datapb.FieldBinlog{} }},
+	{prefix: path.Join(DCPrefix, ChannelCheckpointPrefix), newMsg: func() proto.Message { return &msgpb.MsgPosition{} }},
+	{prefix: path.Join(DCPrefix, CompactionTaskPrefix), newMsg: func() proto.Message { return &datapb.CompactionTask{} }},
+	{prefix: path.Join(DCPrefix, PartitionStatsPrefix), newMsg: func() proto.Message { return &datapb.PartitionStatsInfo{} }},
+	{prefix: path.Join(DCPrefix, AnalyzeTaskPrefix), newMsg: func() proto.Message { return &indexpb.AnalyzeTask{} }},
+	{prefix: path.Join(DCPrefix, StatsTaskPrefix), newMsg: func() proto.Message { return &indexpb.StatsTask{} }},
+	{prefix: ImportJobPrefix, newMsg: func() proto.Message { return &datapb.ImportJob{} }},
+	{prefix: PreImportTaskPrefix, newMsg: func() proto.Message { return &datapb.PreImportTask{} }},
+	{prefix: ImportTaskPrefix, newMsg: func() proto.Message { return &datapb.ImportTaskV2{} }},
+	{prefix: ChannelWatchPrefix, newMsg: func() proto.Message { return &datapb.ChannelWatchInfo{} }},
+	{prefix: IndexPrefix, newMsg: func() proto.Message { return &indexpb.FieldIndex{} }},
+	{prefix: SegmentIndexPrefix, newMsg: func() proto.Message { return &indexpb.SegmentIndex{} }},
+	{prefix: ReplicaPrefix, newMsg: func() proto.Message { return &querypb.Replica{} }},
+	{prefix: strings.TrimSuffix(ResourceGroupPrefix, "/"), newMsg: func() proto.Message { return &querypb.ResourceGroup{} }},
+	{prefix: CollectionLoadPrefixV2, newMsg: func() proto.Message { return &querypb.CollectionLoadInfo{} }},
+	{prefix: PartitionLoadedPrefix, newMsg: func() proto.Message { return &querypb.PartitionLoadInfo{} }},
+}
+
+// NewMetaMessage returns empty proto message registered for meta key, key shall be relative to meta base path.
+// Returns false if no proto type registered for key.
+func NewMetaMessage(key string) (proto.Message, bool) {
+	key = strings.TrimPrefix(key, "/")
+	for _, mt := range metaTypes {
+		if strings.HasPrefix(key, mt.prefix+"/") {
+			return mt.newMsg(), true
+		}
+	}
+	return nil, false
+}
+
+// NewMetaMessageByName returns empty proto message of full name, e.g. `milvus.proto.data.SegmentInfo`.
+func NewMetaMessageByName(name string) (proto.Message, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "proto type %s not found", name)
+	}
+	return mt.New().Interface(), nil
+}
@@ -0,0 +1,33 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/birdwatcher/states/kv"
+)
+
+// SavePlan runs fn against a copy-on-write overlay of cli and saves
+// the resulting meta changes into planOut, meta itself is not modified.
+// Reason of each change is provided with kv.WithChangeReason when written.
+func SavePlan(ctx context.Context, cli kv.MetaKV, basePath, command, planOut string, fn func(cli kv.MetaKV) error) error {
+	overlay := kv.NewOverlayKV(cli)
+	defer overlay.Discard()
+
+	if err := fn(overlay); err != nil {
+		return err
+	}
+
+	plan, err := kv.NewPlan(ctx, overlay, basePath, command)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate change plan")
+	}
+	if err := plan.WriteFile(planOut); err != nil {
+		return errors.Wrap(err, "failed to write plan file")
+	}
+	fmt.Println(plan.String())
+	fmt.Printf("Plan saved to %s, use \"apply %s\" to execute it\n", planOut, planOut)
+	return nil
+}
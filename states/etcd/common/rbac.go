@@ -0,0 +1,373 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/kv"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/etcdpb"
+)
+
+const (
+	rolePrefix           = `root-coord/credential/roles`
+	userRolePrefix       = `root-coord/credential/user-role-mapping`
+	granteePrefix        = `root-coord/credential/grantee-privileges`
+	granteeIDPrefix      = `root-coord/credential/grantee-id`
+	privilegeGroupPrefix = `root-coord/credential/privilege-groups`
+
+	defaultDBName = "default"
+	anyWord       = "*"
+)
+
+// builtinRoles are the roles milvus always considers valid.
+var builtinRoles = []string{"admin", "public"}
+
+// splitTenantKey splits rbac meta key into tenant and fixed number of trailing parts.
+// returns false if key does not have enough parts.
+func splitTenantKey(prefix, key string, n int) (string, []string, bool) {
+	rest := strings.TrimPrefix(key, prefix+"/")
+	parts := strings.Split(rest, "/")
+	if len(parts) < n {
+		return "", nil, false
+	}
+	tenant := strings.Join(parts[:len(parts)-n], "/")
+	return tenant, parts[len(parts)-n:], true
+}
+
+func tenantPath(prefix, tenant string, parts ...string) string {
+	if tenant != "" {
+		prefix = path.Join(prefix, tenant)
+	}
+	return path.Join(append([]string{prefix}, parts...)...)
+}
+
+// ListRoles returns role meta info from rootcoord credential meta.
+func ListRoles(ctx context.Context, cli kv.MetaKV, basePath string, filters ...func(*models.Role) bool) ([]*models.Role, error) {
+	prefix := path.Join(basePath, rolePrefix)
+	keys, _, err := cli.LoadWithPrefix(ctx, prefix+"/", kv.WithKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+	return lo.FilterMap(keys, func(key string, _ int) (*models.Role, bool) {
+		tenant, parts, ok := splitTenantKey(prefix, key, 1)
+		if !ok {
+			return nil, false
+		}
+		role := models.NewRole(parts[0], tenant, key)
+		for _, filter := range filters {
+			if !filter(role) {
+				return nil, false
+			}
+		}
+		return role, true
+	}), nil
+}
+
+// ListUserRoles returns user-role binding info from rootcoord credential meta.
+func ListUserRoles(ctx context.Context, cli kv.MetaKV, basePath string, filters ...func(*models.UserRole) bool) ([]*models.UserRole, error) {
+	prefix := path.Join(basePath, userRolePrefix)
+	keys, _, err := cli.LoadWithPrefix(ctx, prefix+"/", kv.WithKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+	return lo.FilterMap(keys, func(key string, _ int) (*models.UserRole, bool) {
+		tenant, parts, ok := splitTenantKey(prefix, key, 2)
+		if !ok {
+			return nil, false
+		}
+		ur := models.NewUserRole(parts[0], parts[1], tenant, key)
+		for _, filter := range filters {
+			if !filter(ur) {
+				return nil, false
+			}
+		}
+		return ur, true
+	}), nil
+}
+
+// ListGrants returns grant entries with privileges attached from grantee-id meta.
+func ListGrants(ctx context.Context, cli kv.MetaKV, basePath string, filters ...func(*models.Grant) bool) ([]*models.Grant, error) {
+	prefix := path.Join(basePath, granteePrefix)
+	keys, values, err := cli.LoadWithPrefix(ctx, prefix+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	privileges, err := ListGrantPrivileges(ctx, cli, basePath)
+	if err != nil {
+		return nil, err
+	}
+	privGroups := lo.GroupBy(privileges, func(p *models.GrantPrivilege) string {
+		return path.Join(p.Tenant, p.GranteeID)
+	})
+
+	return lo.FilterMap(keys, func(key string, idx int) (*models.Grant, bool) {
+		tenant, parts, ok := splitTenantKey(prefix, key, 3)
+		if !ok {
+			return nil, false
+		}
+		dbName, objectName := splitObjectName(parts[2])
+		grant := models.NewGrant(parts[0], parts[1], dbName, objectName, tenant, values[idx], key)
+		grant.Privileges = privGroups[path.Join(tenant, grant.GranteeID)]
+		for _, filter := range filters {
+			if !filter(grant) {
+				return nil, false
+			}
+		}
+		return grant, true
+	}), nil
+}
+
+// ListGrantPrivileges returns all grantee-id privilege entries.
+func ListGrantPrivileges(ctx context.Context, cli kv.MetaKV, basePath string) ([]*models.GrantPrivilege, error) {
+	prefix := path.Join(basePath, granteeIDPrefix)
+	keys, values, err := cli.LoadWithPrefix(ctx, prefix+"/")
+	if err != nil {
+		return nil, err
+	}
+	return lo.FilterMap(keys, func(key string, idx int) (*models.GrantPrivilege, bool) {
+		tenant, parts, ok := splitTenantKey(prefix, key, 2)
+		if !ok {
+			return nil, false
+		}
+		return models.NewGrantPrivilege(parts[0], parts[1], values[idx], tenant, key), true
+	}), nil
+}
+
+// ListPrivilegeGroups returns custom privilege groups.
+func ListPrivilegeGroups(ctx context.Context, cli kv.MetaKV, basePath string, filters ...func(*models.PrivilegeGroup) bool) ([]*models.PrivilegeGroup, error) {
+	prefix := path.Join(basePath, privilegeGroupPrefix) + "/"
+	return ListObj2Models(ctx, cli, prefix, models.NewPrivilegeGroup, filters...)
+}
+
+// splitObjectName splits `db.object` grant object name, legacy grant without db belongs to default database.
+func splitObjectName(name string) (string, string) {
+	dbName, objectName, ok := strings.Cut(name, ".")
+	if !ok {
+		return defaultDBName, name
+	}
+	return dbName, objectName
+}
+
+// CheckRBAC checks rbac meta consistency against role, user, database and collection meta.
+// Custom privilege groups not granted to any role are reported as warnings.
+func CheckRBAC(ctx context.Context, cli kv.MetaKV, basePath string) ([]*models.RBACIssue, error) {
+	users, err := ListUsers(ctx, cli, basePath)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := ListRoles(ctx, cli, basePath)
+	if err != nil {
+		return nil, err
+	}
+	bindings, err := ListUserRoles(ctx, cli, basePath)
+	if err != nil {
+		return nil, err
+	}
+	grants, err := ListGrants(ctx, cli, basePath)
+	if err != nil {
+		return nil, err
+	}
+	privileges, err := ListGrantPrivileges(ctx, cli, basePath)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := ListPrivilegeGroups(ctx, cli, basePath)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := listRBACObjects(ctx, cli, basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	userSet := lo.SliceToMap(users, func(u *models.UserInfo) (string, struct{}) {
+		return path.Join(u.Tenant, u.Username), struct{}{}
+	})
+	roleSet := lo.SliceToMap(roles, func(r *models.Role) (string, struct{}) {
+		return path.Join(r.Tenant, r.Name), struct{}{}
+	})
+	roleExist := func(tenant, name string) bool {
+		_, ok := roleSet[path.Join(tenant, name)]
+		return ok || lo.Contains(builtinRoles, name)
+	}
+	groupSet := lo.SliceToMap(groups, func(g *models.PrivilegeGroup) (string, struct{}) {
+		return g.GetProto().GetGroupName(), struct{}{}
+	})
+
+	var issues []*models.RBACIssue
+
+	for _, b := range bindings {
+		if !roleExist(b.Tenant, b.RoleName) {
+			issues = append(issues, &models.RBACIssue{
+				Type:        models.RBACIssueBindingRoleMissing,
+				Description: fmt.Sprintf("user %s bound to missing role %s", b.Username, b.RoleName),
+				Keys:        []string{b.Key()},
+			})
+			continue
+		}
+		if _, ok := userSet[path.Join(b.Tenant, b.Username)]; !ok {
+			issues = append(issues, &models.RBACIssue{
+				Type:        models.RBACIssueBindingUserMissing,
+				Description: fmt.Sprintf("missing user %s bound to role %s", b.Username, b.RoleName),
+				Keys:        []string{b.Key()},
+			})
+		}
+	}
+
+	usedGrantee := make(map[string]struct{})
+	usedGroup := make(map[string]struct{})
+	for _, g := range grants {
+		granteePath := tenantPath(path.Join(basePath, granteeIDPrefix), g.Tenant, g.GranteeID)
+		usedGrantee[path.Join(g.Tenant, g.GranteeID)] = struct{}{}
+		newIssue := func(tp models.RBACIssueType, desc string) *models.RBACIssue {
+			return &models.RBACIssue{
+				Type:        tp,
+				Description: desc,
+				Keys:        []string{g.Key()},
+				Prefixes:    []string{granteePath + "/"},
+			}
+		}
+		if !roleExist(g.Tenant, g.RoleName) {
+			issues = append(issues, newIssue(models.RBACIssueGrantRoleMissing,
+				fmt.Sprintf("grant %s %s.%s references missing role %s", g.Object, g.DBName, g.ObjectName, g.RoleName)))
+			continue
+		}
+		if g.DBName != anyWord && !objects.hasDB(g.DBName) {
+			issues = append(issues, newIssue(models.RBACIssueGrantDatabaseMissing,
+				fmt.Sprintf("role %s grant %s %s.%s references dropped database %s", g.RoleName, g.Object, g.DBName, g.ObjectName, g.DBName)))
+			continue
+		}
+		if g.Object == commonpb.ObjectType_Collection.String() && g.DBName != anyWord && g.ObjectName != anyWord &&
+			!objects.hasCollection(g.DBName, g.ObjectName) {
+			issues = append(issues, newIssue(models.RBACIssueGrantCollectionMissing,
+				fmt.Sprintf("role %s grant %s %s.%s references dropped collection", g.RoleName, g.Object, g.DBName, g.ObjectName)))
+			continue
+		}
+		for _, priv := range g.Privileges {
+			usedGroup[priv.Name] = struct{}{}
+			_, builtin := commonpb.ObjectPrivilege_value[priv.Name]
+			_, custom := groupSet[priv.Name]
+			if builtin || custom {
+				continue
+			}
+			issues = append(issues, &models.RBACIssue{
+				Type:        models.RBACIssueGrantGroupMissing,
+				Description: fmt.Sprintf("role %s grant %s %s.%s references missing privilege group %s", g.RoleName, g.Object, g.DBName, g.ObjectName, priv.Name),
+				Keys:        []string{priv.Key()},
+			})
+		}
+	}
+
+	orphans := lo.GroupBy(lo.Filter(privileges, func(p *models.GrantPrivilege, _ int) bool {
+		_, ok := usedGrantee[path.Join(p.Tenant, p.GranteeID)]
+		return !ok
+	}), func(p *models.GrantPrivilege) string {
+		return path.Join(p.Tenant, p.GranteeID)
+	})
+	for _, privs := range orphans {
+		p := privs[0]
+		issues = append(issues, &models.RBACIssue{
+			Type: models.RBACIssueGranteeIDOrphan,
+			Description: fmt.Sprintf("grantee id %s not referenced by any grant, privileges: %v", p.GranteeID,
+				lo.Map(privs, func(p *models.GrantPrivilege, _ int) string { return p.Name })),
+			Prefixes: []string{tenantPath(path.Join(basePath, granteeIDPrefix), p.Tenant, p.GranteeID) + "/"},
+		})
+	}
+
+	for _, group := range groups {
+		if _, ok := usedGroup[group.GetProto().GetGroupName()]; ok {
+			continue
+		}
+		issues = append(issues, &models.RBACIssue{
+			Type:        models.RBACIssuePrivilegeGroupOrphan,
+			Warn:        true,
+			Description: fmt.Sprintf("privilege group %s not granted to any role", group.GetProto().GetGroupName()),
+			Keys:        []string{group.Key()},
+		})
+	}
+
+	return issues, nil
+}
+
+// rbacObjects holds valid grant targets, keyed by database name.
+type rbacObjects struct {
+	dbs map[string]map[string]struct{}
+}
+
+func (o *rbacObjects) hasDB(dbName string) bool {
+	_, ok := o.dbs[dbName]
+	return ok
+}
+
+func (o *rbacObjects) hasCollection(dbName, name string) bool {
+	_, ok := o.dbs[dbName][name]
+	return ok
+}
+
+func listRBACObjects(ctx context.Context, cli kv.MetaKV, basePath string) (*rbacObjects, error) {
+	dbs, err := ListDatabase(ctx, cli, basePath, func(db *models.Database) bool {
+		return db.GetProto().GetState() != etcdpb.DatabaseState_DatabaseDropped
+	})
+	if err != nil {
+		return nil, err
+	}
+	objects := &rbacObjects{dbs: map[string]map[string]struct{}{defaultDBName: {}}}
+	dbNames := make(map[int64]string)
+	for _, db := range dbs {
+		dbNames[db.GetProto().GetId()] = db.GetProto().GetName()
+		objects.dbs[db.GetProto().GetName()] = make(map[string]struct{})
+	}
+	dbName := func(dbID int64) string {
+		name, ok := dbNames[dbID]
+		if !ok {
+			// collection before database feature or default database without db-info
+			return defaultDBName
+		}
+		return name
+	}
+
+	collections, err := ListCollections(ctx, cli, basePath, func(c *models.Collection) bool {
+		state := c.GetProto().GetState()
+		return state != etcdpb.CollectionState_CollectionDropped && state != etcdpb.CollectionState_CollectionDropping
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, coll := range collections {
+		name := dbName(coll.GetProto().GetDbId())
+		if _, ok := objects.dbs[name]; !ok {
+			continue
+		}
+		objects.dbs[name][coll.GetProto().GetSchema().GetName()] = struct{}{}
+	}
+
+	aliases, err := ListAlias(ctx, cli, basePath, "", func(a *models.Alias) bool {
+		return a.State == models.AliasStateAliasCreated
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, alias := range aliases {
+		name := dbName(alias.DBID)
+		if _, ok := objects.dbs[name]; !ok {
+			continue
+		}
+		objects.dbs[name][alias.Name] = struct{}{}
+	}
+	return objects, nil
+}
+
+// PrivilegeGroupNames returns privilege names of provided group.
+func PrivilegeGroupNames(info *milvuspb.PrivilegeGroupInfo) []string {
+	return lo.Map(info.GetPrivileges(), func(p *milvuspb.PrivilegeEntity, _ int) string {
+		return p.GetName()
+	})
+}
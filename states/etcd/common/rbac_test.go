@@ -0,0 +1,68 @@
+package common_test
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+)
+
+func TestCheckRBAC(t *testing.T) {
+	c := fakecluster.New(t)
+	c.AddCollection(fakecluster.NewCollection(100, "coll"))
+	for _, user := range []string{"alice", "bob"} {
+		c.Put(path.Join("root-coord/credential/users", user), `{}`)
+	}
+	for _, role := range []string{"reader", "writer"} {
+		c.Put(path.Join("root-coord/credential/roles", role), "")
+	}
+	for _, binding := range []string{"alice/reader", "carol/reader", "bob/ghost", "bob/admin"} {
+		c.Put(path.Join("root-coord/credential/user-role-mapping", binding), "")
+	}
+	grants := map[string]string{
+		"reader/Collection/default.coll": "g1",
+		"reader/Collection/default.gone": "g2",
+		"reader/Collection/db2.coll":     "g3",
+		"ghost/Global/*.*":               "g4",
+		"writer/Collection/*.*":          "g6",
+	}
+	for key, granteeID := range grants {
+		c.Put(path.Join("root-coord/credential/grantee-privileges", key), granteeID)
+	}
+	for _, privilege := range []string{"g1/PrivilegeQuery", "g1/custom", "g2/PrivilegeQuery", "g5/PrivilegeInsert", "g6/unknown"} {
+		c.Put(path.Join("root-coord/credential/grantee-id", privilege), "root")
+	}
+	// unused custom privilege group is reported as warning
+	c.PutProto("root-coord/credential/privilege-groups/custom", &milvuspb.PrivilegeGroupInfo{GroupName: "custom"})
+	c.PutProto("root-coord/credential/privilege-groups/unused", &milvuspb.PrivilegeGroupInfo{GroupName: "unused"})
+
+	issues, err := common.CheckRBAC(context.Background(), c.KV(), c.BasePath())
+	require.NoError(t, err)
+
+	keyOf := func(key string) string { return path.Join(c.BasePath(), "root-coord/credential", key) }
+	result := lo.SliceToMap(issues, func(issue *models.RBACIssue) (models.RBACIssueType, []string) {
+		return issue.Type, append(issue.Keys, issue.Prefixes...)
+	})
+	assert.Len(t, issues, 8)
+	assert.Equal(t, map[models.RBACIssueType][]string{
+		models.RBACIssueBindingUserMissing:     {keyOf("user-role-mapping/carol/reader")},
+		models.RBACIssueBindingRoleMissing:     {keyOf("user-role-mapping/bob/ghost")},
+		models.RBACIssueGrantCollectionMissing: {keyOf("grantee-privileges/reader/Collection/default.gone"), keyOf("grantee-id/g2") + "/"},
+		models.RBACIssueGrantDatabaseMissing:   {keyOf("grantee-privileges/reader/Collection/db2.coll"), keyOf("grantee-id/g3") + "/"},
+		models.RBACIssueGrantRoleMissing:       {keyOf("grantee-privileges/ghost/Global/*.*"), keyOf("grantee-id/g4") + "/"},
+		models.RBACIssueGranteeIDOrphan:        {keyOf("grantee-id/g5") + "/"},
+		models.RBACIssueGrantGroupMissing:      {keyOf("grantee-id/g6/unknown")},
+		models.RBACIssuePrivilegeGroupOrphan:   {keyOf("privilege-groups/unused")},
+	}, result)
+	assert.Equal(t, []models.RBACIssueType{models.RBACIssuePrivilegeGroupOrphan}, lo.FilterMap(issues, func(issue *models.RBACIssue, _ int) (models.RBACIssueType, bool) {
+		return issue.Type, issue.Warn
+	}))
+}
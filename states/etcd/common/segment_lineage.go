@@ -0,0 +1,154 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/kv"
+)
+
+// BuildSegmentLineage walks ancestors and descendants of provided segment
+// with segment `CompactionFrom` and compaction task plans.
+func BuildSegmentLineage(ctx context.Context, cli kv.MetaKV, basePath string, segmentID int64) (*models.SegmentLineage, error) {
+	targets, err := ListSegments(ctx, cli, basePath, func(s *models.Segment) bool {
+		return s.GetID() == segmentID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// segment may be already removed from meta, try compaction tasks to locate collection
+	var collectionID int64
+	if len(targets) > 0 {
+		collectionID = targets[0].GetCollectionID()
+	}
+	tasks, err := ListCompactionTask(ctx, cli, basePath, func(task *models.CompactionTask) bool {
+		return collectionID == 0 || task.GetCollectionID() == collectionID
+	})
+	if err != nil {
+		return nil, err
+	}
+	if collectionID == 0 {
+		for _, task := range tasks {
+			if containsID(task.GetInputSegments(), segmentID) || containsID(task.GetResultSegments(), segmentID) {
+				collectionID = task.GetCollectionID()
+				break
+			}
+		}
+	}
+	if collectionID == 0 {
+		return nil, fmt.Errorf("segment %d not found in segment meta nor compaction tasks", segmentID)
+	}
+
+	segments, err := ListSegments(ctx, cli, basePath, func(s *models.Segment) bool {
+		return s.GetCollectionID() == collectionID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	all := make(map[int64]*models.SegmentLineageNode)
+	for _, s := range segments {
+		all[s.GetID()] = &models.SegmentLineageNode{
+			SegmentID:    s.GetID(),
+			CollectionID: s.GetCollectionID(),
+			PartitionID:  s.GetPartitionID(),
+			Level:        s.GetLevel().String(),
+			State:        s.GetState().String(),
+			NumOfRows:    s.GetNumOfRows(),
+			DmlTs:        s.GetDmlPosition().GetTimestamp(),
+			DroppedAt:    s.GetDroppedAt(),
+		}
+	}
+
+	// edges keyed by from-to to dedup relations found in both segment & task meta
+	type edgeKey struct{ from, to int64 }
+	edges := make(map[edgeKey]*models.SegmentLineageEdge)
+	for _, s := range segments {
+		for _, from := range s.GetCompactionFrom() {
+			edges[edgeKey{from, s.GetID()}] = &models.SegmentLineageEdge{From: from, To: s.GetID()}
+		}
+	}
+	for _, task := range tasks {
+		if task.GetCollectionID() != collectionID {
+			continue
+		}
+		for _, from := range task.GetInputSegments() {
+			for _, to := range task.GetResultSegments() {
+				edge, ok := edges[edgeKey{from, to}]
+				if !ok {
+					edge = &models.SegmentLineageEdge{From: from, To: to}
+					edges[edgeKey{from, to}] = edge
+				}
+				edge.PlanID = task.GetPlanID()
+				edge.CompactionType = task.GetType().String()
+			}
+		}
+	}
+
+	parents := make(map[int64][]*models.SegmentLineageEdge)
+	children := make(map[int64][]*models.SegmentLineageEdge)
+	for _, edge := range edges {
+		parents[edge.To] = append(parents[edge.To], edge)
+		children[edge.From] = append(children[edge.From], edge)
+	}
+
+	lineage := &models.SegmentLineage{
+		Root:  segmentID,
+		Nodes: make(map[int64]*models.SegmentLineageNode),
+	}
+	addNode := func(id int64) {
+		if _, ok := lineage.Nodes[id]; ok {
+			return
+		}
+		node, ok := all[id]
+		if !ok {
+			node = &models.SegmentLineageNode{SegmentID: id, CollectionID: collectionID, Missing: true}
+		}
+		lineage.Nodes[id] = node
+	}
+	added := make(map[*models.SegmentLineageEdge]struct{})
+	walk := func(next map[int64][]*models.SegmentLineageEdge, pick func(*models.SegmentLineageEdge) int64) {
+		visited := map[int64]struct{}{segmentID: {}}
+		queue := []int64{segmentID}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			for _, edge := range next[id] {
+				if _, ok := added[edge]; !ok {
+					added[edge] = struct{}{}
+					lineage.Edges = append(lineage.Edges, edge)
+				}
+				target := pick(edge)
+				if _, ok := visited[target]; ok {
+					continue
+				}
+				visited[target] = struct{}{}
+				addNode(target)
+				queue = append(queue, target)
+			}
+		}
+	}
+
+	addNode(segmentID)
+	walk(parents, func(e *models.SegmentLineageEdge) int64 { return e.From })
+	walk(children, func(e *models.SegmentLineageEdge) int64 { return e.To })
+	sort.Slice(lineage.Edges, func(i, j int) bool {
+		if lineage.Edges[i].From != lineage.Edges[j].From {
+			return lineage.Edges[i].From < lineage.Edges[j].From
+		}
+		return lineage.Edges[i].To < lineage.Edges[j].To
+	})
+	return lineage, nil
+}
+
+func containsID(ids []int64, id int64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package common
+
+import (
+	"context"
+
+	"github.com/milvus-io/birdwatcher/states/kv"
+)
+
+// SnapshotKey is one rootcoord snapshot key, key layout `{basePath}/snapshots/{origin key}_ts{ts}`.
+type SnapshotKey struct {
+	Key string
+	// Origin is the meta key snapshot taken for, without basePath.
+	Origin    string
+	Ts        uint64
+	Tombstone bool
+}
+
+// ListSnapshotKeys returns snapshot keys under provided origin key prefix grouped by origin key, each group sorted by ts.
+func ListSnapshotKeys(ctx context.Context, cli kv.MetaKV, basePath string, originPrefix string, match func(origin string) bool) (map[string][]*SnapshotKey, error) {
+	entries, err := listSnapshotEntries(ctx, cli, basePath, originPrefix, match)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]*SnapshotKey)
+	for _, entry := range entries {
+		result[entry.origin] = append(result[entry.origin], &SnapshotKey{
+			Key:       entry.key,
+			Origin:    entry.origin,
+			Ts:        entry.ts,
+			Tombstone: entry.tombstone,
+		})
+	}
+	return result, nil
+}
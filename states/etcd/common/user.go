@@ -24,6 +24,15 @@ func ListUsers(ctx context.Context, cli kv.MetaKV, basePath string) ([]*models.U
 	}
 
 	return lo.Map(infos, func(info *internalpb.CredentialInfo, idx int) *models.UserInfo {
+		// username & tenant are encoded in key, credential value may not contain them
+		if tenant, parts, ok := splitTenantKey(prefix, keys[idx], 1); ok {
+			if info.Username == "" {
+				info.Username = parts[0]
+			}
+			if info.Tenant == "" {
+				info.Tenant = tenant
+			}
+		}
 		return models.NewUserInfo(info, keys[idx])
 	}), nil
 }
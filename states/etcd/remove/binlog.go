@@ -87,7 +87,7 @@ func BinlogCommand(cli kv.MetaKV, basePath string) *cobra.Command {
 				return
 			}
 
-			err = backupBinlog(cli, key)
+			err = backupBinlog(kv.WithChangeReason(context.Background(), fmt.Sprintf("backup %s before removal", key)), cli, key)
 			if err != nil {
 				fmt.Println(err.Error())
 				return
@@ -116,7 +116,8 @@ func BinlogCommand(cli kv.MetaKV, basePath string) *cobra.Command {
 					return
 				}
 				fmt.Printf("key:%s will be deleted\n", key)
-				err = removeBinlog(cli, key)
+				ctx := kv.WithChangeReason(context.Background(), fmt.Sprintf("remove all %s of segment %d field %d as requested", logType, segmentID, fieldID))
+				err = removeBinlog(ctx, cli, key)
 				if err != nil {
 					fmt.Println(err.Error())
 					return
@@ -141,7 +142,8 @@ func BinlogCommand(cli kv.MetaKV, basePath string) *cobra.Command {
 					return
 				}
 
-				err = saveFieldBinlog(cli, key, fieldBinlog)
+				ctx := kv.WithChangeReason(context.Background(), fmt.Sprintf("remove %s %d of segment %d field %d as requested", logType, logID, segmentID, fieldID))
+				err = saveFieldBinlog(ctx, cli, key, fieldBinlog)
 				if err != nil {
 					fmt.Println(err.Error())
 					return
@@ -163,8 +165,8 @@ func BinlogCommand(cli kv.MetaKV, basePath string) *cobra.Command {
 	return cmd
 }
 
-func backupBinlog(cli kv.MetaKV, key string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+func backupBinlog(ctx context.Context, cli kv.MetaKV, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
 	defer cancel()
 	val, err := cli.Load(ctx, key)
 	if err != nil {
@@ -204,8 +206,8 @@ func restoreBinlog(cli kv.MetaKV, key string) error {
 	return nil
 }
 
-func removeBinlog(cli kv.MetaKV, key string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+func removeBinlog(ctx context.Context, cli kv.MetaKV, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*3)
 	defer cancel()
 	err := cli.Remove(ctx, key)
 	if err != nil {
@@ -253,8 +255,8 @@ func removeLogFromFieldBinlog(key string, logID int64, fieldBinlog *datapb.Field
 	return fieldBinlog, nil
 }
 
-func saveFieldBinlog(cli kv.MetaKV, key string, fieldBinlog *datapb.FieldBinlog) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+func saveFieldBinlog(ctx context.Context, cli kv.MetaKV, key string, fieldBinlog *datapb.FieldBinlog) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
 	defer cancel()
 	mb, err := proto.Marshal(fieldBinlog)
 	if err != nil {
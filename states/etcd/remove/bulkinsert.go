@@ -9,6 +9,7 @@ import (
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/states/etcd/common"
 	"github.com/milvus-io/birdwatcher/states/etcd/show"
+	"github.com/milvus-io/birdwatcher/states/kv"
 )
 
 type RemoveImportJobParam struct {
@@ -16,6 +17,7 @@ type RemoveImportJobParam struct {
 
 	JobID int64 `name:"job" default:"" desc:"import job id to remove"`
 	Run   bool  `name:"run" default:"false" desc:"flags indicating whether to remove import job from meta"`
+	framework.PlanParam
 }
 
 func (c *ComponentRemove) RemoveImportJobCommand(ctx context.Context, p *RemoveImportJobParam) error {
@@ -46,7 +48,7 @@ func (c *ComponentRemove) RemoveImportJobCommand(ctx context.Context, p *RemoveI
 	}
 
 	fmt.Printf("Start to delete import job...\n")
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	ctx, cancel := context.WithTimeout(kv.WithChangeReason(ctx, fmt.Sprintf("remove import job %d as requested", targetJob.GetJobID())), time.Second*3)
 	err = c.client.Remove(ctx, targetPath)
 	cancel()
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
 )
 
 type RemoveChannelParam struct {
@@ -14,7 +15,8 @@ type RemoveChannelParam struct {
 
 	Channel string `name:"channel" default:"" desc:"channel name to remove"`
 	Run     bool   `name:"run" default:"false" desc:"flags indicating whether to remove channel from meta, default is false"`
-	Force   bool   `name:"force" default:"false" desc:"force remove channel ignoring collection check"`
+	framework.PlanParam
+	Force bool `name:"force" default:"false" desc:"force remove channel ignoring collection check"`
 }
 
 // RemoveChannelCommand defines `remove channel` command.
@@ -52,11 +54,19 @@ func (c *ComponentRemove) RemoveChannelCommand(ctx context.Context, p *RemoveCha
 	}
 
 	var targets []string
+	reasons := make(map[string]string)
+	reason := func(channel string, valid bool) string {
+		if valid {
+			return fmt.Sprintf("channel %s removed with --force", channel)
+		}
+		return fmt.Sprintf("channel %s not found in any collection", channel)
+	}
 	for _, watchChannel := range watchChannels {
 		_, ok := validChannels[watchChannel.GetProto().GetVchan().GetChannelName()]
 		if !ok || p.Force {
 			fmt.Printf("%s selected as target channel, collection id: %d\n", watchChannel.GetProto().GetVchan().GetChannelName(), watchChannel.GetProto().GetVchan().GetCollectionID())
 			targets = append(targets, watchChannel.Key())
+			reasons[watchChannel.Key()] = reason(watchChannel.GetProto().GetVchan().GetChannelName(), ok)
 		}
 	}
 
@@ -65,6 +75,7 @@ func (c *ComponentRemove) RemoveChannelCommand(ctx context.Context, p *RemoveCha
 		if !ok || p.Force {
 			fmt.Printf("%s selected as target orpah checkpoint\n", cp.GetProto().GetChannelName())
 			targets = append(targets, cp.Key())
+			reasons[cp.Key()] = reason(cp.GetProto().GetChannelName(), ok)
 		}
 	}
 
@@ -73,7 +84,7 @@ func (c *ComponentRemove) RemoveChannelCommand(ctx context.Context, p *RemoveCha
 	}
 	fmt.Printf("Start to delete orphan watch channel info...\n")
 	for _, path := range targets {
-		err := c.client.Remove(ctx, path)
+		err := c.client.Remove(kv.WithChangeReason(ctx, reasons[path]), path)
 		if err != nil {
 			fmt.Printf("failed to remove watch key %s, error: %s\n", path, err.Error())
 			continue
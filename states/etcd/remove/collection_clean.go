@@ -63,7 +63,7 @@ func CollectionCleanCommand(cli kv.MetaKV, basePath string) *cobra.Command {
 					if !collectionExist {
 						fmt.Println("clean meta key ", sKey)
 						if run {
-							return cli.Remove(ctx, sKey)
+							return cli.Remove(kv.WithChangeReason(ctx, "collection of meta key not exists"), sKey)
 						}
 					}
 
@@ -67,6 +67,7 @@ func CollectionDropCommand(cli kv.MetaKV, basePath string) *cobra.Command {
 
 func cleanCollectionDropMeta(cli kv.MetaKV, basePath string, info *models.Collection, run bool) {
 	collection := info.GetProto()
+	ctx := kv.WithChangeReason(context.TODO(), fmt.Sprintf("collection %s[%d] is dropping", collection.GetSchema().GetName(), collection.GetID()))
 	fmt.Println("Clean collection(drop) meta:")
 	if info.Key() == "" {
 		fmt.Printf("Collection %s[%d] key is empty string, cannot perform cleanup", collection.Schema.Name, collection.ID)
@@ -97,14 +98,14 @@ func cleanCollectionDropMeta(cli kv.MetaKV, basePath string, info *models.Collec
 	prefixes = append(prefixes, path.Join(basePath, common.SnapshotPrefix, collectionKey))
 
 	for _, prefix := range prefixes {
-		if err := cli.RemoveWithPrefix(context.TODO(), prefix); err != nil {
+		if err := cli.RemoveWithPrefix(ctx, prefix); err != nil {
 			fmt.Printf("failed to clean prefix: %s, error: %s\n", prefix, err.Error())
 		} else {
 			fmt.Printf("clean prefix: %s\n", prefix)
 		}
 	}
 
-	channelWatchInfos, err := common.ListChannelWatch(context.Background(), cli, basePath, func(cw *models.ChannelWatch) bool {
+	channelWatchInfos, err := common.ListChannelWatch(ctx, cli, basePath, func(cw *models.ChannelWatch) bool {
 		return cw.GetProto().Vchan.CollectionID == collection.ID
 	})
 	if err != nil {
@@ -118,17 +119,17 @@ func cleanCollectionDropMeta(cli kv.MetaKV, basePath string, info *models.Collec
 			return
 		}
 		fmt.Println("channel watch info:", info.Key())
-		cli.Remove(context.TODO(), info.Key())
+		cli.Remove(ctx, info.Key())
 	}
 
 	// channel checkpoint and removal
 	for _, channel := range info.Channels() {
 		cpKey := path.Join(basePath, "datacoord-meta/channel-cp", channel.VirtualName)
 		fmt.Println("channel checkpoint:", cpKey)
-		cli.Remove(context.TODO(), cpKey)
+		cli.Remove(ctx, cpKey)
 		removalKey := path.Join(basePath, "datacoord-meta/channel-removal", channel.VirtualName)
 		fmt.Println("channel removal", removalKey)
-		cli.Remove(context.TODO(), removalKey)
+		cli.Remove(ctx, removalKey)
 	}
 
 	// dry run
@@ -140,7 +141,7 @@ func cleanCollectionDropMeta(cli kv.MetaKV, basePath string, info *models.Collec
 	// TODO: yi
 	// remove all keys with transaction
 	/*
-		resp, err := cli.Txn(context.TODO()).If().Then(ops...).Commit()
+		resp, err := cli.Txn(ctx).If().Then(ops...).Commit()
 		if err != nil {
 			fmt.Printf("failed to remove meta for collection %s[%d], err: %s\n", collection.Schema.Name, collection.ID, err.Error())
 			return
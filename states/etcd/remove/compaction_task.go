@@ -8,6 +8,7 @@ import (
 	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
 )
 
 type CompactionTaskParam struct {
@@ -16,9 +17,10 @@ type CompactionTaskParam struct {
 	JobID               string `name:"jobID" default:"" desc:"jobID also known as triggerID"`
 	TaskID              string `name:"taskID" default:"" desc:"taskID also known as planID"`
 	State               string `name:"state" default:"" desc:"task state"`
-	CollectionID        int64  `name:"collectionID" default:"0" desc:"collection id to filter"`
+	CollectionID        int64  `name:"collectionID" resolve:"collection" default:"0" desc:"collection id to filter"`
 	PartitionID         int64  `name:"partitionID" default:"0" desc:"partitionID id to filter"`
 	Run                 bool   `name:"run" default:"false" desc:"flag to control actually run or dry"`
+	framework.PlanParam
 }
 
 // RemoveCompactionTaskCommand is the command function to remove compaction task.
@@ -62,6 +64,7 @@ func (c *ComponentRemove) RemoveCompactionTaskCommand(ctx context.Context, p *Co
 
 	for _, task := range compactionTasks {
 		key := path.Join(c.basePath, common.CompactionTaskPrefix, task.GetType().String(), fmt.Sprint(task.GetTriggerID()), fmt.Sprint(task.GetPlanID()))
+		ctx := kv.WithChangeReason(ctx, fmt.Sprintf("%s compaction task %d of job %d in state %s", task.GetType().String(), task.GetPlanID(), task.GetTriggerID(), task.GetState().String()))
 		err = c.client.RemoveWithPrefix(ctx, key)
 		if err != nil {
 			return err
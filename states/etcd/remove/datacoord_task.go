@@ -0,0 +1,160 @@
+package remove
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
+)
+
+type StatsTaskParam struct {
+	framework.ParamBase `use:"remove stats-task" desc:"Remove stuck or failed stats task from datacoord meta"`
+	TaskID              int64  `name:"task" default:"0" desc:"task id to remove"`
+	CollectionID        int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter with"`
+	NodeID              int64  `name:"node" default:"0" desc:"worker node id to filter with"`
+	State               string `name:"state" default:"" desc:"task state to filter with, e.g. JobStateFailed"`
+	Run                 bool   `name:"run" default:"false" desc:"flag to control actually run or dry"`
+	framework.PlanParam
+}
+
+// RemoveStatsTaskCommand is the command function to remove stats task.
+func (c *ComponentRemove) RemoveStatsTaskCommand(ctx context.Context, p *StatsTaskParam) error {
+	if p.TaskID == 0 && p.State == "" {
+		return errors.New("task id or state must be provided")
+	}
+	tasks, err := common.ListStatsTask(ctx, c.client, c.basePath, func(task *models.StatsTask) bool {
+		t := task.GetProto()
+		return (p.TaskID == 0 || t.GetTaskID() == p.TaskID) &&
+			(p.CollectionID == 0 || t.GetCollectionID() == p.CollectionID) &&
+			(p.NodeID == 0 || t.GetNodeID() == p.NodeID) &&
+			(p.State == "" || strings.EqualFold(t.GetState().String(), p.State))
+	})
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		fmt.Println("no stats task found")
+		return nil
+	}
+
+	for _, task := range tasks {
+		t := task.GetProto()
+		fmt.Printf("target stats task, TaskID %d, Type %s, State %s, SegmentID %d, NodeID %d\n", t.GetTaskID(), t.GetSubJobType().String(), t.GetState().String(), t.GetSegmentID(), t.GetNodeID())
+	}
+	if !p.Run {
+		return nil
+	}
+	return removeTaskKeys(ctx, c, "stats task", tasks, func(task *models.StatsTask) string {
+		return fmt.Sprintf("stats task %d in state %s", task.GetProto().GetTaskID(), task.GetProto().GetState().String())
+	})
+}
+
+type AnalyzeTaskParam struct {
+	framework.ParamBase `use:"remove analyze-task" desc:"Remove stuck or failed analyze task from datacoord meta"`
+	TaskID              int64  `name:"task" default:"0" desc:"task id to remove"`
+	CollectionID        int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter with"`
+	NodeID              int64  `name:"node" default:"0" desc:"worker node id to filter with"`
+	State               string `name:"state" default:"" desc:"task state to filter with, e.g. JobStateFailed"`
+	Run                 bool   `name:"run" default:"false" desc:"flag to control actually run or dry"`
+	framework.PlanParam
+}
+
+// RemoveAnalyzeTaskCommand is the command function to remove analyze task.
+func (c *ComponentRemove) RemoveAnalyzeTaskCommand(ctx context.Context, p *AnalyzeTaskParam) error {
+	if p.TaskID == 0 && p.State == "" {
+		return errors.New("task id or state must be provided")
+	}
+	tasks, err := common.ListAnalyzeTask(ctx, c.client, c.basePath, func(task *models.AnalyzeTask) bool {
+		t := task.GetProto()
+		return (p.TaskID == 0 || t.GetTaskID() == p.TaskID) &&
+			(p.CollectionID == 0 || t.GetCollectionID() == p.CollectionID) &&
+			(p.NodeID == 0 || t.GetNodeID() == p.NodeID) &&
+			(p.State == "" || strings.EqualFold(t.GetState().String(), p.State))
+	})
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		fmt.Println("no analyze task found")
+		return nil
+	}
+
+	for _, task := range tasks {
+		t := task.GetProto()
+		fmt.Printf("target analyze task, TaskID %d, State %s, CollectionID %d, PartitionID %d, NodeID %d\n", t.GetTaskID(), t.GetState().String(), t.GetCollectionID(), t.GetPartitionID(), t.GetNodeID())
+	}
+	if !p.Run {
+		return nil
+	}
+	return removeTaskKeys(ctx, c, "analyze task", tasks, func(task *models.AnalyzeTask) string {
+		return fmt.Sprintf("analyze task %d in state %s", task.GetProto().GetTaskID(), task.GetProto().GetState().String())
+	})
+}
+
+type PartitionStatsParam struct {
+	framework.ParamBase `use:"remove partition-stats" desc:"Remove stale partition stats info from datacoord meta"`
+	CollectionID        int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter with"`
+	PartitionID         int64  `name:"partition" default:"0" desc:"partition id to filter with"`
+	Channel             string `name:"channel" default:"" desc:"vchannel name to filter with"`
+	Version             int64  `name:"version" default:"0" desc:"partition stats version to remove"`
+	IncludeCurrent      bool   `name:"includeCurrent" default:"false" desc:"also remove partition stats of current version"`
+	Run                 bool   `name:"run" default:"false" desc:"flag to control actually run or dry"`
+	framework.PlanParam
+}
+
+// RemovePartitionStatsCommand is the command function to remove partition stats info.
+func (c *ComponentRemove) RemovePartitionStatsCommand(ctx context.Context, p *PartitionStatsParam) error {
+	if p.CollectionID == 0 {
+		return errors.New("collection id must be provided")
+	}
+	infos, err := common.ListPartitionStats(ctx, c.client, c.basePath, func(info *models.PartitionStats) bool {
+		ps := info.GetProto()
+		if ps.GetCollectionID() != p.CollectionID ||
+			(p.PartitionID != 0 && ps.GetPartitionID() != p.PartitionID) ||
+			(p.Channel != "" && ps.GetVChannel() != p.Channel) ||
+			(p.Version != 0 && ps.GetVersion() != p.Version) {
+			return false
+		}
+		if !p.IncludeCurrent && common.GetCurrentPartitionStatsVersion(ctx, c.client, c.basePath, info) == fmt.Sprint(ps.GetVersion()) {
+			fmt.Printf("skip current partition stats, PartitionID %d, Channel %s, Version %d\n", ps.GetPartitionID(), ps.GetVChannel(), ps.GetVersion())
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if len(infos) == 0 {
+		fmt.Println("no partition stats found")
+		return nil
+	}
+
+	for _, info := range infos {
+		ps := info.GetProto()
+		fmt.Printf("target partition stats, PartitionID %d, Channel %s, Version %d, AnalyzeTaskID %d\n", ps.GetPartitionID(), ps.GetVChannel(), ps.GetVersion(), ps.GetAnalyzeTaskID())
+	}
+	if !p.Run {
+		return nil
+	}
+	return removeTaskKeys(ctx, c, "partition stats", infos, func(info *models.PartitionStats) string {
+		ps := info.GetProto()
+		return fmt.Sprintf("stale partition stats of partition %d, channel %s, version %d", ps.GetPartitionID(), ps.GetVChannel(), ps.GetVersion())
+	})
+}
+
+func removeTaskKeys[T interface{ Key() string }](ctx context.Context, c *ComponentRemove, name string, items []T, reason func(T) string) error {
+	for _, item := range items {
+		if err := c.client.Remove(kv.WithChangeReason(ctx, reason(item)), item.Key()); err != nil {
+			fmt.Printf("failed to remove %s, key: %s, err: %s\n", name, item.Key(), err.Error())
+			return err
+		}
+		fmt.Printf("remove %s done, key: %s\n", name, item.Key())
+	}
+	return nil
+}
@@ -9,14 +9,16 @@ import (
 	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 )
 
 type DirtyImportingSegment struct {
 	framework.ParamBase `use:"remove dirty-importing-segment" desc:"remove dirty importing segments with 0 rows"`
-	CollectionID        int64 `name:"collection" default:"0" desc:"collection id to filter with"`
+	CollectionID        int64 `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter with"`
 	Ts                  int64 `name:"ts" default:"0" desc:"only remove segments with ts less than this value"`
 	Run                 bool  `name:"run" default:"false" desc:"flag to control actually run or dry"`
+	framework.PlanParam
 }
 
 // DirtyImportingSegmentCommand returns command to remove
@@ -44,6 +46,7 @@ func (c *ComponentRemove) DirtyImportingSegmentCommand(ctx context.Context, p *D
 				if segment.NumOfRows == 0 && segmentTs < uint64(p.Ts) {
 					cnt++
 					if p.Run {
+						ctx := kv.WithChangeReason(ctx, fmt.Sprintf("importing segment %d with 0 rows, ts %d before %d", segment.ID, segmentTs, p.Ts))
 						err := common.RemoveSegmentByID(ctx, c.client, c.basePath, segment.CollectionID, segment.PartitionID, segment.ID)
 						if err != nil {
 							fmt.Printf("failed to remove segment %d, err: %s\n", segment.ID, err.Error())
@@ -7,12 +7,14 @@ import (
 	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
 )
 
 type RemoveIndexParam struct {
 	framework.ParamBase `use:"remove index" desc:"Remove index meta"`
 	IndexID             int64 `name:"indexID" default:"0" desc:"index id to remove"`
 	Run                 bool  `name:"run" default:"false" desc:"flag to control actually run or dry"`
+	framework.PlanParam
 }
 
 func (c *ComponentRemove) RemoveIndexCommand(ctx context.Context, p *RemoveIndexParam) error {
@@ -38,7 +40,7 @@ func (c *ComponentRemove) RemoveIndexCommand(ctx context.Context, p *RemoveIndex
 	}
 
 	for _, index := range indexes {
-		err := c.client.Remove(ctx, index.Key())
+		err := c.client.Remove(kv.WithChangeReason(ctx, fmt.Sprintf("remove index %d as requested", p.IndexID)), index.Key())
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,81 @@
+package remove
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
+)
+
+type RBACOrphanParam struct {
+	framework.ParamBase `use:"remove rbac-orphan" desc:"remove rbac meta entries reported by check rbac"`
+	Types               []string `name:"type" default:"" desc:"issue types to clean, all non-warning types if not provided"`
+	Run                 bool     `name:"run" default:"false" desc:"flag to control actually run or dry"`
+	framework.PlanParam
+}
+
+// RBACOrphanCommand implements `remove rbac-orphan` command.
+func (c *ComponentRemove) RBACOrphanCommand(ctx context.Context, p *RBACOrphanParam) error {
+	issues, err := common.CheckRBAC(ctx, c.client, c.basePath)
+	if err != nil {
+		return err
+	}
+	issues = lo.Filter(issues, func(issue *models.RBACIssue, _ int) bool {
+		if len(p.Types) == 0 {
+			return !issue.Warn
+		}
+		return lo.Contains(p.Types, string(issue.Type))
+	})
+
+	if len(issues) == 0 {
+		fmt.Println("no rbac orphan found")
+		return nil
+	}
+
+	for _, issue := range issues {
+		fmt.Printf("[%s] %s\n", issue.Type, issue.Description)
+		for _, key := range issue.Keys {
+			fmt.Printf("\tkey: %s\n", key)
+		}
+		for _, prefix := range issue.Prefixes {
+			fmt.Printf("\tprefix: %s\n", prefix)
+		}
+	}
+
+	if !p.Run {
+		fmt.Printf("%d issue(s) found, use --run to remove them\n", len(issues))
+		return nil
+	}
+
+	var failed int
+	for _, issue := range issues {
+		ctx := kv.WithChangeReason(ctx, fmt.Sprintf("[%s] %s", issue.Type, issue.Description))
+		ok := true
+		for _, key := range issue.Keys {
+			if err := c.client.Remove(ctx, key); err != nil {
+				fmt.Printf("failed to remove key %s, err: %s\n", key, err.Error())
+				ok = false
+			}
+		}
+		for _, prefix := range issue.Prefixes {
+			if err := c.client.RemoveWithPrefix(ctx, prefix); err != nil {
+				fmt.Printf("failed to remove prefix %s, err: %s\n", prefix, err.Error())
+				ok = false
+			}
+		}
+		if !ok {
+			failed++
+		}
+	}
+	fmt.Printf("%d rbac issue(s) cleaned, %d failed\n", len(issues)-failed, failed)
+	if failed > 0 {
+		return errors.Newf("failed to clean %d rbac issue(s)", failed)
+	}
+	return nil
+}
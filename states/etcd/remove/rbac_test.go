@@ -0,0 +1,60 @@
+package remove
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+	"github.com/milvus-io/birdwatcher/states/kv"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+)
+
+// failingRemoveKV fails removing keys containing pattern.
+type failingRemoveKV struct {
+	kv.MetaKV
+	pattern string
+}
+
+func (f *failingRemoveKV) Remove(ctx context.Context, key string) error {
+	if strings.Contains(key, f.pattern) {
+		return errors.New("mock remove error")
+	}
+	return f.MetaKV.Remove(ctx, key)
+}
+
+func TestRemoveRBACOrphan(t *testing.T) {
+	ctx := context.Background()
+	c := fakecluster.New(t)
+	c.Put("root-coord/credential/users/alice", `{}`)
+	for _, binding := range []string{"alice/ghost", "alice/phantom"} {
+		c.Put(path.Join("root-coord/credential/user-role-mapping", binding), "")
+	}
+	c.PutProto("root-coord/credential/privilege-groups/unused", &milvuspb.PrivilegeGroupInfo{GroupName: "unused"})
+
+	exists := func(key string) bool {
+		_, err := c.KV().Load(ctx, path.Join(c.BasePath(), "root-coord/credential", key))
+		return err == nil
+	}
+
+	// failed removal is reported and not counted as cleaned
+	rm := NewComponent(&failingRemoveKV{MetaKV: c.KV(), pattern: "phantom"}, nil, c.BasePath())
+	assert.Error(t, rm.RBACOrphanCommand(ctx, &RBACOrphanParam{Run: true}))
+	assert.False(t, exists("user-role-mapping/alice/ghost"))
+	assert.True(t, exists("user-role-mapping/alice/phantom"))
+	// warnings are kept unless selected explicitly
+	assert.True(t, exists("privilege-groups/unused"))
+
+	rm = NewComponent(c.KV(), nil, c.BasePath())
+	require.NoError(t, rm.RBACOrphanCommand(ctx, &RBACOrphanParam{Run: true}))
+	assert.False(t, exists("user-role-mapping/alice/phantom"))
+	assert.True(t, exists("privilege-groups/unused"))
+
+	require.NoError(t, rm.RBACOrphanCommand(ctx, &RBACOrphanParam{Types: []string{"privilege-group-orphan"}, Run: true}))
+	assert.False(t, exists("privilege-groups/unused"))
+}
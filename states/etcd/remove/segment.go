@@ -73,7 +73,8 @@ func SegmentCommand(cli kv.MetaKV, basePath string) *cobra.Command {
 					return err
 				}
 
-				if err = common.RemoveSegment(context.TODO(), cli, basePath, info); err != nil {
+				ctx := kv.WithChangeReason(context.TODO(), fmt.Sprintf("remove %s segment %d as requested", info.GetState().String(), info.GetID()))
+				if err = common.RemoveSegment(ctx, cli, basePath, info); err != nil {
 					fmt.Printf("Remove segment %d from Etcd failed, err: %s\n", info.ID, err.Error())
 					return err
 				}
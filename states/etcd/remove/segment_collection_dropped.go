@@ -57,7 +57,8 @@ func SegmentCollectionDroppedCommand(cli kv.MetaKV, basePath string) *cobra.Comm
 
 			for _, info := range segments {
 				fmt.Printf("[WARNING] about to remove segment %d from etcd\n", info.GetID())
-				err = common.RemoveSegment(context.TODO(), cli, basePath, info.SegmentInfo)
+				ctx := kv.WithChangeReason(context.TODO(), fmt.Sprintf("collection %d of segment %d dropped", collectionID, info.GetID()))
+				err = common.RemoveSegment(ctx, cli, basePath, info.SegmentInfo)
 				if err != nil {
 					fmt.Printf("Remove segment %d from Etcd failed, err: %s\n", info.ID, err.Error())
 					return
@@ -10,12 +10,14 @@ import (
 	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
 )
 
 type SegmentOrphan struct {
 	framework.ParamBase `use:"remove segment-orphan" desc:"remove orphan segments that collection meta already gone"`
-	CollectionID        int64 `name:"collection" default:"0" desc:"collection id to filter with"`
+	CollectionID        int64 `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter with"`
 	Run                 bool  `name:"run" default:"false" desc:"flag to control actually run or dry"`
+	framework.PlanParam
 }
 
 // SegmentOrphanCommand returns command to remove
@@ -41,6 +43,7 @@ func (c *ComponentRemove) SegmentOrphanCommand(ctx context.Context, p *SegmentOr
 
 			if p.Run {
 				for _, segment := range segments {
+					ctx := kv.WithChangeReason(ctx, fmt.Sprintf("collection %d not found, orphan segment %d", collectionID, segment.ID))
 					err := common.RemoveSegmentByID(ctx, c.client, c.basePath, segment.CollectionID, segment.PartitionID, segment.ID)
 					if err != nil {
 						fmt.Printf("failed to remove segment %d, err: %s\n", segment.ID, err.Error())
@@ -10,6 +10,7 @@ import (
 	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
 )
 
 type RemoveSessionParam struct {
@@ -17,6 +18,7 @@ type RemoveSessionParam struct {
 	Component           string `name:"component" default:"" desc:"component type to remove"`
 	ID                  int64  `name:"sessionID" default:"0" desc:"session id to remove"`
 	Run                 bool   `name:"run" default:"false" desc:"actual remove session, default in dry-run mode"`
+	framework.PlanParam
 }
 
 func (c *ComponentRemove) RemoveSessionCommand(ctx context.Context, p *RemoveSessionParam) error {
@@ -42,6 +44,7 @@ func (c *ComponentRemove) RemoveSessionCommand(ctx context.Context, p *RemoveSes
 	if p.Run {
 		fmt.Println("Start to remove session")
 		for _, session := range sessions {
+			ctx := kv.WithChangeReason(ctx, fmt.Sprintf("remove %s session %d as requested", session.ServerName, session.ServerID))
 			err := c.client.Remove(ctx, session.GetKey())
 			if err != nil {
 				return err
@@ -0,0 +1,165 @@
+package remove
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
+	"github.com/milvus-io/birdwatcher/utils"
+)
+
+type SnapshotsParam struct {
+	framework.ParamBase `use:"remove snapshots" desc:"remove expired rootcoord snapshot & tombstone keys with retention policy"`
+	OlderThan           time.Duration `name:"older-than" default:"30d" desc:"only remove snapshots older than provided duration, e.g. 72h, 30d"`
+	KeepLatest          int64         `name:"keep-latest" default:"1" desc:"number of latest snapshots kept for each meta key, at least 1"`
+	CollectionID        int64         `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter snapshots with"`
+	BatchSize           int64         `name:"batch-size" default:"100" desc:"number of keys removed in one batch"`
+	RateLimit           int64         `name:"rate-limit" default:"10" desc:"max removal batches per second, 0 for unlimited"`
+	Run                 bool          `name:"run" default:"false" desc:"flag to control actually run or dry"`
+	framework.PlanParam
+}
+
+// RemoveSnapshotsCommand removes rootcoord snapshot keys out of retention.
+// The latest snapshot of each meta key backs current meta state and is always kept,
+// unless the meta is dropped (latest snapshot is tombstone) before the retention,
+// in which case the origin key holding the tombstone mark is removed as well.
+func (c *ComponentRemove) RemoveSnapshotsCommand(ctx context.Context, p *SnapshotsParam) error {
+	if p.KeepLatest < 1 {
+		return errors.New("keep-latest shall be at least 1, latest snapshot backs current meta")
+	}
+	if p.BatchSize <= 0 {
+		return errors.New("batch-size shall be positive")
+	}
+	cutoff := time.Now().Add(-p.OlderThan)
+
+	match := func(string) bool { return true }
+	if p.CollectionID > 0 {
+		match = collectionSnapshotMatcher(p.CollectionID)
+	}
+	groups, err := common.ListSnapshotKeys(ctx, c.client, c.basePath, "", match)
+	if err != nil {
+		return err
+	}
+
+	origins := lo.Keys(groups)
+	sort.Strings(origins)
+	var targets []snapshotTarget
+	var total int
+	for _, origin := range origins {
+		snapshots := groups[origin]
+		total += len(snapshots)
+
+		dropped, originTombstone := false, false
+		if latest := snapshots[len(snapshots)-1]; latest.Tombstone && snapshotExpired(latest, cutoff) {
+			// make sure meta is actually gone before removing the tombstone,
+			// origin key either removed or holding the tombstone mark
+			value, _, err := c.client.LoadWithRevision(ctx, path.Join(c.basePath, origin))
+			if err != nil && !errors.Is(err, kv.ErrKeyNotFound) {
+				return err
+			}
+			originTombstone = err == nil && bytes.Equal([]byte(value), common.CollectionTombstone)
+			dropped = err != nil || originTombstone
+		}
+
+		expired := expiredSnapshots(snapshots, cutoff, int(p.KeepLatest), dropped)
+		if len(expired) == 0 {
+			continue
+		}
+		fmt.Printf("%s: %d/%d snapshot(s) to remove, dropped: %t\n", origin, len(expired), len(snapshots), dropped)
+		reason := fmt.Sprintf("snapshot of %s older than %s", origin, cutoff.Format(time.RFC3339))
+		if dropped {
+			reason = fmt.Sprintf("%s dropped before %s", origin, cutoff.Format(time.RFC3339))
+		}
+		for _, snapshot := range expired {
+			targets = append(targets, snapshotTarget{key: snapshot.Key, reason: reason})
+		}
+		if originTombstone {
+			targets = append(targets, snapshotTarget{key: path.Join(c.basePath, origin), reason: reason})
+		}
+	}
+	fmt.Printf("--- %d key(s) to remove from %d snapshot key(s) scanned, older than %s\n", len(targets), total, cutoff.Format(time.RFC3339))
+
+	if !p.Run || len(targets) == 0 {
+		return nil
+	}
+
+	var limiter <-chan time.Time
+	if p.RateLimit > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(p.RateLimit))
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+	var removed int
+	for idx, batch := range lo.Chunk(targets, int(p.BatchSize)) {
+		if idx > 0 && limiter != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-limiter:
+			}
+		}
+		groups := lo.GroupBy(batch, func(target snapshotTarget) string { return target.reason })
+		for _, reason := range lo.Uniq(lo.Map(batch, func(target snapshotTarget, _ int) string { return target.reason })) {
+			keys := lo.Map(groups[reason], func(target snapshotTarget, _ int) string { return target.key })
+			if err := c.client.MultiSaveAndRemoveIf(kv.WithChangeReason(ctx, reason), nil, keys); err != nil {
+				return errors.Wrapf(err, "failed to remove snapshots, %d removed", removed)
+			}
+			removed += len(keys)
+		}
+	}
+	fmt.Printf("%d key(s) removed\n", removed)
+	return nil
+}
+
+// snapshotTarget is a snapshot key to remove with the reason.
+type snapshotTarget struct {
+	key    string
+	reason string
+}
+
+// expiredSnapshots returns snapshots out of retention from ts sorted snapshots of one meta key.
+// When meta is dropped, the whole history including the tombstone is expired.
+func expiredSnapshots(snapshots []*common.SnapshotKey, cutoff time.Time, keepLatest int, dropped bool) []*common.SnapshotKey {
+	if dropped {
+		return snapshots
+	}
+	candidates := snapshots[:max(len(snapshots)-keepLatest, 0)]
+	return lo.Filter(candidates, func(snapshot *common.SnapshotKey, _ int) bool {
+		return snapshotExpired(snapshot, cutoff)
+	})
+}
+
+func snapshotExpired(snapshot *common.SnapshotKey, cutoff time.Time) bool {
+	t, _ := utils.ParseTS(snapshot.Ts)
+	return t.Before(cutoff)
+}
+
+// collectionSnapshotMatcher matches snapshots of collection, partition & field meta of provided collection.
+func collectionSnapshotMatcher(collectionID int64) func(string) bool {
+	id := strconv.FormatInt(collectionID, 10)
+	collectionKey := path.Join(common.RCPrefix, common.CollectionPrefix, id)
+	dbCollectionPrefix := path.Join(common.RCPrefix, common.DBPrefix, common.CollectionInfoPrefix) + "/"
+	partitionPrefix := path.Join(common.RCPrefix, common.PartitionPrefix, id) + "/"
+	fieldPrefix := path.Join(common.RCPrefix, common.FieldPrefix, id) + "/"
+	return func(origin string) bool {
+		switch {
+		case origin == collectionKey:
+			return true
+		case strings.HasPrefix(origin, dbCollectionPrefix):
+			return path.Base(origin) == id
+		default:
+			return strings.HasPrefix(origin, partitionPrefix) || strings.HasPrefix(origin, fieldPrefix)
+		}
+	}
+}
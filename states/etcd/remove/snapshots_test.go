@@ -0,0 +1,79 @@
+package remove
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+)
+
+func TestRemoveSnapshots(t *testing.T) {
+	ctx := context.Background()
+	c := fakecluster.New(t)
+
+	ts := func(t time.Time) uint64 { return uint64(t.UnixMilli()) << 18 }
+	old, recent := ts(time.Now().Add(-60*24*time.Hour)), ts(time.Now())
+	snapshot := func(origin string, ts uint64, value string) {
+		c.Put(path.Join(common.SnapshotPrefix, fmt.Sprintf("%s_ts%d", origin, ts)), value)
+	}
+	tombstone := string(common.CollectionTombstone)
+	// live collection, latest snapshot is recent
+	snapshot("root-coord/collection/100", old, "v1")
+	snapshot("root-coord/collection/100", old+1, "v2")
+	snapshot("root-coord/collection/100", recent, "v3")
+	c.Put("root-coord/collection/100", "v3")
+	// latest snapshot expired but backs current meta
+	snapshot("root-coord/partitions/100/101", old, "v1")
+	snapshot("root-coord/partitions/100/101", old+1, "v2")
+	c.Put("root-coord/partitions/100/101", "v2")
+	// dropped collection, origin key holds the tombstone mark as rootcoord writes it
+	snapshot("root-coord/collection/200", old, "v1")
+	snapshot("root-coord/collection/200", old+1, tombstone)
+	c.Put("root-coord/collection/200", tombstone)
+	// dropped collection, origin key already removed
+	snapshot("root-coord/collection/400", old, "v1")
+	snapshot("root-coord/collection/400", old+1, tombstone)
+	// tombstone with meta still exists
+	snapshot("root-coord/collection/300", old, "v1")
+	snapshot("root-coord/collection/300", old+1, tombstone)
+	c.Put("root-coord/collection/300", "v1")
+
+	count := func() int {
+		keys, _, err := c.KV().LoadWithPrefix(ctx, path.Join(c.BasePath(), common.SnapshotPrefix)+"/")
+		require.NoError(t, err)
+		return len(keys)
+	}
+	require.Equal(t, 11, count())
+
+	rm := NewComponent(c.KV(), nil, c.BasePath())
+	// dry run
+	require.NoError(t, rm.RemoveSnapshotsCommand(ctx, &SnapshotsParam{OlderThan: 30 * 24 * time.Hour, KeepLatest: 1, BatchSize: 2}))
+	assert.Equal(t, 11, count())
+
+	require.NoError(t, rm.RemoveSnapshotsCommand(ctx, &SnapshotsParam{OlderThan: 30 * 24 * time.Hour, KeepLatest: 1, CollectionID: 100, BatchSize: 2, Run: true}))
+	assert.Equal(t, 8, count())
+
+	require.NoError(t, rm.RemoveSnapshotsCommand(ctx, &SnapshotsParam{OlderThan: 30 * 24 * time.Hour, KeepLatest: 1, BatchSize: 2, Run: true}))
+	assert.Equal(t, 3, count())
+	groups, err := common.ListSnapshotKeys(ctx, c.KV(), c.BasePath(), "", func(string) bool { return true })
+	require.NoError(t, err)
+	assert.Len(t, groups["root-coord/collection/100"], 1)
+	assert.Len(t, groups["root-coord/partitions/100/101"], 1)
+	assert.NotContains(t, groups, "root-coord/collection/200")
+	assert.NotContains(t, groups, "root-coord/collection/400")
+	_, err = c.KV().Load(ctx, path.Join(c.BasePath(), "root-coord/collection/200"))
+	assert.Error(t, err)
+	_, err = c.KV().Load(ctx, path.Join(c.BasePath(), "root-coord/collection/300"))
+	assert.NoError(t, err)
+	require.Len(t, groups["root-coord/collection/300"], 1)
+	assert.True(t, groups["root-coord/collection/300"][0].Tombstone)
+
+	assert.Error(t, rm.RemoveSnapshotsCommand(ctx, &SnapshotsParam{OlderThan: 30 * 24 * time.Hour, KeepLatest: 0, BatchSize: 2}))
+}
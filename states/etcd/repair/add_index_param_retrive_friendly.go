@@ -9,19 +9,22 @@ import (
 	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus/pkg/v2/proto/indexpb"
 )
 
 type AddIndexParamParam struct {
 	framework.ParamBase `use:"repair add_index_params" desc:"check index param and try to add param"`
-	Collection          int64  `name:"collection" default:"0" desc:"collection id to filter with"`
+	Collection          int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter with"`
 	Key                 string `name:"key" default:"retrieve_friendly" desc:"add params key"`
 	Value               string `name:"value" default:"true" desc:"add params value"`
 	Run                 bool   `name:"run" default:"false" desc:"actual do repair"`
+	framework.PlanParam
 }
 
 func (c *ComponentRepair) AddIndexParamsCommand(ctx context.Context, p *AddIndexParamParam) error {
+	c = c.guarded()
 	indexes, err := common.ListIndex(ctx, c.client, c.basePath, func(index *models.FieldIndex) bool {
 		return p.Collection == 0 || p.Collection == index.GetProto().GetIndexInfo().GetCollectionID()
 	})
@@ -63,7 +66,8 @@ func (c *ComponentRepair) AddIndexParamsCommand(ctx context.Context, p *AddIndex
 		return nil
 	}
 	for _, index := range newIndexes {
-		if err := writeRepairedIndex(c.client, c.basePath, index.GetProto()); err != nil {
+		ctx := kv.WithChangeReason(ctx, fmt.Sprintf("add index param %s=%s to index %d", p.Key, p.Value, index.GetProto().GetIndexInfo().GetIndexID()))
+		if err := writeRepairedIndex(ctx, c.client, c.basePath, index.GetProto()); err != nil {
 			return err
 		}
 	}
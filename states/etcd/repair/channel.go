@@ -17,7 +17,7 @@ import (
 
 type RepairChannelParam struct {
 	framework.ParamBase `use:"repair channel" desc:"do channel watch change and try to repair"`
-	Collection          int64 `name:"collection" default:"0" desc:"collection id to filter with"`
+	Collection          int64 `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter with"`
 	Run                 bool  `name:"run" default:"false" desc:"actual do repair"`
 }
 
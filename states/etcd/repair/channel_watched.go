@@ -11,17 +11,20 @@ import (
 	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 )
 
 type ChannelWatchedParam struct {
 	framework.ParamBase `use:"repair channel-watch"`
-	CollectionID        int64  `name:"collection" default:"0" desc:"collection id to repair"`
+	CollectionID        int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id to repair"`
 	ChannelName         string `name:"vchannel" default:"" desc:"channel name to repair"`
 	Run                 bool   `name:"run" default:"false" desc:"whether to remove legacy collection meta, default set to \"false\" to dry run"`
+	framework.PlanParam
 }
 
 func (c *ComponentRepair) RepairChannelWatchedCommand(ctx context.Context, p *ChannelWatchedParam) error {
+	c = c.guarded()
 	infos, err := common.ListChannelWatch(ctx, c.client, c.basePath, func(channel *models.ChannelWatch) bool {
 		return (p.CollectionID == 0 || channel.GetProto().Vchan.CollectionID == p.CollectionID) &&
 			(p.ChannelName == "" || channel.GetProto().Vchan.ChannelName == p.ChannelName)
@@ -57,6 +60,7 @@ func (c *ComponentRepair) RepairChannelWatchedCommand(ctx context.Context, p *Ch
 		fmt.Println("Collection schema found, about to set schema as:")
 		fmt.Println(sb.String())
 		if p.Run {
+			ctx := kv.WithChangeReason(ctx, fmt.Sprintf("fill empty schema of channel %s watch info", info.GetProto().GetVchan().GetChannelName()))
 			err := common.WriteChannelWatchInfo(ctx, c.client, c.basePath, info, collection.GetProto().GetSchema())
 			if err != nil {
 				fmt.Println("failed to write modified channel watch info, err: ", err.Error())
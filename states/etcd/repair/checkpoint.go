@@ -22,22 +22,26 @@ import (
 
 type RepairCheckpointParam struct {
 	framework.ParamBase `use:"repair checkpoint" desc:"reset checkpoint of vchannels to latest checkpoint(or latest msgID) of physical channel"`
-	Collection          int64  `name:"collection" default:"0" desc:"collection id"`
+	Collection          int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id"`
 	VChannel            string `name:"vchannel" default:"" desc:"vchannel name"`
-	SetTo               string `name:"set_to" default:"latest-cp" desc:"support latest-cp(the latest checkpoint from segment checkpoint of corresponding collection on this physical channel) and latest-msgid(the latest msg from this physical channel)"`
-	MqType              string `name:"mq_type" default:"kafka" desc:"MQ type, only support kafka(default) and pulsar"`
+	SetTo               string `name:"set_to" default:"latest-cp" enum:"latest-cp,latest-msgid" desc:"support latest-cp(the latest checkpoint from segment checkpoint of corresponding collection on this physical channel) and latest-msgid(the latest msg from this physical channel)"`
+	MqType              string `name:"mq_type" default:"kafka" enum:"kafka,pulsar" desc:"MQ type, only support kafka(default) and pulsar"`
 	Address             string `name:"address" default:"localhost:9092" desc:"mq endpoint, default value is kafka address"`
 	Run                 bool   `name:"run" default:"false" desc:"actual do repair"`
+	framework.PlanParam
 }
 
 // CheckpointCommand usage:
 // repair checkpoint --collection 437744071571606912 --vchannel by-dev-rootcoord-dml_3_437744071571606912v1 --mq_type kafka --address localhost:9092 --set_to latest-msgid
 // repair checkpoint --collection 437744071571606912 --vchannel by-dev-rootcoord-dml_3_437744071571606912v1 --mq_type pulsar --address pulsar://localhost:6650 --set_to latest-msgid
 func (c *ComponentRepair) RepairCheckpointCommand(ctx context.Context, p *RepairCheckpointParam) error {
+	c = c.guarded()
 	coll, err := common.GetCollectionByIDVersion(ctx, c.client, c.basePath, p.Collection)
 	if err != nil {
 		return errors.Wrap(err, "failed to get collection")
 	}
+	fmt.Printf("Repairing checkpoint of collection %s\n",
+		common.CollectionLabel(common.CollectionNames(ctx, c.client, c.basePath), coll.GetProto().GetID()))
 
 	switch p.SetTo {
 	case "latest-cp":
@@ -60,6 +64,7 @@ func setCheckPointWithLatestMsgID(ctx context.Context, cli kv.MetaKV, basePath s
 				return errors.Wrapf(err, "vchannel:%s -> pchannel:%s, get latest msgID failed", ch.VirtualName, pChannel)
 			}
 
+			ctx := kv.WithChangeReason(ctx, fmt.Sprintf("reset checkpoint of vchannel %s to latest msgID of pchannel %s", ch.VirtualName, pChannel))
 			err = saveChannelCheckpoint(ctx, cli, basePath, ch.VirtualName, cp)
 			t, _ := utils.ParseTS(cp.GetTimestamp())
 			if err != nil {
@@ -93,6 +98,7 @@ func setCheckPointWithLatestCheckPoint(ctx context.Context, cli kv.MetaKV, baseP
 			}
 
 			t, _ := utils.ParseTS(cp.GetTimestamp())
+			ctx := kv.WithChangeReason(ctx, fmt.Sprintf("reset checkpoint of vchannel %s to latest checkpoint of pchannel %s", ch.VirtualName, pChannel))
 			err := saveChannelCheckpoint(ctx, cli, basePath, ch.VirtualName, cp)
 			if err != nil {
 				return errors.Errorf("failed to set latest checkpoint(ts:%v) for vchannel:%s", t, ch.VirtualName)
@@ -7,15 +7,18 @@ import (
 	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
 )
 
 type CollectionLegacyDroppedParams struct {
 	framework.ParamBase `use:"repair legacy-collection-remnant"`
-	CollectionID        int64 `name:"collection" default:"0" desc:"collection id to repair"`
+	CollectionID        int64 `name:"collection" resolve:"collection" default:"0" desc:"collection id to repair"`
 	Run                 bool  `name:"run" default:"false" desc:"whether to remove legacy collection meta, default set to \"false\" to dry run"`
+	framework.PlanParam
 }
 
 func (c *ComponentRepair) CollectionLegacyDroppedCommand(ctx context.Context, p *CollectionLegacyDroppedParams) error {
+	c = c.guarded()
 	collections, err := common.ListCollections(ctx, c.client, c.basePath, func(info *models.Collection) bool {
 		coll := info.GetProto()
 		return coll.DbId == 0 && len(coll.Schema.Fields) == 0 && (p.CollectionID == 0 || p.CollectionID == coll.ID)
@@ -29,6 +32,7 @@ func (c *ComponentRepair) CollectionLegacyDroppedCommand(ctx context.Context, p
 		collection := info.GetProto()
 		fmt.Printf("collection [%d]%s is suspect of legacy collection remnant\n", collection.ID, collection.Schema.Name)
 		if p.Run {
+			ctx := kv.WithChangeReason(ctx, fmt.Sprintf("legacy collection remnant %d without schema fields", collection.ID))
 			key := info.Key()
 			fmt.Printf("start to remove remnant meta for %s, key:%s\n", collection.Schema.Name, key)
 			err := c.client.Remove(ctx, info.Key())
@@ -58,7 +62,7 @@ func (c *ComponentRepair) CollectionLegacyDroppedCommand(ctx context.Context, p
 			}
 			for _, hc := range historyCollections {
 				if p.Run {
-					c.client.Remove(ctx, hc.Key())
+					c.client.Remove(kv.WithChangeReason(ctx, fmt.Sprintf("legacy history of removed collection %d", p.CollectionID)), hc.Key())
 				} else {
 					fmt.Println("legacy collection history found:", hc.Key())
 				}
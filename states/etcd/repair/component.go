@@ -18,3 +18,11 @@ func NewComponent(cli kv.MetaKV, config *configs.Config, basePath string) *Compo
 		basePath: basePath,
 	}
 }
+
+// guarded returns component copy whose meta writes abort if the key is modified concurrently,
+// e.g. by coordinators, after it was read for analysis.
+func (c *ComponentRepair) guarded() *ComponentRepair {
+	shadow := *c
+	shadow.client = kv.NewGuardedKV(c.client)
+	return &shadow
+}
@@ -26,6 +26,7 @@ func IndexMetricCommand(cli kv.MetaKV, basePath string) *cobra.Command {
 		Aliases: []string{"indexes_metric_type"},
 		Short:   "do index meta of metric_type check and try to repair",
 		Run: func(cmd *cobra.Command, args []string) {
+			cli := kv.NewGuardedKV(cli)
 			collID, err := cmd.Flags().GetInt64("collection")
 			if err != nil {
 				fmt.Println(err.Error())
@@ -85,7 +86,8 @@ func IndexMetricCommand(cli kv.MetaKV, basePath string) *cobra.Command {
 						fmt.Println("no metric_type in IndexParams or TypeParams")
 						return
 					}
-					if err := writeRepairedIndex(cli, basePath, newIndex); err != nil {
+					ctx := kv.WithChangeReason(context.Background(), fmt.Sprintf("fill metric_type of index %d from type params", newIndex.GetIndexInfo().GetIndexID()))
+					if err := writeRepairedIndex(ctx, cli, basePath, newIndex); err != nil {
 						fmt.Println(err.Error())
 						return
 					}
@@ -117,14 +119,14 @@ func IndexMetricCommand(cli kv.MetaKV, basePath string) *cobra.Command {
 // 	return indexes, err
 // }
 
-func writeRepairedIndex(cli kv.MetaKV, basePath string, index *indexpb.FieldIndex) error {
+func writeRepairedIndex(ctx context.Context, cli kv.MetaKV, basePath string, index *indexpb.FieldIndex) error {
 	p := path.Join(basePath, fmt.Sprintf("field-index/%d/%d", index.IndexInfo.CollectionID, index.IndexInfo.IndexID))
 
 	bs, err := proto.Marshal(index)
 	if err != nil {
 		fmt.Println("failed to marshal segment info", err.Error())
 	}
-	err = cli.Save(context.Background(), p, string(bs))
+	err = cli.Save(ctx, p, string(bs))
 	return err
 }
 
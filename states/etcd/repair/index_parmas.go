@@ -20,6 +20,7 @@ func DiskAnnIndexParamsCommand(cli kv.MetaKV, basePath string) *cobra.Command {
 		Aliases: []string{"diskann_index_params"},
 		Short:   "check index parma and try to repair",
 		Run: func(cmd *cobra.Command, args []string) {
+			cli := kv.NewGuardedKV(cli)
 			collID, err := cmd.Flags().GetInt64("collection")
 			if err != nil {
 				fmt.Println(err.Error())
@@ -152,7 +153,9 @@ func DiskAnnIndexParamsCommand(cli kv.MetaKV, basePath string) *cobra.Command {
 				return
 			}
 			for _, index := range newIndexes {
-				if err := writeRepairedIndex(cli, basePath, index.GetProto()); err != nil {
+				ctx := kv.WithChangeReason(context.Background(), fmt.Sprintf("remove unnecessary params %v of index %d",
+					unnecessaryParamsMap[index.GetProto().GetIndexInfo().GetIndexID()], index.GetProto().GetIndexInfo().GetIndexID()))
+				if err := writeRepairedIndex(ctx, cli, basePath, index.GetProto()); err != nil {
 					fmt.Println(err.Error())
 					return
 				}
@@ -18,7 +18,7 @@ import (
 
 type ManualCompactionParam struct {
 	framework.ParamBase `use:"repair manual-compaction" desc:"do manual compaction"`
-	Collection          int64 `name:"collection" default:"0" desc:"collection id"`
+	Collection          int64 `name:"collection" resolve:"collection" default:"0" desc:"collection id"`
 }
 
 func (c *ComponentRepair) ManualCompactionCommand(ctx context.Context, p *ManualCompactionParam) error {
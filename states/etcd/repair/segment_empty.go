@@ -7,6 +7,7 @@ import (
 	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
 )
@@ -15,10 +16,12 @@ type RepairEmptySegmentParam struct {
 	framework.ParamBase `use:"repair empty-segment" desc:"Remove empty segment from meta"`
 
 	Run bool `name:"run" default:"false" desc:"flags indicating whether to remove segments from meta"`
+	framework.PlanParam
 }
 
 // EmptySegmentCommand returns repair empty-segment command.
 func (c *ComponentRepair) RepairEmptySegmentCommand(ctx context.Context, p *RepairEmptySegmentParam) error {
+	c = c.guarded()
 	segments, err := common.ListSegments(ctx, c.client, c.basePath, func(info *models.Segment) bool {
 		return info.GetState() == commonpb.SegmentState_Flushed ||
 			info.GetState() == commonpb.SegmentState_Flushing ||
@@ -35,6 +38,7 @@ func (c *ComponentRepair) RepairEmptySegmentCommand(ctx context.Context, p *Repa
 			fmt.Printf("suspect segment %d found:\n", info.GetID())
 			fmt.Printf("SegmentID: %d State: %s, Row Count:%d\n", info.ID, info.State.String(), info.NumOfRows)
 			if p.Run {
+				ctx := kv.WithChangeReason(ctx, fmt.Sprintf("%s segment %d has no binlog", info.GetState().String(), info.GetID()))
 				err := common.RemoveSegment(ctx, c.client, c.basePath, info.SegmentInfo)
 				if err == nil {
 					fmt.Printf("remove segment %d from meta succeed\n", info.GetID())
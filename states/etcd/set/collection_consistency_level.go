@@ -14,7 +14,7 @@ import (
 
 type CollectionConsistencyLevelParam struct {
 	framework.ParamBase `use:"set collection consistency-level" desc:"set collection default consistency level"`
-	CollectionID        int64  `name:"collection" default:"0" desc:"collection id to update"`
+	CollectionID        int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id to update"`
 	ConsistencyLevel    string `name:"consistency-level" default:"" desc:"Consistency Level to set"`
 	Run                 bool   `name:"run" default:"false"`
 }
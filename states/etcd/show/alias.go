@@ -16,7 +16,7 @@ import (
 )
 
 type AliasParam struct {
-	framework.ParamBase `use:"show alias" desc:"list alias meta info" alias:"aliases"`
+	framework.ParamBase `use:"show alias" desc:"list alias meta info" alias:"aliases" readonly:"true"`
 	DBID                int64 `name:"dbid" default:"-1" desc:"database id to filter with"`
 }
 
@@ -0,0 +1,407 @@
+package show
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
+	"github.com/milvus-io/milvus/pkg/v2/proto/etcdpb"
+)
+
+const (
+	bloatTombstone         = "tombstone"
+	bloatSnapshot          = "snapshot"
+	bloatDroppedCollection = "dropped-collection"
+
+	// etcd default backend quota is 2GB
+	etcdDefaultQuota = 2 * 1024 * 1024 * 1024
+)
+
+// collectionScopedPrefixes are meta prefixes followed by collection id.
+var collectionScopedPrefixes = []string{
+	common.FieldMetaPrefix,
+	path.Join(common.RCPrefix, common.PartitionPrefix),
+	common.IndexPrefix,
+	common.SegmentIndexPrefix,
+	path.Join(common.DCPrefix, common.SegmentMetaPrefix),
+	path.Join(common.DCPrefix, "binlog"),
+	path.Join(common.DCPrefix, "deltalog"),
+	common.SegmentStatsMetaPrefix,
+	common.CollectionLoadPrefixV2,
+	common.PartitionLoadedPrefix,
+	common.ReplicaPrefix,
+}
+
+type AnalyzeEtcdParam struct {
+	framework.ParamBase `use:"analyze etcd" desc:"analyze etcd key space size, revision churn, compaction health and milvus meta bloat"`
+	Prefix              string `name:"prefix" default:"" desc:"the kv prefix to analyze, default is the instance base path"`
+	Level               int64  `name:"level" default:"2" desc:"the level of prefixes to aggregate size with"`
+	TopK                int64  `name:"topK" default:"10" desc:"the number of top prefixes & keys to show"`
+	KeysOnly            bool   `name:"keys-only" default:"false" desc:"scan keys only which is cheaper, value size & tombstones are not counted"`
+}
+
+// AnalyzeEtcdCommand reports etcd key space usage and health.
+func (c *ComponentShow) AnalyzeEtcdCommand(ctx context.Context, p *AnalyzeEtcdParam) (*EtcdAnalysis, error) {
+	statsKV, ok := c.client.(kv.StatsKV)
+	if !ok {
+		return nil, errors.New("analyze etcd requires connecting to etcd meta store")
+	}
+	prefix := p.Prefix
+	if prefix == "" {
+		prefix = c.basePath + "/"
+	}
+
+	collections, err := common.ListCollections(ctx, c.client, c.metaPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list collections")
+	}
+	live := make(map[int64]struct{})
+	for _, coll := range collections {
+		if coll.GetProto().GetState() != etcdpb.CollectionState_CollectionDropped {
+			live[coll.GetProto().GetID()] = struct{}{}
+		}
+	}
+
+	analyzer := newEtcdAnalyzer(c.metaPath, prefix, int(p.Level), int(p.TopK), p.KeysOnly, live)
+	if err := statsKV.WalkStatsWithPrefix(ctx, prefix, p.KeysOnly, 1000, analyzer.add); err != nil {
+		return nil, errors.Wrap(err, "failed to scan etcd")
+	}
+	rs := analyzer.result()
+
+	rs.Store, err = statsKV.StoreStats(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get etcd status")
+	}
+	rs.checkStore()
+	return rs, nil
+}
+
+// PrefixUsage is the key count & size of one prefix.
+type PrefixUsage struct {
+	Level  int    `json:"level"`
+	Prefix string `json:"prefix"`
+	Keys   int64  `json:"keys"`
+	Bytes  int64  `json:"bytes"`
+}
+
+// MetaBloat is the milvus meta entries which are safe to clean or shall be cleaned by milvus.
+type MetaBloat struct {
+	Kind   string `json:"kind"`
+	Keys   int64  `json:"keys"`
+	Bytes  int64  `json:"bytes"`
+	Sample string `json:"sample"`
+}
+
+// EtcdAnalysis is the result of analyze etcd.
+type EtcdAnalysis struct {
+	Prefix      string         `json:"prefix"`
+	KeysOnly    bool           `json:"keys_only"`
+	TotalKeys   int64          `json:"total_keys"`
+	TotalBytes  int64          `json:"total_bytes"`
+	Prefixes    []*PrefixUsage `json:"prefixes"`
+	LargestKeys []*kv.KeyStat  `json:"largest_keys"`
+	ChurnKeys   []*kv.KeyStat  `json:"churn_keys"`
+	Bloats      []*MetaBloat   `json:"bloats"`
+	Store       *kv.StoreStats `json:"store"`
+	Warnings    []string       `json:"warnings"`
+}
+
+func (rs *EtcdAnalysis) Entities() any {
+	return rs
+}
+
+func (rs *EtcdAnalysis) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatJSON:
+		bs, err := json.MarshalIndent(rs, "", "  ")
+		if err != nil {
+			return err.Error()
+		}
+		return string(bs)
+	case framework.FormatDefault, framework.FormatPlain:
+		return rs.printDefault()
+	default:
+	}
+	return ""
+}
+
+func (rs *EtcdAnalysis) printDefault() string {
+	sb := &strings.Builder{}
+	sizeNote := ""
+	if rs.KeysOnly {
+		sizeNote = " (keys only, value size not counted)"
+	}
+	fmt.Fprintf(sb, "Prefix %s: %d keys, %s%s\n", rs.Prefix, rs.TotalKeys, hrSize(rs.TotalBytes), sizeNote)
+
+	fmt.Fprintln(sb, "\n=== Size by prefix ===")
+	level := 0
+	for _, usage := range rs.Prefixes {
+		if usage.Level != level {
+			level = usage.Level
+			fmt.Fprintf(sb, "Level %d:\n", level)
+		}
+		fmt.Fprintf(sb, "  %-70s keys: %-8d size: %s\n", usage.Prefix, usage.Keys, hrSize(usage.Bytes))
+	}
+
+	fmt.Fprintln(sb, "\n=== Largest keys ===")
+	for _, stat := range rs.LargestKeys {
+		fmt.Fprintf(sb, "  %-80s size: %s\n", stat.Key, hrSize(int64(len(stat.Key))+stat.ValueSize))
+	}
+
+	fmt.Fprintln(sb, "\n=== Highest version keys ===")
+	for _, stat := range rs.ChurnKeys {
+		fmt.Fprintf(sb, "  %-80s version: %-10d create rev: %-12d mod rev: %d\n", stat.Key, stat.Version, stat.CreateRevision, stat.ModRevision)
+	}
+
+	fmt.Fprintln(sb, "\n=== Milvus meta bloat ===")
+	for _, bloat := range rs.Bloats {
+		fmt.Fprintf(sb, "  %-20s keys: %-8d size: %-24s sample: %s\n", bloat.Kind, bloat.Keys, hrSize(bloat.Bytes), bloat.Sample)
+	}
+	if rs.KeysOnly {
+		fmt.Fprintln(sb, "  tombstones not detected in keys only mode")
+	}
+
+	if rs.Store != nil {
+		fmt.Fprintln(sb, "\n=== Store ===")
+		fmt.Fprintf(sb, "  Revision: %d\tCompacted revision: %d\tUncompacted revisions: %d\n", rs.Store.Revision, rs.Store.CompactRevision, rs.Store.Revision-rs.Store.CompactRevision)
+		for _, ep := range rs.Store.Endpoints {
+			fmt.Fprintf(sb, "  Endpoint: %s\tVersion: %s\tDB size: %s\tIn use: %s\n", ep.Endpoint, ep.Version, hrSize(ep.DBSize), hrSize(ep.DBSizeInUse))
+		}
+	}
+
+	if len(rs.Warnings) > 0 {
+		fmt.Fprintln(sb, "\n=== Warnings ===")
+		for _, warning := range rs.Warnings {
+			fmt.Fprintf(sb, "  %s\n", warning)
+		}
+	}
+	return sb.String()
+}
+
+// checkStore generates warnings from store status.
+func (rs *EtcdAnalysis) checkStore() {
+	store := rs.Store
+	if store.CompactRevision == 0 && store.Revision > 1 {
+		rs.Warnings = append(rs.Warnings, fmt.Sprintf("etcd never compacted, %d revisions kept in history, check auto-compaction settings", store.Revision))
+	}
+	for _, ep := range store.Endpoints {
+		if ep.DBSize >= etcdDefaultQuota*8/10 {
+			rs.Warnings = append(rs.Warnings, fmt.Sprintf("endpoint %s db size %s is close to default 2GB quota", ep.Endpoint, hrSize(ep.DBSize)))
+		}
+		if ep.DBSize > 0 && ep.DBSizeInUse*2 < ep.DBSize {
+			rs.Warnings = append(rs.Warnings, fmt.Sprintf("endpoint %s only %s of %s db in use, defrag could reclaim space", ep.Endpoint, hrSize(ep.DBSizeInUse), hrSize(ep.DBSize)))
+		}
+	}
+	for _, bloat := range rs.Bloats {
+		if bloat.Keys > 0 {
+			rs.Warnings = append(rs.Warnings, fmt.Sprintf("%d %s entries found, e.g. %s", bloat.Keys, bloat.Kind, bloat.Sample))
+		}
+	}
+}
+
+type etcdAnalyzer struct {
+	metaPath string
+	prefix   string
+	level    int
+	topK     int
+	keysOnly bool
+	live     map[int64]struct{}
+
+	totalKeys  int64
+	totalBytes int64
+	prefixes   map[int]map[string]*PrefixUsage
+	largest    []*kv.KeyStat
+	churn      []*kv.KeyStat
+	bloats     map[string]*MetaBloat
+	// snapshots grouped by origin key, counted as bloat after scan since key order is not ts order
+	snapshots map[string][]*snapshotStat
+}
+
+func newEtcdAnalyzer(metaPath, prefix string, level, topK int, keysOnly bool, live map[int64]struct{}) *etcdAnalyzer {
+	return &etcdAnalyzer{
+		metaPath:  metaPath,
+		prefix:    prefix,
+		level:     level,
+		topK:      topK,
+		keysOnly:  keysOnly,
+		live:      live,
+		prefixes:  make(map[int]map[string]*PrefixUsage),
+		snapshots: make(map[string][]*snapshotStat),
+		bloats: map[string]*MetaBloat{
+			bloatTombstone:         {Kind: bloatTombstone},
+			bloatSnapshot:          {Kind: bloatSnapshot},
+			bloatDroppedCollection: {Kind: bloatDroppedCollection},
+		},
+	}
+}
+
+func (a *etcdAnalyzer) add(stat *kv.KeyStat) error {
+	size := int64(len(stat.Key)) + stat.ValueSize
+	a.totalKeys++
+	a.totalBytes += size
+
+	for level := 1; level <= a.level; level++ {
+		prefix := getNthLevelPrefix(stat.Key, a.prefix, level)
+		if prefix == "" {
+			break
+		}
+		usages, ok := a.prefixes[level]
+		if !ok {
+			usages = make(map[string]*PrefixUsage)
+			a.prefixes[level] = usages
+		}
+		usage, ok := usages[prefix]
+		if !ok {
+			usage = &PrefixUsage{Level: level, Prefix: prefix}
+			usages[prefix] = usage
+		}
+		usage.Keys++
+		usage.Bytes += size
+	}
+
+	for _, kind := range a.bloatKinds(stat) {
+		a.addBloat(kind, stat.Key, size)
+	}
+
+	// value not kept in result
+	kept := *stat
+	kept.Value = nil
+	a.largest = a.keepTopK(a.largest, &kept, func(stat *kv.KeyStat) int64 { return int64(len(stat.Key)) + stat.ValueSize })
+	a.churn = a.keepTopK(a.churn, &kept, func(stat *kv.KeyStat) int64 { return stat.Version })
+	return nil
+}
+
+func (a *etcdAnalyzer) addBloat(kind string, key string, size int64) {
+	bloat := a.bloats[kind]
+	bloat.Keys++
+	bloat.Bytes += size
+	if bloat.Sample == "" {
+		bloat.Sample = key
+	}
+}
+
+// snapshotStat is one rootcoord snapshot key seen during scan.
+type snapshotStat struct {
+	key       string
+	ts        uint64
+	size      int64
+	tombstone bool
+}
+
+// addSnapshotBloats counts snapshots `remove snapshots` could reclaim, regardless of retention:
+// all snapshots superseded by a newer one, and the latest one as well if meta is dropped (latest is tombstone).
+// Tombstones are not detected in keys only mode, so only superseded snapshots are counted.
+func (a *etcdAnalyzer) addSnapshotBloats() {
+	origins := lo.Keys(a.snapshots)
+	sort.Strings(origins)
+	for _, origin := range origins {
+		snapshots := a.snapshots[origin]
+		sort.Slice(snapshots, func(i, j int) bool {
+			return snapshots[i].ts < snapshots[j].ts
+		})
+		if !snapshots[len(snapshots)-1].tombstone {
+			snapshots = snapshots[:len(snapshots)-1]
+		}
+		for _, snapshot := range snapshots {
+			a.addBloat(bloatSnapshot, snapshot.key, snapshot.size)
+		}
+	}
+}
+
+// keepTopK appends stat and truncates stats to top k by score when it grows too large.
+func (a *etcdAnalyzer) keepTopK(stats []*kv.KeyStat, stat *kv.KeyStat, score func(*kv.KeyStat) int64) []*kv.KeyStat {
+	stats = append(stats, stat)
+	if len(stats) > 2*a.topK+16 {
+		stats = a.topKOf(stats, score)
+	}
+	return stats
+}
+
+func (a *etcdAnalyzer) topKOf(stats []*kv.KeyStat, score func(*kv.KeyStat) int64) []*kv.KeyStat {
+	sort.SliceStable(stats, func(i, j int) bool {
+		return score(stats[i]) > score(stats[j])
+	})
+	if len(stats) > a.topK {
+		stats = stats[:a.topK]
+	}
+	return stats
+}
+
+// bloatKinds returns the bloat kinds key belongs to, snapshot entries could be tombstones as well.
+// Snapshot entries are recorded and counted after scan.
+func (a *etcdAnalyzer) bloatKinds(stat *kv.KeyStat) []string {
+	var kinds []string
+	tombstone := !a.keysOnly && bytes.Equal(stat.Value, common.CollectionTombstone)
+	if tombstone {
+		kinds = append(kinds, bloatTombstone)
+	}
+	key, ok := strings.CutPrefix(stat.Key, a.metaPath+"/")
+	if !ok {
+		// not milvus meta
+		return kinds
+	}
+	if snapshotKey, ok := strings.CutPrefix(key, common.SnapshotPrefix+"/"); ok {
+		if origin, ts, ok := common.SplitSnapshotKey(snapshotKey); ok {
+			a.snapshots[origin] = append(a.snapshots[origin], &snapshotStat{
+				key:       stat.Key,
+				ts:        ts,
+				size:      int64(len(stat.Key)) + stat.ValueSize,
+				tombstone: tombstone,
+			})
+		}
+		return kinds
+	}
+	for _, prefix := range collectionScopedPrefixes {
+		rest, ok := strings.CutPrefix(key, prefix+"/")
+		if !ok {
+			continue
+		}
+		idStr, _, _ := strings.Cut(rest, "/")
+		collectionID, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			break
+		}
+		if _, ok := a.live[collectionID]; !ok {
+			kinds = append(kinds, bloatDroppedCollection)
+		}
+		break
+	}
+	return kinds
+}
+
+func (a *etcdAnalyzer) result() *EtcdAnalysis {
+	a.addSnapshotBloats()
+	rs := &EtcdAnalysis{
+		Prefix:      a.prefix,
+		KeysOnly:    a.keysOnly,
+		TotalKeys:   a.totalKeys,
+		TotalBytes:  a.totalBytes,
+		LargestKeys: a.topKOf(a.largest, func(stat *kv.KeyStat) int64 { return int64(len(stat.Key)) + stat.ValueSize }),
+		ChurnKeys:   a.topKOf(a.churn, func(stat *kv.KeyStat) int64 { return stat.Version }),
+		Bloats: lo.Map([]string{bloatTombstone, bloatSnapshot, bloatDroppedCollection}, func(kind string, _ int) *MetaBloat {
+			return a.bloats[kind]
+		}),
+	}
+	for level := 1; level <= a.level; level++ {
+		usages := lo.Values(a.prefixes[level])
+		sort.Slice(usages, func(i, j int) bool {
+			return usages[i].Bytes > usages[j].Bytes
+		})
+		if len(usages) > a.topK {
+			usages = usages[:a.topK]
+		}
+		rs.Prefixes = append(rs.Prefixes, usages...)
+	}
+	return rs
+}
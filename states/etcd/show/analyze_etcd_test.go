@@ -0,0 +1,62 @@
+package show
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+)
+
+func TestAnalyzeEtcd(t *testing.T) {
+	ctx := context.Background()
+	c := fakecluster.New(t)
+	c.AddCollection(fakecluster.NewCollection(100, "coll").WithPrimaryKey(100, "pk").WithPartition(101, "_default")).
+		AddSegment(fakecluster.NewSegment(1001, 100, 101).WithRows(10)).
+		AddSegment(fakecluster.NewSegment(2001, 200, 201).WithRows(10).WithBinlog(100, 1))
+	// dropped meta, latest snapshot is tombstone
+	c.Put(path.Join(common.SnapshotPrefix, "root-coord/collection/200_ts1"), string(common.CollectionTombstone))
+	// ts2 superseded by ts10, latest snapshot is kept
+	c.Put(path.Join(common.SnapshotPrefix, "root-coord/collection/100_ts2"), "v1")
+	c.Put(path.Join(common.SnapshotPrefix, "root-coord/collection/100_ts10"), "v2")
+	c.Put(path.Join(common.SnapshotPrefix, "root-coord/collection/101_ts5"), "v1")
+	for i := 0; i < 5; i++ {
+		c.Put(path.Join(common.DCPrefix, common.ChannelCheckpointPrefix, "dml_0"), "cp")
+	}
+
+	resp, err := c.Client().Get(ctx, "/")
+	require.NoError(t, err)
+	_, err = c.Client().Compact(ctx, resp.Header.Revision)
+	require.NoError(t, err)
+
+	show := NewComponent(c.KV(), nil, fakecluster.RootPath, fakecluster.MetaPath)
+	rs, err := show.AnalyzeEtcdCommand(ctx, &AnalyzeEtcdParam{Level: 2, TopK: 3})
+	require.NoError(t, err)
+
+	bloats := lo.SliceToMap(rs.Bloats, func(bloat *MetaBloat) (string, int64) { return bloat.Kind, bloat.Keys })
+	assert.EqualValues(t, 1, bloats[bloatTombstone])
+	assert.EqualValues(t, 2, bloats[bloatSnapshot])
+	// segment & binlog meta of collection 200
+	assert.EqualValues(t, 2, bloats[bloatDroppedCollection])
+
+	require.NotEmpty(t, rs.ChurnKeys)
+	assert.Equal(t, path.Join(c.BasePath(), common.DCPrefix, common.ChannelCheckpointPrefix, "dml_0"), rs.ChurnKeys[0].Key)
+	assert.EqualValues(t, 5, rs.ChurnKeys[0].Version)
+	assert.Len(t, rs.LargestKeys, 3)
+	assert.Equal(t, resp.Header.Revision, rs.Store.CompactRevision)
+	assert.Equal(t, "by-dev/meta", rs.Prefixes[0].Prefix)
+
+	rs, err = show.AnalyzeEtcdCommand(ctx, &AnalyzeEtcdParam{Level: 1, TopK: 3, KeysOnly: true})
+	require.NoError(t, err)
+	assert.Zero(t, rs.Bloats[0].Keys)
+	// tombstone not detected, only superseded snapshot counted
+	assert.EqualValues(t, 1, rs.Bloats[1].Keys)
+	assert.Equal(t, path.Join(c.BasePath(), common.SnapshotPrefix, "root-coord/collection/100_ts2"), rs.Bloats[1].Sample)
+	assert.NotEmpty(t, rs.PrintAs(framework.FormatDefault))
+}
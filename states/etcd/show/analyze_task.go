@@ -0,0 +1,79 @@
+package show
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+)
+
+type AnalyzeTaskParam struct {
+	framework.ParamBase `use:"show analyze-task" desc:"display clustering compaction analyze task meta from DataCoord" alias:"analyze-tasks" readonly:"true"`
+	CollectionID        int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter with"`
+	PartitionID         int64  `name:"partition" default:"0" desc:"partition id to filter with"`
+	TaskID              int64  `name:"task" default:"0" desc:"task id to filter with"`
+	NodeID              int64  `name:"node" default:"0" desc:"worker node id to filter with"`
+	State               string `name:"state" default:"" desc:"task state to filter with, [JobStateInit, JobStateInProgress, JobStateFinished, JobStateFailed, JobStateRetry]"`
+	Detail              bool   `name:"detail" default:"false" desc:"flags indicating whether printing segment ids"`
+}
+
+// AnalyzeTaskCommand implements `show analyze-task` command.
+func (c *ComponentShow) AnalyzeTaskCommand(ctx context.Context, p *AnalyzeTaskParam) (*AnalyzeTasks, error) {
+	tasks, err := common.ListAnalyzeTask(ctx, c.client, c.metaPath, func(task *models.AnalyzeTask) bool {
+		t := task.GetProto()
+		return (p.CollectionID == 0 || t.GetCollectionID() == p.CollectionID) &&
+			(p.PartitionID == 0 || t.GetPartitionID() == p.PartitionID) &&
+			(p.TaskID == 0 || t.GetTaskID() == p.TaskID) &&
+			(p.NodeID == 0 || t.GetNodeID() == p.NodeID) &&
+			(p.State == "" || strings.EqualFold(t.GetState().String(), p.State))
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].GetProto().GetTaskID() < tasks[j].GetProto().GetTaskID()
+	})
+	return &AnalyzeTasks{
+		ListResultSet: framework.ListResultSet[*models.AnalyzeTask]{Data: tasks},
+		detail:        p.Detail,
+	}, nil
+}
+
+type AnalyzeTasks struct {
+	framework.ListResultSet[*models.AnalyzeTask]
+	detail bool
+}
+
+func (rs *AnalyzeTasks) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatDefault, framework.FormatPlain:
+		sb := &strings.Builder{}
+		for _, task := range rs.Data {
+			t := task.GetProto()
+			fmt.Fprintf(sb, "TaskID: %d\tState: %s\tCollectionID: %d\tPartitionID: %d\tField: %d(%s, %s)\tNodeID: %d\tVersion: %d\tSegments: %d\n",
+				t.GetTaskID(), t.GetState().String(), t.GetCollectionID(), t.GetPartitionID(), t.GetFieldID(), t.GetFieldName(), t.GetFieldType().String(),
+				t.GetNodeID(), t.GetVersion(), len(t.GetSegmentIDs()))
+			if t.GetCentroidsFile() != "" {
+				fmt.Fprintf(sb, "\tCentroids File: %s\tDim: %d\n", t.GetCentroidsFile(), t.GetDim())
+			}
+			if t.GetFailReason() != "" {
+				fmt.Fprintf(sb, "\tFail Reason: %s\n", t.GetFailReason())
+			}
+			if rs.detail {
+				fmt.Fprintf(sb, "\tSegmentIDs: %v\n", t.GetSegmentIDs())
+			}
+		}
+		printStateCount(sb, "analyze task", lo.Map(rs.Data, func(task *models.AnalyzeTask, _ int) string {
+			return task.GetProto().GetState().String()
+		}))
+		return sb.String()
+	default:
+	}
+	return ""
+}
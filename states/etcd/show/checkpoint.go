@@ -17,8 +17,8 @@ import (
 )
 
 type CheckpointParam struct {
-	framework.ParamBase `use:"show checkpoint" desc:"list checkpoint collection vchannels" alias:"checkpoints,cp"`
-	CollectionID        int64 `name:"collection" default:"0" desc:"collection id to filter with"`
+	framework.ParamBase `use:"show checkpoint" desc:"list checkpoint collection vchannels" alias:"checkpoints,cp" readonly:"true"`
+	CollectionID        int64 `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter with"`
 }
 
 // CheckpointCommand returns show checkpoint command.
@@ -60,7 +60,9 @@ func (c *ComponentShow) CheckpointCommand(ctx context.Context, p *CheckpointPara
 		checkpoints = append(checkpoints, checkpoint)
 	}
 
-	return framework.NewListResult[Checkpoints](checkpoints), nil
+	rs := framework.NewListResult[Checkpoints](checkpoints)
+	rs.collection = common.CollectionLabel(common.CollectionNames(ctx, c.client, c.metaPath), coll.GetProto().GetID())
+	return rs, nil
 }
 
 type Checkpoint struct {
@@ -71,12 +73,14 @@ type Checkpoint struct {
 
 type Checkpoints struct {
 	framework.ListResultSet[*Checkpoint]
+	collection string
 }
 
 func (rs *Checkpoints) PrintAs(format framework.Format) string {
 	switch format {
 	case framework.FormatDefault, framework.FormatPlain:
 		sb := &strings.Builder{}
+		fmt.Fprintf(sb, "Collection %s\n", rs.collection)
 		for _, checkpoint := range rs.Data {
 			if checkpoint.Checkpoint == nil {
 				fmt.Fprintf(sb, "Vchannel %s checkpoint not found, fallback to collection start pos\n", checkpoint.Channel.VirtualName)
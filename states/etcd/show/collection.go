@@ -19,7 +19,7 @@ import (
 // CollectionCommand returns sub command for showCmd.
 // show collection [options...]
 type CollectionParam struct {
-	framework.ParamBase `use:"show collections" desc:"list current available collection from RootCoord"`
+	framework.ParamBase `use:"show collections" desc:"list current available collection from RootCoord" readonly:"true"`
 	CollectionID        int64  `name:"id" default:"0" desc:"collection id to display"`
 	CollectionName      string `name:"name" default:"" desc:"collection name to display"`
 	DatabaseID          int64  `name:"dbid" default:"-1" desc:"database id to filter"`
@@ -58,21 +58,9 @@ func (c *ComponentShow) CollectionCommand(ctx context.Context, p *CollectionPara
 	if err != nil {
 		return nil, err
 	}
-	channels := 0
-	healthy := 0
-	for _, collection := range collections {
-		if collection.GetProto().State == etcdpb.CollectionState_CollectionCreated {
-			channels += len(collection.GetProto().GetVirtualChannelNames())
-			healthy++
-		}
-	}
-
-	return &Collections{
-		collections: collections,
-		total:       total,
-		channels:    channels,
-		healthy:     healthy,
-	}, nil
+	rs := &Collections{total: total}
+	rs.SetEntities(collections)
+	return rs, nil
 }
 
 type Collections struct {
@@ -101,6 +89,21 @@ func (rs *Collections) Entities() any {
 	return rs.collections
 }
 
+// SetEntities implements framework.EntitiesSetter, channel & healthy statistics are updated with entities.
+func (rs *Collections) SetEntities(entities any) {
+	collections, ok := entities.([]*models.Collection)
+	if !ok {
+		return
+	}
+	rs.collections, rs.channels, rs.healthy = collections, 0, 0
+	for _, collection := range collections {
+		if collection.GetProto().State == etcdpb.CollectionState_CollectionCreated {
+			rs.channels += len(collection.GetProto().GetVirtualChannelNames())
+			rs.healthy++
+		}
+	}
+}
+
 func printCollection(sb *strings.Builder, info *models.Collection) {
 	collection := info.GetProto()
 	fmt.Fprintln(sb, "================================================================================")
@@ -1,70 +1,105 @@
 package show
 
-/*
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
+	"github.com/milvus-io/birdwatcher/utils"
+)
+
 type CollectionHistoryParam struct {
-	framework.ParamBase `use:"show collection-history" desc:"display collection change history"`
-	CollectionID        int64 `name:"id" default:"0" desc:"collection id to display" form:"id"`
-	// DBID                int64 `name:"dbid" default:"0" desc:"database id to display"`
+	framework.ParamBase `use:"show collection-history" desc:"display collection change timeline from rootcoord snapshots" readonly:"true"`
+	CollectionID        int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id, name, db.name or alias to display, dropped collection by id only"`
+	Type                string `name:"type" default:"" desc:"event type to filter, [State|Schema|Property|Partition|Alias|Other]"`
+	Diff                bool   `name:"diff" default:"false" desc:"print diff between consecutive snapshots"`
 }
 
 // CollectionHistoryCommand returns sub command for showCmd.
 // show collection-history [options...]
 func (c *ComponentShow) CollectionHistoryCommand(ctx context.Context, p *CollectionHistoryParam) (*CollectionHistory, error) {
 	if p.CollectionID == 0 {
-		return nil, errors.New("collection id not provided")
+		return nil, errors.New("collection not provided")
 	}
 
-	// fetch current for now
-	collection, err := common.GetCollectionByIDVersion(ctx, c.client, c.metaPath, etcdversion.GetVersion(), p.CollectionID)
-	if err != nil {
-		switch {
-		case errors.Is(err, common.ErrCollectionDropped):
-			return nil, fmt.Errorf("[Current] collection id %d already marked with Tombstone", p.CollectionID)
-		case errors.Is(err, common.ErrCollectionNotFound):
-			return nil, fmt.Errorf("[Current] collection id %d not found", p.CollectionID)
-		default:
-			return nil, err
-		}
+	result := &CollectionHistory{param: p}
+	// current collection could be already removed from meta
+	collection, err := common.GetCollectionByIDVersion(ctx, c.client, c.metaPath, p.CollectionID)
+	switch {
+	case err == nil:
+		result.Collection = collection
+	case errors.Is(err, common.ErrCollectionNotFound), errors.Is(err, kv.ErrKeyNotFound):
+	default:
+		return nil, errors.Wrap(err, "failed to get current collection meta")
 	}
 
-	result := &CollectionHistory{
-		Collection: collection,
-	}
-	// fetch history
-	items, err := common.ListCollectionHistory(ctx, c.client, c.metaPath, etcdversion.GetVersion(), collection.DBID, p.CollectionID)
+	events, warnings, err := common.ListCollectionTimeline(ctx, c.client, c.metaPath, p.CollectionID)
 	if err != nil {
 		return nil, err
 	}
-
-	result.HistoryItems = items
+	if p.Type != "" {
+		events = lo.Filter(events, func(e *models.CollectionHistoryEvent, _ int) bool {
+			return strings.EqualFold(string(e.Type), p.Type)
+		})
+	}
+	if len(events) == 0 && result.Collection == nil {
+		return nil, fmt.Errorf("collection %d not found in current meta nor snapshots", p.CollectionID)
+	}
+	result.Events = events
+	result.Warnings = warnings
 	return result, nil
 }
 
 type CollectionHistory struct {
-	Collection   *models.Collection
-	HistoryItems []*models.CollectionHistory
+	Collection *models.Collection
+	Events     []*models.CollectionHistoryEvent
+	// Warnings holds snapshots failed to decode
+	Warnings []string
+	param    *CollectionHistoryParam
 }
 
 func (rs *CollectionHistory) PrintAs(format framework.Format) string {
 	switch format {
 	case framework.FormatDefault, framework.FormatPlain:
 		sb := &strings.Builder{}
-		printCollection(sb, rs.Collection)
-		for _, item := range rs.HistoryItems {
-			t, _ := utils.ParseTS(item.Ts)
-			fmt.Fprintln(sb, "Snapshot at", t.Format("2006-01-02 15:04:05"))
-			if item.Dropped {
-				fmt.Fprintln(sb, "Collection Dropped")
-				continue
+		if rs.Collection != nil {
+			printCollection(sb, rs.Collection)
+		} else {
+			fmt.Fprintf(sb, "[Current] collection %d not found in meta, history from snapshots only\n", rs.param.CollectionID)
+		}
+		fmt.Fprintln(sb, "================================================================================")
+		fmt.Fprintln(sb, "Timeline:")
+		for _, event := range rs.Events {
+			t, logical := utils.ParseTS(event.Ts)
+			fmt.Fprintf(sb, "%s(%d) [%s] %s", t.Format("2006-01-02 15:04:05.000"), logical, event.Type, event.DDL)
+			if event.Target != "" {
+				fmt.Fprintf(sb, " %s", event.Target)
+			}
+			fmt.Fprintf(sb, ": %s\n", event.Detail)
+			if rs.param.Diff {
+				for _, change := range event.Changes {
+					fmt.Fprintf(sb, "\t%s\n", change)
+				}
+				fmt.Fprintf(sb, "\tsnapshot key: %s\n", event.Key)
 			}
-			printCollection(sb, &item.Collection)
 		}
+		for _, warning := range rs.Warnings {
+			fmt.Fprintf(sb, "[Warning] %s\n", warning)
+		}
+		fmt.Fprintf(sb, "--- Total event(s): %d\n", len(rs.Events))
+		return sb.String()
 	default:
 	}
 	return ""
 }
 
 func (rs *CollectionHistory) Entities() any {
-	return rs
+	return rs.Events
 }
-*/
@@ -0,0 +1,84 @@
+package show
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+	"github.com/milvus-io/birdwatcher/states/kv"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/etcdpb"
+)
+
+// failingKV fails prefix loads of current collection meta.
+type failingKV struct {
+	kv.MetaKV
+}
+
+func (f failingKV) LoadWithPrefix(ctx context.Context, key string, opts ...kv.LoadOption) ([]string, []string, error) {
+	if strings.Contains(key, common.DBCollectionMetaPrefix) && !strings.Contains(key, common.SnapshotPrefix) {
+		return nil, nil, errors.New("connection lost")
+	}
+	return f.MetaKV.LoadWithPrefix(ctx, key, opts...)
+}
+
+func TestCollectionHistory(t *testing.T) {
+	ctx := context.Background()
+	c := fakecluster.New(t)
+	live := fakecluster.NewCollection(100, "coll").WithPrimaryKey(100, "pk")
+	c.AddCollection(live)
+
+	snapshot := func(origin string, ts string, value string) {
+		c.Put(path.Join(common.SnapshotPrefix, origin+"_ts"+ts), value)
+	}
+	marshal := func(info *etcdpb.CollectionInfo) string {
+		bs, err := proto.Marshal(info)
+		require.NoError(t, err)
+		return string(bs)
+	}
+	info := proto.Clone(live.Build()).(*etcdpb.CollectionInfo)
+	snapshot(path.Join(common.DBCollectionMetaPrefix, "0/100"), "10", marshal(info))
+	info.Properties = []*commonpb.KeyValuePair{{Key: "collection.ttl.seconds", Value: "60"}}
+	snapshot(path.Join(common.DBCollectionMetaPrefix, "0/100"), "20", marshal(info))
+	snapshot(path.Join(common.DBCollectionMetaPrefix, "0/100"), "30", "\xff")
+
+	dropped := &etcdpb.CollectionInfo{ID: 200, Schema: live.Build().GetSchema()}
+	snapshot(path.Join(common.DBCollectionMetaPrefix, "0/200"), "5", marshal(dropped))
+	snapshot(path.Join(common.DBCollectionMetaPrefix, "0/200"), "15", string(common.CollectionTombstone))
+
+	show := NewComponent(c.KV(), nil, fakecluster.RootPath, fakecluster.MetaPath)
+	rs, err := show.CollectionHistoryCommand(ctx, &CollectionHistoryParam{CollectionID: 100})
+	require.NoError(t, err)
+	require.NotNil(t, rs.Collection)
+	require.Len(t, rs.Events, 2)
+	assert.Equal(t, "CreateCollection", rs.Events[0].DDL)
+	assert.Equal(t, models.CollectionEventProperty, rs.Events[1].Type)
+	// undecodable snapshot is reported as warning
+	require.Len(t, rs.Warnings, 1)
+	assert.Contains(t, rs.Warnings[0], "_ts30")
+
+	rs, err = show.CollectionHistoryCommand(ctx, &CollectionHistoryParam{CollectionID: 200, Type: "State"})
+	require.NoError(t, err)
+	assert.Nil(t, rs.Collection)
+	require.Len(t, rs.Events, 2)
+	assert.Equal(t, "DropCollection", rs.Events[1].DDL)
+	assert.Contains(t, rs.PrintAs(framework.FormatDefault), "collection 200 not found in meta")
+
+	_, err = show.CollectionHistoryCommand(ctx, &CollectionHistoryParam{CollectionID: 300})
+	assert.Error(t, err)
+
+	// errors other than not found shall not be reported as collection missing
+	show = NewComponent(failingKV{MetaKV: c.KV()}, nil, fakecluster.RootPath, fakecluster.MetaPath)
+	_, err = show.CollectionHistoryCommand(ctx, &CollectionHistoryParam{CollectionID: 200})
+	assert.ErrorContains(t, err, "connection lost")
+}
@@ -18,8 +18,8 @@ const (
 )
 
 type CollectionLoadedParam struct {
-	framework.ParamBase `use:"show collection-loaded" desc:"display information of loaded collection from querycoord" alias:"collection-load"`
-	CollectionID        int64 `name:"collection" default:"0" desc:"collection id to check"`
+	framework.ParamBase `use:"show collection-loaded" desc:"display information of loaded collection from querycoord" alias:"collection-load" readonly:"true"`
+	CollectionID        int64 `name:"collection" resolve:"collection" default:"0" desc:"collection id to check"`
 }
 
 // CollectionLoadedCommand return show collection-loaded command.
@@ -17,10 +17,10 @@ import (
 // CompactionCommand returns sub command for showCmd.
 // show compaction [options...]
 type CompactionTaskParam struct {
-	framework.ParamBase `use:"show compactions" desc:"list current available compactions from DataCoord"`
+	framework.ParamBase `use:"show compactions" desc:"list current available compactions from DataCoord" readonly:"true"`
 	CollectionName      string `name:"collectionName" default:"" desc:"collection name to display"`
 	State               string `name:"state" default:"" desc:"compaction state to filter"`
-	CollectionID        int64  `name:"collectionID" default:"0" desc:"collection id to filter"`
+	CollectionID        int64  `name:"collectionID" resolve:"collection" default:"0" desc:"collection id to filter"`
 	PartitionID         int64  `name:"partitionID" default:"0" desc:"partitionID id to filter"`
 	TriggerID           int64  `name:"triggerID" default:"0" desc:"TriggerID to filter"`
 	PlanID              int64  ` name:"planID" default:"0" desc:"PlanID  to filter"`
@@ -1,9 +1,13 @@
 package show
 
 import (
+	"context"
 	"path"
 
+	"github.com/cockroachdb/errors"
+
 	"github.com/milvus-io/birdwatcher/configs"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
 	"github.com/milvus-io/birdwatcher/states/kv"
 )
 
@@ -26,3 +30,14 @@ func NewComponent(cli kv.MetaKV, config *configs.Config, basePath string, metaPa
 		metaPath: path.Join(basePath, metaPath),
 	}
 }
+
+// ResolveValue implements framework.ValueResolver, resolves collection reference into collection id
+// for command param fields tagged with `resolve:"collection"`.
+func (c *ComponentShow) ResolveValue(ctx context.Context, kind string, raw string) (any, error) {
+	switch kind {
+	case "collection":
+		return common.ResolveCollectionID(ctx, c.client, c.metaPath, raw)
+	default:
+		return nil, errors.Newf("unknown resolve kind %s", kind)
+	}
+}
@@ -9,7 +9,7 @@ import (
 )
 
 type ConfigEtcdParam struct {
-	framework.ParamBase `use:"show config-etcd" desc:"list configuations set by etcd source"`
+	framework.ParamBase `use:"show config-etcd" desc:"list configuations set by etcd source" readonly:"true"`
 }
 
 // ConfigEtcdCommand return show config-etcd command.
@@ -13,7 +13,7 @@ import (
 )
 
 type DatabaseParam struct {
-	framework.ParamBase `use:"show database" desc:"display Database info from rootcoord meta"`
+	framework.ParamBase `use:"show database" desc:"display Database info from rootcoord meta" readonly:"true"`
 	DatabaseID          int64  `name:"id" default:"0" desc:"database id to filter with"`
 	DatabaseName        string `name:"name" default:"" desc:"database name to filter with"`
 }
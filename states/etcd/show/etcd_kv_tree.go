@@ -11,7 +11,7 @@ import (
 )
 
 type EtcdKVTree struct {
-	framework.ParamBase `use:"show etcd-kv-tree" desc:"show etcd kv tree with key size of each prefix"`
+	framework.ParamBase `use:"show etcd-kv-tree" desc:"show etcd kv tree with key size of each prefix" readonly:"true"`
 	Prefix              string `name:"prefix" default:"" desc:"the kv prefix to show"`
 	Level               int64  `name:"level" default:"1" desc:"the level of kv tree to show"`
 	TopK                int64  `name:"topK" default:"10" desc:"the number of top prefixes to show per level"`
@@ -3,53 +3,338 @@ package show
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
 
 	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/states/etcd/common"
 	"github.com/milvus-io/birdwatcher/utils"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
 )
 
+// maxPrintedSegments is the max number of segment ids printed for segments without index.
+const maxPrintedSegments = 20
+
 type IndexParam struct {
-	framework.ParamBase `use:"show index" desc:"" alias:"indexes"`
-	CollectionID        int64 `name:"collection" default:"0" desc:"collection id to list index on"`
+	framework.ParamBase `use:"show index" desc:"display index with field schema, build progress and health report" alias:"indexes" readonly:"true"`
+	CollectionID        int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id to list index on"`
+	IndexID             int64  `name:"indexID" default:"0" desc:"index id to filter with"`
+	SearchMetric        string `name:"search-metric" default:"" desc:"metric type used by search requests, warn if index metric type differs"`
 }
 
 // IndexCommand returns show index command.
-func (c *ComponentShow) IndexCommand(ctx context.Context, p *IndexParam) error {
+func (c *ComponentShow) IndexCommand(ctx context.Context, p *IndexParam) (*Indexes, error) {
 	fieldIndexes, err := common.ListIndex(ctx, c.client, c.metaPath, func(info *models.FieldIndex) bool {
-		return p.CollectionID == 0 || p.CollectionID == info.GetProto().GetIndexInfo().GetCollectionID()
+		return (p.CollectionID == 0 || p.CollectionID == info.GetProto().GetIndexInfo().GetCollectionID()) &&
+			(p.IndexID == 0 || p.IndexID == info.GetProto().GetIndexInfo().GetIndexID())
+	})
+	if err != nil {
+		return nil, err
+	}
+	collectionIDs := lo.Uniq(lo.Map(fieldIndexes, func(index *models.FieldIndex, _ int) int64 {
+		return index.GetProto().GetIndexInfo().GetCollectionID()
+	}))
+
+	segments, err := common.ListSegments(ctx, c.client, c.metaPath, func(segment *models.Segment) bool {
+		return lo.Contains(collectionIDs, segment.CollectionID) && isIndexableSegment(segment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	collSegments := lo.GroupBy(segments, func(segment *models.Segment) int64 { return segment.CollectionID })
+
+	segmentIndexes, err := common.ListSegmentIndex(ctx, c.client, c.metaPath, func(segIdx *models.SegmentIndex) bool {
+		return lo.Contains(collectionIDs, segIdx.GetProto().GetCollectionID()) && !segIdx.GetProto().GetDeleted()
+	})
+	if err != nil {
+		return nil, err
+	}
+	// index id => segment id => segment index
+	indexSegments := make(map[int64]map[int64]*models.SegmentIndex)
+	for _, segIdx := range segmentIndexes {
+		indexID := segIdx.GetProto().GetIndexID()
+		if _, ok := indexSegments[indexID]; !ok {
+			indexSegments[indexID] = make(map[int64]*models.SegmentIndex)
+		}
+		indexSegments[indexID][segIdx.GetProto().GetSegmentID()] = segIdx
+	}
+
+	collections := make(map[int64]*models.Collection)
+	for _, collectionID := range collectionIDs {
+		collection, err := common.GetCollectionByIDVersion(ctx, c.client, c.metaPath, collectionID)
+		if err == nil {
+			collections[collectionID] = collection
+		}
+	}
+
+	engine := queryNodeEngineVersion(ctx, c)
+	names := common.CollectionNames(ctx, c.client, c.metaPath)
+	reports := lo.Map(fieldIndexes, func(index *models.FieldIndex, _ int) *IndexReport {
+		collectionID := index.GetProto().GetIndexInfo().GetCollectionID()
+		report := newIndexReport(index, collections[collectionID], common.CollectionLabel(names, collectionID))
+		report.NodeEngineVersion = engine
+		if !report.Deleted {
+			report.collectBuilds(collSegments[collectionID], indexSegments[report.IndexID])
+		}
+		report.check(collections[collectionID] != nil, p.SearchMetric)
+		return report
 	})
+	return framework.NewListResult[Indexes](reports), nil
+}
+
+// isIndexableSegment returns whether segment shall have index built, L0 segments hold deletions only.
+func isIndexableSegment(segment *models.Segment) bool {
+	return (segment.State == commonpb.SegmentState_Flushed || segment.State == commonpb.SegmentState_Flushing) &&
+		segment.Level != datapb.SegmentLevel_L0
+}
+
+// queryNodeEngineVersion returns engine version range supported by all querynodes,
+// segment index built with version out of range could not be loaded.
+func queryNodeEngineVersion(ctx context.Context, c *ComponentShow) models.IndexEngineVersion {
+	sessions, err := common.ListSessions(ctx, c.client, c.metaPath)
 	if err != nil {
-		return err
+		return models.IndexEngineVersion{}
+	}
+	var result models.IndexEngineVersion
+	for _, session := range sessions {
+		version := session.IndexEngineVersion
+		if session.ServerName != "querynode" || version.CurrentIndexVersion == 0 {
+			continue
+		}
+		if result.CurrentIndexVersion == 0 || version.CurrentIndexVersion < result.CurrentIndexVersion {
+			result.CurrentIndexVersion = version.CurrentIndexVersion
+		}
+		result.MinimalIndexVersion = max(result.MinimalIndexVersion, version.MinimalIndexVersion)
+	}
+	return result
+}
+
+// FailedBuild is the failed segment index build.
+type FailedBuild struct {
+	SegmentID  int64
+	BuildID    int64
+	FailReason string
+}
+
+// IndexReport is the field index joined with schema and aggregated segment index builds.
+type IndexReport struct {
+	IndexID     int64
+	IndexName   string
+	Collection  string
+	FieldID     int64
+	FieldName   string
+	DataType    string
+	Dim         string
+	IndexType   string
+	MetricType  string
+	IsAutoIndex bool
+	Deleted     bool
+	CreateTime  uint64
+
+	Finished       int
+	InProgress     int
+	Failed         int
+	Unissued       int
+	NoIndex        int
+	IndexedRows    int64
+	TotalRows      int64
+	SerializedSize uint64
+	MemSize        uint64
+
+	// index version => number of segments
+	IndexVersions     map[int64]int
+	MinEngineVersion  int32
+	MaxEngineVersion  int32
+	NodeEngineVersion models.IndexEngineVersion
+
+	FailedBuilds    []*FailedBuild
+	NoIndexSegments []int64
+	Warnings        []string
+
+	index *models.FieldIndex
+	field *schemapb.FieldSchema
+}
+
+func newIndexReport(index *models.FieldIndex, collection *models.Collection, collectionLabel string) *IndexReport {
+	info := index.GetProto().GetIndexInfo()
+	report := &IndexReport{
+		IndexID:       info.GetIndexID(),
+		IndexName:     info.GetIndexName(),
+		Collection:    collectionLabel,
+		FieldID:       info.GetFieldID(),
+		IndexType:     common.GetKVPair(info.GetIndexParams(), "index_type"),
+		MetricType:    common.GetKVPair(info.GetIndexParams(), "metric_type"),
+		IsAutoIndex:   info.GetIsAutoIndex(),
+		Deleted:       index.GetProto().GetDeleted(),
+		CreateTime:    index.GetProto().GetCreateTime(),
+		IndexVersions: make(map[int64]int),
+		index:         index,
+	}
+	if collection == nil {
+		return report
+	}
+	field, ok := lo.Find(collection.GetProto().GetSchema().GetFields(), func(field *schemapb.FieldSchema) bool {
+		return field.GetFieldID() == info.GetFieldID()
+	})
+	if ok {
+		report.field = field
+		report.FieldName = field.GetName()
+		report.DataType = field.GetDataType().String()
+		report.Dim = common.GetKVPair(field.GetTypeParams(), "dim")
+	}
+	return report
+}
+
+// collectBuilds aggregates segment index build states of indexable segments.
+func (r *IndexReport) collectBuilds(segments []*models.Segment, segIdxs map[int64]*models.SegmentIndex) {
+	sort.Slice(segments, func(i, j int) bool { return segments[i].ID < segments[j].ID })
+	for _, segment := range segments {
+		r.TotalRows += segment.NumOfRows
+		segIdx, ok := segIdxs[segment.ID]
+		if !ok {
+			r.NoIndex++
+			r.NoIndexSegments = append(r.NoIndexSegments, segment.ID)
+			continue
+		}
+		info := segIdx.GetProto()
+		switch info.GetState() {
+		case commonpb.IndexState_Finished:
+			r.Finished++
+			r.IndexedRows += info.GetNumRows()
+			r.SerializedSize += info.GetSerializeSize()
+			r.MemSize += info.GetMemSize()
+			r.IndexVersions[info.GetIndexVersion()]++
+			if r.MinEngineVersion == 0 || info.GetCurrentIndexVersion() < r.MinEngineVersion {
+				r.MinEngineVersion = info.GetCurrentIndexVersion()
+			}
+			r.MaxEngineVersion = max(r.MaxEngineVersion, info.GetCurrentIndexVersion())
+		case commonpb.IndexState_InProgress, commonpb.IndexState_Retry:
+			r.InProgress++
+		case commonpb.IndexState_Failed:
+			r.Failed++
+			r.FailedBuilds = append(r.FailedBuilds, &FailedBuild{
+				SegmentID:  segment.ID,
+				BuildID:    info.GetBuildID(),
+				FailReason: info.GetFailReason(),
+			})
+		default:
+			r.Unissued++
+		}
+	}
+}
+
+// check appends warnings for config mismatches & unhealthy builds.
+func (r *IndexReport) check(hasCollection bool, searchMetric string) {
+	warn := func(format string, args ...any) {
+		r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+	}
+	if !hasCollection {
+		warn("collection meta not found")
+	} else if r.field == nil {
+		warn("field %d not found in collection schema", r.FieldID)
+	}
+
+	info := r.index.GetProto().GetIndexInfo()
+	if userMetric := common.GetKVPair(info.GetUserIndexParams(), "metric_type"); userMetric != "" && !strings.EqualFold(userMetric, r.MetricType) {
+		warn("metric type %s in index params differs from user specified %s", r.MetricType, userMetric)
+	}
+	if searchMetric != "" && r.MetricType != "" && !strings.EqualFold(searchMetric, r.MetricType) {
+		warn("index built with metric type %s while searched with %s", r.MetricType, searchMetric)
+	}
+	if dim := common.GetKVPair(info.GetTypeParams(), "dim"); dim != "" && r.Dim != "" && dim != r.Dim {
+		warn("index dim %s differs from field dim %s", dim, r.Dim)
+	}
+	if r.Deleted {
+		return
 	}
 
-	for _, index := range fieldIndexes {
-		printIndex(index)
+	if r.Failed > 0 {
+		warn("%d segment index builds failed", r.Failed)
+	}
+	if r.NoIndex > 0 {
+		warn("%d segments have no index record", r.NoIndex)
 	}
-	return nil
+	if len(r.IndexVersions) > 1 {
+		warn("segments indexed with %d different index versions", len(r.IndexVersions))
+	}
+	node := r.NodeEngineVersion
+	if node.CurrentIndexVersion > 0 && r.Finished > 0 {
+		if r.MinEngineVersion < node.MinimalIndexVersion {
+			warn("segment index engine version %d is below querynode minimal version %d", r.MinEngineVersion, node.MinimalIndexVersion)
+		}
+		if r.MaxEngineVersion > node.CurrentIndexVersion {
+			warn("segment index engine version %d is above querynode current version %d", r.MaxEngineVersion, node.CurrentIndexVersion)
+		}
+	}
+}
+
+type Indexes struct {
+	framework.ListResultSet[*IndexReport]
 }
 
-func printIndex(info *models.FieldIndex) {
-	index := info.GetProto()
-	fmt.Println("==================================================================")
-	fmt.Printf("Index ID: %d\tIndex Name: %s\tCollectionID: %d\tFieldID: %d\n", index.GetIndexInfo().GetIndexID(), index.GetIndexInfo().GetIndexName(), index.GetIndexInfo().GetCollectionID(), index.GetIndexInfo().GetFieldID())
-	createTime, _ := utils.ParseTS(index.GetCreateTime())
-	fmt.Printf("Create Time: %s\tDeleted: %t\n", createTime.Format(tsPrintFormat), index.GetDeleted())
-	indexParams := index.GetIndexInfo().GetIndexParams()
-	fmt.Printf("Index Type: %s\tMetric Type: %s\n",
-		common.GetKVPair(indexParams, "index_type"),
-		common.GetKVPair(indexParams, "metric_type"),
-	)
-	fmt.Printf("ParamsJSON : %s\n", common.GetKVPair(index.GetIndexInfo().GetUserIndexParams(), "params"))
-	// print detail param in meta
-	fmt.Println("Index.IndexParams:")
-	for _, kv := range indexParams {
-		fmt.Printf("\t%s: %s\n", kv.GetKey(), kv.GetValue())
+func (rs *Indexes) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatDefault, framework.FormatPlain:
+		sb := &strings.Builder{}
+		for _, report := range rs.Data {
+			printIndexReport(sb, report)
+		}
+		return sb.String()
+	default:
 	}
-	fmt.Println("Index.UserParams")
-	for _, kv := range index.GetIndexInfo().GetUserIndexParams() {
-		fmt.Printf("\t%s: %s\n", kv.GetKey(), kv.GetValue())
+	return ""
+}
+
+func printIndexReport(sb *strings.Builder, r *IndexReport) {
+	info := r.index.GetProto().GetIndexInfo()
+	fmt.Fprintln(sb, "==================================================================")
+	fmt.Fprintf(sb, "Index ID: %d\tIndex Name: %s\tCollection: %s\tDeleted: %t\n", r.IndexID, r.IndexName, r.Collection, r.Deleted)
+	fmt.Fprintf(sb, "Field: %s(%d)\tData Type: %s\tDim: %s\n", r.FieldName, r.FieldID, r.DataType, r.Dim)
+	fmt.Fprintf(sb, "Index Type: %s\tMetric Type: %s\tAuto Index: %t\n", r.IndexType, r.MetricType, r.IsAutoIndex)
+	createTime, _ := utils.ParseTS(r.CreateTime)
+	fmt.Fprintf(sb, "Create Time: %s\n", createTime.Format(tsPrintFormat))
+	fmt.Fprintf(sb, "Index Params: %s\n", formatKVPairs(info.GetIndexParams()))
+	fmt.Fprintf(sb, "User Params: %s\n", formatKVPairs(info.GetUserIndexParams()))
+	fmt.Fprintf(sb, "Type Params: %s\n", formatKVPairs(info.GetTypeParams()))
+	if !r.Deleted {
+		fmt.Fprintf(sb, "Build Progress: Finished %d, InProgress %d, Failed %d, Unissued %d, NoIndex %d, Indexed Rows %d/%d\n",
+			r.Finished, r.InProgress, r.Failed, r.Unissued, r.NoIndex, r.IndexedRows, r.TotalRows)
+		fmt.Fprintf(sb, "Index Size: Serialized %s, Memory %s\n", hrSize(int64(r.SerializedSize)), hrSize(int64(r.MemSize)))
+		versions := lo.Keys(r.IndexVersions)
+		sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+		fmt.Fprintf(sb, "Index Versions: %s\n", strings.Join(lo.Map(versions, func(version int64, _ int) string {
+			return fmt.Sprintf("v%d(%d segments)", version, r.IndexVersions[version])
+		}), ", "))
+		fmt.Fprintf(sb, "Engine Versions: min %d, current %d\tQueryNode Engine Versions: minimal %d, current %d\n",
+			r.MinEngineVersion, r.MaxEngineVersion, r.NodeEngineVersion.MinimalIndexVersion, r.NodeEngineVersion.CurrentIndexVersion)
+	}
+	if len(r.FailedBuilds) > 0 {
+		fmt.Fprintln(sb, "Failed Builds:")
+		for _, build := range r.FailedBuilds {
+			fmt.Fprintf(sb, "\tSegment %d\tBuild %d\tReason: %s\n", build.SegmentID, build.BuildID, build.FailReason)
+		}
+	}
+	if len(r.NoIndexSegments) > 0 {
+		printed := r.NoIndexSegments[:min(len(r.NoIndexSegments), maxPrintedSegments)]
+		fmt.Fprintf(sb, "Segments Without Index: %v", printed)
+		if len(printed) < len(r.NoIndexSegments) {
+			fmt.Fprintf(sb, " ... %d more", len(r.NoIndexSegments)-len(printed))
+		}
+		fmt.Fprintln(sb)
 	}
-	fmt.Println("==================================================================")
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(sb, "[WARN] %s\n", warning)
+	}
+	fmt.Fprintln(sb, "==================================================================")
+}
+
+func formatKVPairs(pairs []*commonpb.KeyValuePair) string {
+	return strings.Join(lo.Map(pairs, func(kv *commonpb.KeyValuePair, _ int) string {
+		return fmt.Sprintf("%s=%s", kv.GetKey(), kv.GetValue())
+	}), ", ")
 }
@@ -0,0 +1,50 @@
+package show
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+)
+
+func TestShowIndex(t *testing.T) {
+	ctx := context.Background()
+	c := fakecluster.New(t)
+	finished := fakecluster.NewSegment(1001, 100, 101).WithRows(3000)
+	failed := fakecluster.NewSegment(1002, 100, 101).WithRows(2000)
+	building := fakecluster.NewSegment(1003, 100, 101).WithRows(1000)
+	c.AddCollection(fakecluster.NewCollection(100, "coll").WithPrimaryKey(100, "pk").WithVector(101, "vec", 8).WithPartition(101, "_default")).
+		AddSegment(finished).AddSegment(failed).AddSegment(building).
+		AddSegment(fakecluster.NewSegment(1004, 100, 101).WithRows(500)).
+		AddSegment(fakecluster.NewSegment(1005, 100, 101).WithRows(500).WithState(commonpb.SegmentState_Dropped)).
+		AddIndex(fakecluster.NewIndex(100, 101, 10, "vec_idx").WithParams("index_type", "HNSW", "metric_type", "L2")).
+		AddSegmentIndex(fakecluster.NewSegmentIndex(finished, 10, 11)).
+		AddSegmentIndex(fakecluster.NewSegmentIndex(failed, 10, 12).WithFailReason("out of memory")).
+		AddSegmentIndex(fakecluster.NewSegmentIndex(building, 10, 13).WithState(commonpb.IndexState_InProgress))
+
+	show := NewComponent(c.KV(), nil, fakecluster.RootPath, fakecluster.MetaPath)
+	rs, err := show.IndexCommand(ctx, &IndexParam{CollectionID: 100, SearchMetric: "COSINE"})
+	require.NoError(t, err)
+	require.Len(t, rs.Data, 1)
+	report := rs.Data[0]
+	assert.Equal(t, "vec", report.FieldName)
+	assert.Equal(t, "FloatVector", report.DataType)
+	assert.Equal(t, "8", report.Dim)
+	assert.Equal(t, "HNSW", report.IndexType)
+	assert.Equal(t, []int{1, 1, 1, 0, 1}, []int{report.Finished, report.InProgress, report.Failed, report.Unissued, report.NoIndex})
+	assert.EqualValues(t, 3000, report.IndexedRows)
+	assert.EqualValues(t, 6500, report.TotalRows)
+	assert.Equal(t, []int64{1004}, report.NoIndexSegments)
+	require.Len(t, report.FailedBuilds, 1)
+	assert.Equal(t, "out of memory", report.FailedBuilds[0].FailReason)
+
+	output := rs.PrintAs(framework.FormatDefault)
+	assert.Contains(t, output, "index built with metric type L2 while searched with COSINE")
+	assert.Contains(t, output, "1 segments have no index record")
+	assert.Contains(t, output, "Segment 1002\tBuild 12\tReason: out of memory")
+}
@@ -13,8 +13,8 @@ import (
 )
 
 type PartitionParam struct {
-	framework.ParamBase `use:"show partition" desc:"list partitions of provided collection"`
-	CollectionID        int64 `name:"collection" default:"0" desc:"collection id to list"`
+	framework.ParamBase `use:"show partition" desc:"list partitions of provided collection" readonly:"true"`
+	CollectionID        int64 `name:"collection" resolve:"collection" default:"0" desc:"collection id to list"`
 }
 
 // PartitionCommand returns command to list partition info for provided collection.
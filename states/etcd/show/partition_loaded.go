@@ -11,8 +11,8 @@ import (
 )
 
 type PartitionLoadedParam struct {
-	framework.ParamBase `use:"show partition-loaded" desc:"display the information of loaded partition(s) from querycoord meta"`
-	CollectionID        int64 `name:"collection" default:"0" desc:"collection id to filter with"`
+	framework.ParamBase `use:"show partition-loaded" desc:"display the information of loaded partition(s) from querycoord meta" readonly:"true"`
+	CollectionID        int64 `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter with"`
 	PartitionID         int64 `name:"partition" default:"0" desc:"partition id to filter with"`
 }
 
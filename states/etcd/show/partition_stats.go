@@ -0,0 +1,74 @@
+package show
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+)
+
+type PartitionStatsParam struct {
+	framework.ParamBase `use:"show partition-stats" desc:"display partition stats info meta from DataCoord" readonly:"true"`
+	CollectionID        int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter with"`
+	PartitionID         int64  `name:"partition" default:"0" desc:"partition id to filter with"`
+	Channel             string `name:"channel" default:"" desc:"vchannel name to filter with"`
+	Detail              bool   `name:"detail" default:"false" desc:"flags indicating whether printing segment ids"`
+}
+
+// PartitionStatsCommand implements `show partition-stats` command.
+func (c *ComponentShow) PartitionStatsCommand(ctx context.Context, p *PartitionStatsParam) (*PartitionStatsInfos, error) {
+	infos, err := common.ListPartitionStats(ctx, c.client, c.metaPath, func(info *models.PartitionStats) bool {
+		ps := info.GetProto()
+		return (p.CollectionID == 0 || ps.GetCollectionID() == p.CollectionID) &&
+			(p.PartitionID == 0 || ps.GetPartitionID() == p.PartitionID) &&
+			(p.Channel == "" || ps.GetVChannel() == p.Channel)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Key() < infos[j].Key()
+	})
+	currents := make(map[string]string)
+	for _, info := range infos {
+		currents[info.Key()] = common.GetCurrentPartitionStatsVersion(ctx, c.client, c.metaPath, info)
+	}
+	return &PartitionStatsInfos{
+		ListResultSet: framework.ListResultSet[*models.PartitionStats]{Data: infos},
+		currents:      currents,
+		detail:        p.Detail,
+	}, nil
+}
+
+type PartitionStatsInfos struct {
+	framework.ListResultSet[*models.PartitionStats]
+	// currents is the key to current version mapping
+	currents map[string]string
+	detail   bool
+}
+
+func (rs *PartitionStatsInfos) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatDefault, framework.FormatPlain:
+		sb := &strings.Builder{}
+		for _, info := range rs.Data {
+			ps := info.GetProto()
+			current := rs.currents[info.Key()]
+			fmt.Fprintf(sb, "CollectionID: %d\tPartitionID: %d\tChannel: %s\tVersion: %d(current: %s)\tAnalyzeTaskID: %d\tSegments: %d\tCommitTime: %s\n",
+				ps.GetCollectionID(), ps.GetPartitionID(), ps.GetVChannel(), ps.GetVersion(), current, ps.GetAnalyzeTaskID(),
+				len(ps.GetSegmentIDs()), time.Unix(ps.GetCommitTime(), 0).Format("2006-01-02 15:04:05"))
+			if rs.detail {
+				fmt.Fprintf(sb, "\tSegmentIDs: %v\n", ps.GetSegmentIDs())
+			}
+		}
+		fmt.Fprintf(sb, "--- Total partition stats: %d\n", len(rs.Data))
+		return sb.String()
+	default:
+	}
+	return ""
+}
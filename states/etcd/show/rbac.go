@@ -0,0 +1,209 @@
+package show
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+)
+
+type RoleParam struct {
+	framework.ParamBase `use:"show role" desc:"display role info from rootcoord meta" alias:"roles" readonly:"true"`
+	Name                string `name:"name" default:"" desc:"role name to filter with"`
+}
+
+// RoleCommand implements `show role` command.
+func (c *ComponentShow) RoleCommand(ctx context.Context, p *RoleParam) (*Roles, error) {
+	roles, err := common.ListRoles(ctx, c.client, c.metaPath, func(r *models.Role) bool {
+		return p.Name == "" || r.Name == p.Name
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list role info")
+	}
+	bindings, err := common.ListUserRoles(ctx, c.client, c.metaPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list user-role mapping")
+	}
+	grants, err := common.ListGrants(ctx, c.client, c.metaPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list grants")
+	}
+
+	return &Roles{
+		ListResultSet: framework.ListResultSet[*models.Role]{Data: roles},
+		users: lo.MapValues(lo.GroupBy(bindings, func(b *models.UserRole) string {
+			return path.Join(b.Tenant, b.RoleName)
+		}), func(bindings []*models.UserRole, _ string) []string {
+			return lo.Map(bindings, func(b *models.UserRole, _ int) string { return b.Username })
+		}),
+		grants: lo.MapValues(lo.GroupBy(grants, func(g *models.Grant) string {
+			return path.Join(g.Tenant, g.RoleName)
+		}), func(grants []*models.Grant, _ string) int {
+			return len(grants)
+		}),
+	}, nil
+}
+
+type Roles struct {
+	framework.ListResultSet[*models.Role]
+	users  map[string][]string
+	grants map[string]int
+}
+
+func (rs *Roles) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatDefault, framework.FormatPlain:
+		sb := &strings.Builder{}
+		for _, role := range rs.Data {
+			key := path.Join(role.Tenant, role.Name)
+			fmt.Fprintf(sb, "Role: %s", role.Name)
+			if role.Tenant != "" {
+				fmt.Fprintf(sb, "\tTenant: %s", role.Tenant)
+			}
+			fmt.Fprintf(sb, "\tGrants: %d\tUsers: %v\n", rs.grants[key], rs.users[key])
+		}
+		fmt.Fprintf(sb, "--- Total Role(s): %d\n", len(rs.Data))
+		return sb.String()
+	default:
+	}
+	return ""
+}
+
+type GrantParam struct {
+	framework.ParamBase `use:"show grant" desc:"display grant entries from rootcoord meta" alias:"grants" readonly:"true"`
+	Role                string `name:"role" default:"" desc:"role name to filter with"`
+	Object              string `name:"object" default:"" desc:"object type(Collection/Global/User) or object name to filter with"`
+	DBName              string `name:"db" default:"" desc:"database name to filter with"`
+}
+
+// GrantCommand implements `show grant` command.
+func (c *ComponentShow) GrantCommand(ctx context.Context, p *GrantParam) (*Grants, error) {
+	grants, err := common.ListGrants(ctx, c.client, c.metaPath, func(g *models.Grant) bool {
+		if p.Role != "" && g.RoleName != p.Role {
+			return false
+		}
+		if p.Object != "" && !strings.EqualFold(g.Object, p.Object) && g.ObjectName != p.Object {
+			return false
+		}
+		if p.DBName != "" && g.DBName != p.DBName {
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list grants")
+	}
+	sort.Slice(grants, func(i, j int) bool {
+		if grants[i].RoleName != grants[j].RoleName {
+			return grants[i].RoleName < grants[j].RoleName
+		}
+		return grants[i].Key() < grants[j].Key()
+	})
+
+	return framework.NewListResult[Grants](grants), nil
+}
+
+type Grants struct {
+	framework.ListResultSet[*models.Grant]
+}
+
+func (rs *Grants) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatDefault, framework.FormatPlain:
+		sb := &strings.Builder{}
+		for _, grant := range rs.Data {
+			fmt.Fprintf(sb, "Role: %s\tObject: %s\tDatabase: %s\tObjectName: %s\n", grant.RoleName, grant.Object, grant.DBName, grant.ObjectName)
+			for _, priv := range grant.Privileges {
+				fmt.Fprintf(sb, "\tPrivilege: %s\tGrantor: %s\n", priv.Name, priv.Grantor)
+			}
+		}
+		fmt.Fprintf(sb, "--- Total Grant(s): %d\n", len(rs.Data))
+		return sb.String()
+	default:
+	}
+	return ""
+}
+
+type PrivilegeGroupParam struct {
+	framework.ParamBase `use:"show privilege-group" desc:"display custom privilege groups from rootcoord meta" readonly:"true"`
+	Name                string `name:"name" default:"" desc:"privilege group name to filter with"`
+}
+
+// PrivilegeGroupCommand implements `show privilege-group` command.
+func (c *ComponentShow) PrivilegeGroupCommand(ctx context.Context, p *PrivilegeGroupParam) (*PrivilegeGroups, error) {
+	groups, err := common.ListPrivilegeGroups(ctx, c.client, c.metaPath, func(g *models.PrivilegeGroup) bool {
+		return p.Name == "" || g.GetProto().GetGroupName() == p.Name
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list privilege groups")
+	}
+	return framework.NewListResult[PrivilegeGroups](groups), nil
+}
+
+type PrivilegeGroups struct {
+	framework.ListResultSet[*models.PrivilegeGroup]
+}
+
+func (rs *PrivilegeGroups) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatDefault, framework.FormatPlain:
+		sb := &strings.Builder{}
+		for _, group := range rs.Data {
+			fmt.Fprintf(sb, "Privilege Group: %s\tPrivileges: %v\n", group.GetProto().GetGroupName(), common.PrivilegeGroupNames(group.GetProto()))
+		}
+		fmt.Fprintf(sb, "--- Total Privilege Group(s): %d\n", len(rs.Data))
+		return sb.String()
+	default:
+	}
+	return ""
+}
+
+type RBACCheckParam struct {
+	framework.ParamBase `use:"check rbac" desc:"check rbac meta consistency with collection, database, role and user meta" readonly:"true"`
+}
+
+// RBACCheckCommand implements `check rbac` command.
+func (c *ComponentShow) RBACCheckCommand(ctx context.Context, p *RBACCheckParam) (*RBACIssues, error) {
+	issues, err := common.CheckRBAC(ctx, c.client, c.metaPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check rbac meta")
+	}
+	return framework.NewListResult[RBACIssues](issues), nil
+}
+
+type RBACIssues struct {
+	framework.ListResultSet[*models.RBACIssue]
+}
+
+func (rs *RBACIssues) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatDefault, framework.FormatPlain:
+		sb := &strings.Builder{}
+		for tp, issues := range lo.GroupBy(rs.Data, func(issue *models.RBACIssue) models.RBACIssueType { return issue.Type }) {
+			if issues[0].Warn {
+				fmt.Fprintf(sb, "[%s] %d warning(s), clean with `remove rbac-orphan --type %s` if not intended\n", tp, len(issues), tp)
+			} else {
+				fmt.Fprintf(sb, "[%s] %d issue(s)\n", tp, len(issues))
+			}
+			for _, issue := range issues {
+				fmt.Fprintf(sb, "\t%s\n", issue.Description)
+			}
+		}
+		if len(rs.Data) == 0 {
+			fmt.Fprintln(sb, "rbac meta check passed, no issue found")
+		} else {
+			fmt.Fprintf(sb, "--- Total Issue(s): %d, use `remove rbac-orphan` to clean them\n", len(rs.Data))
+		}
+		return sb.String()
+	default:
+	}
+	return ""
+}
@@ -15,8 +15,8 @@ import (
 )
 
 type ReplicaParam struct {
-	framework.ParamBase `use:"show replica" desc:"list current replica information from QueryCoord" alias:"replicas"`
-	CollectionID        int64 `name:"collection" default:"0" desc:"collection id to filter with"`
+	framework.ParamBase `use:"show replica" desc:"list current replica information from QueryCoord" alias:"replicas" readonly:"true"`
+	CollectionID        int64 `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter with"`
 }
 
 // ReplicaCommand returns command for show querycoord replicas.
@@ -11,7 +11,7 @@ import (
 )
 
 type ResourceGroupParam struct {
-	framework.ParamBase `use:"show resource-group" desc:"list resource groups in current instance"`
+	framework.ParamBase `use:"show resource-group" desc:"list resource groups in current instance" readonly:"true"`
 	Name                string `name:"name" default:"" desc:"resource group name to list"`
 }
 
@@ -3,6 +3,8 @@ package show
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"sort"
 	"strings"
 	"time"
@@ -17,14 +19,14 @@ import (
 )
 
 type SegmentParam struct {
-	framework.ParamBase `use:"show segment" desc:"display segment information from data coord meta store" alias:"segments"`
-	CollectionID        int64  `name:"collection" default:"0" desc:"collection id to filter with"`
+	framework.ParamBase `use:"show segment" desc:"display segment information from data coord meta store" alias:"segments" readonly:"true"`
+	CollectionID        int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter with"`
 	PartitionID         int64  `name:"partition" default:"0" desc:"partition id to filter with"`
 	SegmentID           int64  `name:"segment" default:"0" desc:"segment id to display"`
-	Format              string `name:"format" default:"line" desc:"segment display format"`
+	Format              string `name:"format" default:"line" enum:"line,table,statistics" desc:"segment display format"`
 	Detail              bool   `name:"detail" default:"false" desc:"flags indicating whether printing detail binlog info"`
-	State               string `name:"state" default:"" desc:"target segment state"`
-	Level               string `name:"level" default:"" desc:"target segment level"`
+	State               string `name:"state" default:"" enum:"Growing,Sealed,Flushing,Flushed,Dropped,Importing" desc:"target segment state"`
+	Level               string `name:"level" default:"" enum:"Legacy,L0,L1,L2" desc:"target segment level"`
 }
 
 type segStats struct {
@@ -40,7 +42,7 @@ type segStats struct {
 }
 
 // SegmentCommand returns show segments command.
-func (c *ComponentShow) SegmentCommand(ctx context.Context, p *SegmentParam) error {
+func (c *ComponentShow) SegmentCommand(ctx context.Context, p *SegmentParam) (*Segments, error) {
 	segments, err := common.ListSegments(ctx, c.client, c.metaPath, func(segment *models.Segment) bool {
 		return (p.CollectionID == 0 || segment.CollectionID == p.CollectionID) &&
 			(p.PartitionID == 0 || segment.PartitionID == p.PartitionID) &&
@@ -50,9 +52,52 @@ func (c *ComponentShow) SegmentCommand(ctx context.Context, p *SegmentParam) err
 	})
 	if err != nil {
 		fmt.Println("failed to list segments", err.Error())
-		return nil
+		return nil, nil
 	}
 
+	switch p.Format {
+	case "table", "line", "statistics":
+	default:
+		return nil, fmt.Errorf("unsupport format:%s", p.Format)
+	}
+
+	return &Segments{
+		segments: segments,
+		names:    common.CollectionNames(ctx, c.client, c.metaPath),
+		format:   p.Format,
+		detail:   p.Detail,
+	}, nil
+}
+
+type Segments struct {
+	segments []*models.Segment
+	names    map[int64]string
+	format   string
+	detail   bool
+}
+
+func (rs *Segments) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatDefault, framework.FormatPlain:
+		sb := &strings.Builder{}
+		rs.printSegments(sb)
+		return sb.String()
+	}
+	return ""
+}
+
+func (rs *Segments) Entities() any {
+	return rs.segments
+}
+
+// SetEntities implements framework.EntitiesSetter.
+func (rs *Segments) SetEntities(entities any) {
+	if segments, ok := entities.([]*models.Segment); ok {
+		rs.segments = segments
+	}
+}
+
+func (rs *Segments) printSegments(w io.Writer) {
 	totalRC := int64(0)
 	healthy := 0
 
@@ -61,12 +106,18 @@ func (c *ComponentShow) SegmentCommand(ctx context.Context, p *SegmentParam) err
 	var smallCnt, otherCnt int64
 
 	collectionID2SegStats := make(map[int64]*segStats)
-	collectionID2Segments := lo.GroupBy(segments, func(s *models.Segment) int64 {
+	collectionID2Segments := lo.GroupBy(rs.segments, func(s *models.Segment) int64 {
 		return s.CollectionID
 	})
+	// keep collection order of segments, which could be sorted
+	collectionIDs := lo.Uniq(lo.Map(rs.segments, func(s *models.Segment, _ int) int64 {
+		return s.CollectionID
+	}))
 
-	for collectionID, segs := range collectionID2Segments {
-		fmt.Printf("===============================CollectionID: %d===========================\n", collectionID)
+	for _, collectionID := range collectionIDs {
+		segs := collectionID2Segments[collectionID]
+		fmt.Fprintf(w, "===============================Collection: %s===========================\n",
+			common.CollectionLabel(rs.names, collectionID))
 		collectionID2SegStats[collectionID] = &segStats{
 			binlogLogSize: make(map[int64]int64),
 			binlogMemSize: make(map[int64]int64),
@@ -96,11 +147,11 @@ func (c *ComponentShow) SegmentCommand(ctx context.Context, p *SegmentParam) err
 				dropped++
 			}
 
-			switch p.Format {
+			switch rs.format {
 			case "table":
-				PrintSegmentInfo(info, p.Detail)
+				printSegmentInfo(w, info, rs.detail)
 			case "line":
-				fmt.Printf("SegmentID: %d PartitionID: %d State: %s, Level: %s, Row Count:%d,  StorageVersion:%d, IsSorted: %v \n",
+				fmt.Fprintf(w, "SegmentID: %d PartitionID: %d State: %s, Level: %s, Row Count:%d,  StorageVersion:%d, IsSorted: %v \n",
 					info.ID, info.PartitionID, info.State.String(), info.Level.String(), info.NumOfRows, info.StorageVersion, info.IsSorted)
 			case "statistics":
 				if info.State != commonpb.SegmentState_Dropped {
@@ -124,28 +175,24 @@ func (c *ComponentShow) SegmentCommand(ctx context.Context, p *SegmentParam) err
 						}
 					}
 				}
-			default:
-				err := fmt.Errorf("unsupport format:%s", p.Format)
-				return err
 			}
 		}
-		if p.Format == "statistics" {
-			outputStats("Collection", collectionID2SegStats[collectionID])
+		if rs.format == "statistics" {
+			outputStats(w, "Collection", collectionID2SegStats[collectionID])
 		}
-		fmt.Printf("\n")
+		fmt.Fprintf(w, "\n")
 	}
 
-	if p.Format == "statistics" {
-		outputStats("Total", lo.Values(collectionID2SegStats)...)
+	if rs.format == "statistics" {
+		outputStats(w, "Total", lo.Values(collectionID2SegStats)...)
 	}
 
-	fmt.Printf("--- Growing: %d, Sealed: %d, Flushed: %d, Dropped: %d\n", growing, sealed, flushed, dropped)
-	fmt.Printf("--- Small Segments: %d, row count: %d\t Other Segments: %d, row count: %d\n", small, smallCnt, other, otherCnt)
-	fmt.Printf("--- Total Segments: %d, row count: %d\n", healthy, totalRC)
-	return nil
+	fmt.Fprintf(w, "--- Growing: %d, Sealed: %d, Flushed: %d, Dropped: %d\n", growing, sealed, flushed, dropped)
+	fmt.Fprintf(w, "--- Small Segments: %d, row count: %d\t Other Segments: %d, row count: %d\n", small, smallCnt, other, otherCnt)
+	fmt.Fprintf(w, "--- Total Segments: %d, row count: %d\n", healthy, totalRC)
 }
 
-func outputStats(scope string, stats ...*segStats) {
+func outputStats(w io.Writer, scope string, stats ...*segStats) {
 	var totalBinlogLogSize int64
 	var totalBinlogMemSize int64
 	var totalDeltaLogSize int64
@@ -157,7 +204,7 @@ func outputStats(scope string, stats ...*segStats) {
 		for fieldID, logSize := range s.binlogLogSize {
 			memSize := s.binlogMemSize[fieldID]
 			if scope != "Total" {
-				fmt.Printf("field[%d] binlog size: %s, mem size: %s\n", fieldID, hrSize(logSize), hrSize(memSize))
+				fmt.Fprintf(w, "field[%d] binlog size: %s, mem size: %s\n", fieldID, hrSize(logSize), hrSize(memSize))
 			}
 			totalBinlogLogSize += logSize
 			totalBinlogMemSize += memSize
@@ -170,9 +217,9 @@ func outputStats(scope string, stats ...*segStats) {
 		totalStatsMemSize += s.statsMemSize
 	}
 
-	fmt.Printf("--- %s binlog size: %s, mem size: %s\n", scope, hrSize(totalBinlogLogSize), hrSize(totalBinlogMemSize))
-	fmt.Printf("--- %s deltalog size: %s, mem size: %s, delta entry number: %d\n", scope, hrSize(totalDeltaLogSize), hrSize(totalDeltaMemSize), totalDeltaEntryNum)
-	fmt.Printf("--- %s statslog size: %s, mem size: %s\n", scope, hrSize(totalStatsLogSize), hrSize(totalStatsMemSize))
+	fmt.Fprintf(w, "--- %s binlog size: %s, mem size: %s\n", scope, hrSize(totalBinlogLogSize), hrSize(totalBinlogMemSize))
+	fmt.Fprintf(w, "--- %s deltalog size: %s, mem size: %s, delta entry number: %d\n", scope, hrSize(totalDeltaLogSize), hrSize(totalDeltaMemSize), totalDeltaEntryNum)
+	fmt.Fprintf(w, "--- %s statslog size: %s, mem size: %s\n", scope, hrSize(totalStatsLogSize), hrSize(totalStatsMemSize))
 }
 
 func hrSize(size int64) string {
@@ -192,53 +239,57 @@ const (
 
 // PrintSegmentInfo prints segments info
 func PrintSegmentInfo(info *models.Segment, detailBinlog bool) {
-	fmt.Println("================================================================================")
-	fmt.Printf("Segment ID: %d\n", info.ID)
-	fmt.Printf("Segment State: %v", info.State)
+	printSegmentInfo(os.Stdout, info, detailBinlog)
+}
+
+func printSegmentInfo(w io.Writer, info *models.Segment, detailBinlog bool) {
+	fmt.Fprintln(w, "================================================================================")
+	fmt.Fprintf(w, "Segment ID: %d\n", info.ID)
+	fmt.Fprintf(w, "Segment State: %v", info.State)
 	if info.State == commonpb.SegmentState_Dropped {
 		dropTime := time.Unix(0, int64(info.DroppedAt))
-		fmt.Printf("\tDropped Time: %s", dropTime.Format(tsPrintFormat))
+		fmt.Fprintf(w, "\tDropped Time: %s", dropTime.Format(tsPrintFormat))
 	}
-	fmt.Printf("\tSegment Level: %s", info.Level.String())
-	fmt.Printf("\tStorage Version: %d", info.GetStorageVersion())
-	fmt.Println()
-	fmt.Printf("Collection ID: %d\t\tPartitionID: %d\n", info.CollectionID, info.PartitionID)
-	fmt.Printf("Insert Channel:%s\n", info.InsertChannel)
-	fmt.Printf("Num of Rows: %d\t\tMax Row Num: %d\n", info.NumOfRows, info.MaxRowNum)
+	fmt.Fprintf(w, "\tSegment Level: %s", info.Level.String())
+	fmt.Fprintf(w, "\tStorage Version: %d", info.GetStorageVersion())
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Collection ID: %d\t\tPartitionID: %d\n", info.CollectionID, info.PartitionID)
+	fmt.Fprintf(w, "Insert Channel:%s\n", info.InsertChannel)
+	fmt.Fprintf(w, "Num of Rows: %d\t\tMax Row Num: %d\n", info.NumOfRows, info.MaxRowNum)
 	lastExpireTime, _ := utils.ParseTS(info.LastExpireTime)
-	fmt.Printf("Last Expire Time: %s\n", lastExpireTime.Format(tsPrintFormat))
-	fmt.Printf("Compact from %v \n", info.CompactionFrom)
+	fmt.Fprintf(w, "Last Expire Time: %s\n", lastExpireTime.Format(tsPrintFormat))
+	fmt.Fprintf(w, "Compact from %v \n", info.CompactionFrom)
 	if info.StartPosition != nil {
 		startTime, _ := utils.ParseTS(info.GetStartPosition().GetTimestamp())
-		fmt.Printf("Start Position ID: %v, time: %s, channel name %s\n", info.GetStartPosition().MsgID, startTime.Format(tsPrintFormat), info.GetStartPosition().GetChannelName())
+		fmt.Fprintf(w, "Start Position ID: %v, time: %s, channel name %s\n", info.GetStartPosition().MsgID, startTime.Format(tsPrintFormat), info.GetStartPosition().GetChannelName())
 	} else {
-		fmt.Println("Start Position: nil")
+		fmt.Fprintln(w, "Start Position: nil")
 	}
 	if info.DmlPosition != nil {
 		dmlTime, _ := utils.ParseTS(info.DmlPosition.Timestamp)
-		fmt.Printf("Dml Position ID: %v, time: %s, channel name: %s\n", info.DmlPosition.MsgID, dmlTime.Format(tsPrintFormat), info.GetDmlPosition().GetChannelName())
+		fmt.Fprintf(w, "Dml Position ID: %v, time: %s, channel name: %s\n", info.DmlPosition.MsgID, dmlTime.Format(tsPrintFormat), info.GetDmlPosition().GetChannelName())
 	} else {
-		fmt.Println("Dml Position: nil")
+		fmt.Fprintln(w, "Dml Position: nil")
 	}
-	fmt.Printf("Binlog Nums %d\tStatsLog Nums: %d\tDeltaLog Nums:%d\n",
+	fmt.Fprintf(w, "Binlog Nums %d\tStatsLog Nums: %d\tDeltaLog Nums:%d\n",
 		countBinlogNum(info.GetBinlogs()), countBinlogNum(info.GetStatslogs()), countBinlogNum(info.GetDeltalogs()))
 
 	if detailBinlog {
 		var binlogSize int64
 		var insertmemSize int64
-		fmt.Println("**************************************")
-		fmt.Println("Binlogs:")
+		fmt.Fprintln(w, "**************************************")
+		fmt.Fprintln(w, "Binlogs:")
 		sort.Slice(info.GetBinlogs(), func(i, j int) bool {
 			return info.GetBinlogs()[i].FieldID < info.GetBinlogs()[j].FieldID
 		})
 		for _, log := range info.GetBinlogs() {
 			var fieldLogSize int64
-			fmt.Printf("Field %d:\n", log.FieldID)
+			fmt.Fprintf(w, "Field %d:\n", log.FieldID)
 			for _, binlog := range log.Binlogs {
-				fmt.Printf("Path: %s\n", binlog.LogPath)
+				fmt.Fprintf(w, "Path: %s\n", binlog.LogPath)
 				tf, _ := utils.ParseTS(binlog.TimestampFrom)
 				tt, _ := utils.ParseTS(binlog.TimestampTo)
-				fmt.Printf("LogID: %d \t Mem Size: %d \t Log Size: %d \t Entry Num: %d\t TimeRange:%s-%s\n",
+				fmt.Fprintf(w, "LogID: %d \t Mem Size: %d \t Log Size: %d \t Entry Num: %d\t TimeRange:%s-%s\n",
 					binlog.LogID, binlog.MemSize,
 					binlog.LogSize, binlog.EntriesNum,
 					tf.Format(tsPrintFormat), tt.Format(tsPrintFormat))
@@ -246,48 +297,48 @@ func PrintSegmentInfo(info *models.Segment, detailBinlog bool) {
 				insertmemSize += binlog.MemSize
 				fieldLogSize += binlog.LogSize
 			}
-			fmt.Println("--- Field Log Size:", hrSize(fieldLogSize))
+			fmt.Fprintln(w, "--- Field Log Size:", hrSize(fieldLogSize))
 		}
-		fmt.Println("=== Segment Total Binlog Size: ", hrSize(binlogSize))
-		fmt.Println("=== Segment Total Binlog Mem Size: ", hrSize(insertmemSize))
+		fmt.Fprintln(w, "=== Segment Total Binlog Size: ", hrSize(binlogSize))
+		fmt.Fprintln(w, "=== Segment Total Binlog Mem Size: ", hrSize(insertmemSize))
 
-		fmt.Println("**************************************")
-		fmt.Println("Statslogs:")
+		fmt.Fprintln(w, "**************************************")
+		fmt.Fprintln(w, "Statslogs:")
 		sort.Slice(info.GetStatslogs(), func(i, j int) bool {
 			return info.GetStatslogs()[i].FieldID < info.GetStatslogs()[j].FieldID
 		})
 		var statsLogSize int64
 		for _, log := range info.GetStatslogs() {
-			fmt.Printf("Field %d:\n", log.FieldID)
+			fmt.Fprintf(w, "Field %d:\n", log.FieldID)
 			for _, binlog := range log.Binlogs {
-				fmt.Printf("Path: %s\n", binlog.LogPath)
+				fmt.Fprintf(w, "Path: %s\n", binlog.LogPath)
 				tf, _ := utils.ParseTS(binlog.TimestampFrom)
 				tt, _ := utils.ParseTS(binlog.TimestampTo)
-				fmt.Printf("LogID: %d \t Log Size: %d \t Entry Num: %d\t TimeRange:%s-%s\n",
+				fmt.Fprintf(w, "LogID: %d \t Log Size: %d \t Entry Num: %d\t TimeRange:%s-%s\n",
 					binlog.LogID, binlog.LogSize, binlog.EntriesNum,
 					tf.Format(tsPrintFormat), tt.Format(tsPrintFormat))
 				statsLogSize += binlog.LogSize
 			}
 		}
-		fmt.Println("=== Segment Total Statslog Size: ", hrSize(statsLogSize))
+		fmt.Fprintln(w, "=== Segment Total Statslog Size: ", hrSize(statsLogSize))
 
-		fmt.Println("**************************************")
-		fmt.Println("Delta Logs:")
+		fmt.Fprintln(w, "**************************************")
+		fmt.Fprintln(w, "Delta Logs:")
 		var deltaLogSize int64
 		var memSize int64
 		for _, log := range info.GetDeltalogs() {
 			for _, l := range log.Binlogs {
-				fmt.Printf("Entries: %d From: %v - To: %v\n", l.EntriesNum, l.TimestampFrom, l.TimestampTo)
-				fmt.Printf("LogID: %d, Path: %v LogSize: %s, MemSize: %s\n", l.LogID, l.LogPath, hrSize(l.LogSize), hrSize(l.MemSize))
+				fmt.Fprintf(w, "Entries: %d From: %v - To: %v\n", l.EntriesNum, l.TimestampFrom, l.TimestampTo)
+				fmt.Fprintf(w, "LogID: %d, Path: %v LogSize: %s, MemSize: %s\n", l.LogID, l.LogPath, hrSize(l.LogSize), hrSize(l.MemSize))
 				deltaLogSize += l.LogSize
 				memSize += l.MemSize
 			}
 		}
-		fmt.Println("=== Segment Total Deltalog Size: ", hrSize(deltaLogSize))
-		fmt.Println("=== Segment Total Deltalog Mem Size: ", hrSize(memSize))
+		fmt.Fprintln(w, "=== Segment Total Deltalog Size: ", hrSize(deltaLogSize))
+		fmt.Fprintln(w, "=== Segment Total Deltalog Mem Size: ", hrSize(memSize))
 	}
 
-	fmt.Println("================================================================================")
+	fmt.Fprintln(w, "================================================================================")
 }
 
 func countBinlogNum(fbl []*models.FieldBinlog) int {
@@ -13,8 +13,8 @@ import (
 )
 
 type SegmentIndexParam struct {
-	framework.ParamBase `use:"show segment-index" desc:"display segment index information" alias:"segments-index,segment-indexes,segments-indexes"`
-	CollectionID        int64 `name:"collection" default:"0" desc:"collection id to filter with"`
+	framework.ParamBase `use:"show segment-index" desc:"display segment index information" alias:"segments-index,segment-indexes,segments-indexes" readonly:"true"`
+	CollectionID        int64 `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter with"`
 	SegmentID           int64 `name:"segment" default:"0" desc:"segment id to filter with"`
 	FieldID             int64 `name:"field" default:"0" desc:"field id to filter with"`
 	IndexID             int64 `name:"indexID" default:"0" desc:"index id to filter with"`
@@ -58,13 +58,15 @@ func (c *ComponentShow) SegmentIndexCommand(ctx context.Context, p *SegmentIndex
 		return info.GetProto().GetIndexInfo().GetIndexID(), info
 	})
 
+	names := common.CollectionNames(ctx, c.client, c.metaPath)
 	count := make(map[string]int)
 
 	for _, segment := range segments {
 		if segment.State != commonpb.SegmentState_Flushed && segment.GetState() != commonpb.SegmentState_Flushing {
 			continue
 		}
-		fmt.Printf("SegmentID: %d\t State: %s\n", segment.GetID(), segment.GetState().String())
+		fmt.Printf("SegmentID: %d\t Collection: %s\t State: %s\n", segment.GetID(),
+			common.CollectionLabel(names, segment.GetCollectionID()), segment.GetState().String())
 		segIdxs, ok := seg2Idx[segment.GetID()]
 		if !ok {
 			continue
@@ -0,0 +1,172 @@
+package show
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/utils"
+)
+
+type SegmentLineageParam struct {
+	framework.ParamBase `use:"segment lineage" desc:"display compaction lineage of segment, ancestors and descendants" readonly:"true"`
+	SegmentID           int64  `name:"segment" default:"0" desc:"segment id to trace"`
+	Format              string `name:"format" default:"tree" desc:"output format, [tree, dot, mermaid]"`
+	Output              string `name:"output" default:"" desc:"file path to write output, print to stdout if empty"`
+}
+
+// SegmentLineageCommand implements `segment lineage` command.
+func (c *ComponentShow) SegmentLineageCommand(ctx context.Context, p *SegmentLineageParam) (*SegmentLineage, error) {
+	if p.SegmentID == 0 {
+		return nil, errors.New("segment id not provided")
+	}
+	switch p.Format {
+	case "tree", "dot", "mermaid":
+	default:
+		return nil, errors.Newf("unknown lineage format: %s", p.Format)
+	}
+	lineage, err := common.BuildSegmentLineage(ctx, c.client, c.metaPath, p.SegmentID)
+	if err != nil {
+		return nil, err
+	}
+	rs := &SegmentLineage{lineage: lineage, format: p.Format}
+	if p.Output != "" {
+		if err := os.WriteFile(p.Output, []byte(rs.PrintAs(framework.FormatPlain)), 0o644); err != nil {
+			return nil, err
+		}
+		fmt.Printf("segment lineage written to %s\n", p.Output)
+		return nil, nil
+	}
+	return rs, nil
+}
+
+type SegmentLineage struct {
+	lineage *models.SegmentLineage
+	format  string
+}
+
+func (rs *SegmentLineage) Entities() any {
+	return rs.lineage
+}
+
+func (rs *SegmentLineage) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatDefault, framework.FormatPlain:
+		sb := &strings.Builder{}
+		switch rs.format {
+		case "dot":
+			rs.printDot(sb)
+		case "mermaid":
+			rs.printMermaid(sb)
+		default:
+			rs.printTree(sb)
+		}
+		return sb.String()
+	default:
+	}
+	return ""
+}
+
+func (rs *SegmentLineage) printTree(sb *strings.Builder) {
+	l := rs.lineage
+	fmt.Fprintln(sb, "Ancestors:")
+	rs.printBranch(sb, l.Root, 0, l.Parents, func(e *models.SegmentLineageEdge) int64 { return e.From }, map[int64]bool{})
+	fmt.Fprintln(sb, "Descendants:")
+	rs.printBranch(sb, l.Root, 0, l.Children, func(e *models.SegmentLineageEdge) int64 { return e.To }, map[int64]bool{})
+	fmt.Fprintf(sb, "--- Total segments in lineage: %d, compaction relations: %d\n", len(l.Nodes), len(l.Edges))
+}
+
+func (rs *SegmentLineage) printBranch(sb *strings.Builder, id int64, level int, next func(int64) []*models.SegmentLineageEdge, pick func(*models.SegmentLineageEdge) int64, visited map[int64]bool) {
+	node := rs.lineage.Nodes[id]
+	fmt.Fprintf(sb, "%s%s\n", strings.Repeat("    ", level), lineageNodeDesc(node))
+	if visited[id] {
+		return
+	}
+	visited[id] = true
+	edges := next(id)
+	sort.Slice(edges, func(i, j int) bool { return pick(edges[i]) < pick(edges[j]) })
+	for _, edge := range edges {
+		if edge.PlanID > 0 {
+			fmt.Fprintf(sb, "%s  |- plan %d(%s)\n", strings.Repeat("    ", level), edge.PlanID, edge.CompactionType)
+		}
+		rs.printBranch(sb, pick(edge), level+1, next, pick, visited)
+	}
+}
+
+func lineageNodeDesc(node *models.SegmentLineageNode) string {
+	if node.Missing {
+		return fmt.Sprintf("Segment %d [missing in meta]", node.SegmentID)
+	}
+	desc := fmt.Sprintf("Segment %d [%s] %s rows: %d", node.SegmentID, node.Level, node.State, node.NumOfRows)
+	if node.DmlTs > 0 {
+		t, _ := utils.ParseTS(node.DmlTs)
+		desc += fmt.Sprintf(" dml position: %s", t.Format("2006-01-02 15:04:05"))
+	}
+	if node.DroppedAt > 0 {
+		desc += fmt.Sprintf(" dropped at: %s", time.Unix(0, int64(node.DroppedAt)).Format("2006-01-02 15:04:05"))
+	}
+	return desc
+}
+
+func (rs *SegmentLineage) sortedNodes() []*models.SegmentLineageNode {
+	nodes := make([]*models.SegmentLineageNode, 0, len(rs.lineage.Nodes))
+	for _, node := range rs.lineage.Nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].SegmentID < nodes[j].SegmentID })
+	return nodes
+}
+
+func lineageNodeLabel(node *models.SegmentLineageNode) string {
+	if node.Missing {
+		return fmt.Sprintf("%d\\nmissing", node.SegmentID)
+	}
+	return fmt.Sprintf("%d\\n%s %s\\nrows: %d", node.SegmentID, node.Level, node.State, node.NumOfRows)
+}
+
+func (rs *SegmentLineage) printDot(sb *strings.Builder) {
+	fmt.Fprintln(sb, "digraph segment_lineage {")
+	fmt.Fprintln(sb, "  node [shape=box];")
+	for _, node := range rs.sortedNodes() {
+		attrs := fmt.Sprintf("label=\"%s\"", lineageNodeLabel(node))
+		switch {
+		case node.SegmentID == rs.lineage.Root:
+			attrs += ", style=bold"
+		case node.Missing:
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(sb, "  \"%d\" [%s];\n", node.SegmentID, attrs)
+	}
+	for _, edge := range rs.lineage.Edges {
+		if edge.PlanID > 0 {
+			fmt.Fprintf(sb, "  \"%d\" -> \"%d\" [label=\"plan %d\"];\n", edge.From, edge.To, edge.PlanID)
+		} else {
+			fmt.Fprintf(sb, "  \"%d\" -> \"%d\";\n", edge.From, edge.To)
+		}
+	}
+	fmt.Fprintln(sb, "}")
+}
+
+func (rs *SegmentLineage) printMermaid(sb *strings.Builder) {
+	fmt.Fprintln(sb, "graph TD")
+	for _, node := range rs.sortedNodes() {
+		label := strings.ReplaceAll(lineageNodeLabel(node), "\\n", "<br/>")
+		fmt.Fprintf(sb, "  s%d[\"%s\"]\n", node.SegmentID, label)
+	}
+	for _, edge := range rs.lineage.Edges {
+		if edge.PlanID > 0 {
+			fmt.Fprintf(sb, "  s%d -->|plan %d| s%d\n", edge.From, edge.PlanID, edge.To)
+		} else {
+			fmt.Fprintf(sb, "  s%d --> s%d\n", edge.From, edge.To)
+		}
+	}
+	fmt.Fprintf(sb, "  style s%d stroke-width:3px\n", rs.lineage.Root)
+}
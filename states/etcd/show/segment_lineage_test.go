@@ -0,0 +1,83 @@
+package show
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+)
+
+func TestSegmentLineage(t *testing.T) {
+	ctx := context.Background()
+	c := fakecluster.New(t)
+	c.AddCollection(fakecluster.NewCollection(100, "coll").WithPrimaryKey(100, "pk").WithPartition(101, "_default")).
+		AddSegment(fakecluster.NewSegment(1001, 100, 101).WithRows(100).WithState(commonpb.SegmentState_Dropped)).
+		AddSegment(fakecluster.NewSegment(1002, 100, 101).WithRows(200).WithState(commonpb.SegmentState_Dropped)).
+		AddSegment(fakecluster.NewSegment(1003, 100, 101).WithRows(300).WithState(commonpb.SegmentState_Dropped).WithCompactionFrom(1001, 1002)).
+		AddSegment(fakecluster.NewSegment(1005, 100, 101).WithRows(350).WithCompactionFrom(1003, 1004)).
+		AddSegment(fakecluster.NewSegment(1006, 100, 101).WithRows(10))
+	// segment 1004 already removed from meta, only referenced by compaction task
+	c.PutProto(path.Join(common.DCPrefix, common.CompactionTaskPrefix, "100/77"), &datapb.CompactionTask{
+		PlanID:         77,
+		CollectionID:   100,
+		Type:           datapb.CompactionType_MixCompaction,
+		InputSegments:  []int64{1003, 1004},
+		ResultSegments: []int64{1005},
+	})
+
+	show := NewComponent(c.KV(), nil, fakecluster.RootPath, fakecluster.MetaPath)
+	_, err := show.SegmentLineageCommand(ctx, &SegmentLineageParam{})
+	assert.Error(t, err)
+	_, err = show.SegmentLineageCommand(ctx, &SegmentLineageParam{SegmentID: 1003, Format: "svg"})
+	assert.Error(t, err)
+	_, err = show.SegmentLineageCommand(ctx, &SegmentLineageParam{SegmentID: 9999, Format: "tree"})
+	assert.Error(t, err)
+
+	rs, err := show.SegmentLineageCommand(ctx, &SegmentLineageParam{SegmentID: 1003, Format: "tree"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int64{1001, 1002, 1003, 1005}, lineageNodeIDs(rs))
+	require.Len(t, rs.lineage.Edges, 3)
+	assert.EqualValues(t, 77, rs.lineage.Edges[2].PlanID)
+	assert.Equal(t, "MixCompaction", rs.lineage.Edges[2].CompactionType)
+	tree := rs.PrintAs(framework.FormatDefault)
+	assert.Contains(t, tree, "    Segment 1001 [L1] Dropped rows: 100")
+	assert.Contains(t, tree, "  |- plan 77(MixCompaction)")
+	assert.Contains(t, tree, "--- Total segments in lineage: 4, compaction relations: 3")
+
+	// root segment missing in meta, collection located by compaction task
+	rs, err = show.SegmentLineageCommand(ctx, &SegmentLineageParam{SegmentID: 1004, Format: "dot"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int64{1004, 1005}, lineageNodeIDs(rs))
+	dot := rs.PrintAs(framework.FormatDefault)
+	assert.Contains(t, dot, "\"1004\" [label=\"1004\\nmissing\", style=bold];")
+	assert.Contains(t, dot, "\"1004\" -> \"1005\" [label=\"plan 77\"];")
+
+	output := filepath.Join(t.TempDir(), "lineage.mmd")
+	rs, err = show.SegmentLineageCommand(ctx, &SegmentLineageParam{SegmentID: 1005, Format: "mermaid", Output: output})
+	require.NoError(t, err)
+	assert.Nil(t, rs)
+	bs, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Contains(t, string(bs), "s1003 -->|plan 77| s1005")
+	assert.Contains(t, string(bs), "s1001 --> s1003")
+	assert.Contains(t, string(bs), "style s1005 stroke-width:3px")
+	assert.NotContains(t, string(bs), "s1006")
+}
+
+func lineageNodeIDs(rs *SegmentLineage) []int64 {
+	var ids []int64
+	for id := range rs.lineage.Nodes {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,38 @@
+package show
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+)
+
+func TestShowSegment(t *testing.T) {
+	ctx := context.Background()
+	c := fakecluster.New(t)
+	c.AddCollection(fakecluster.NewCollection(100, "coll").WithPrimaryKey(100, "pk").WithPartition(101, "_default")).
+		AddSegment(fakecluster.NewSegment(1001, 100, 101).WithRows(3000).WithLevel(datapb.SegmentLevel_L1)).
+		AddSegment(fakecluster.NewSegment(1002, 100, 101).WithRows(500).WithLevel(datapb.SegmentLevel_L1)).
+		AddSegment(fakecluster.NewSegment(1003, 100, 101).WithRows(100).WithLevel(datapb.SegmentLevel_L0)).
+		AddSegment(fakecluster.NewSegment(1004, 100, 101).WithRows(800).WithState(commonpb.SegmentState_Dropped))
+
+	show := NewComponent(c.KV(), nil, fakecluster.RootPath, fakecluster.MetaPath)
+	rs, err := show.SegmentCommand(ctx, &SegmentParam{Format: "line", State: "Flushed"})
+	require.NoError(t, err)
+	assert.Len(t, rs.Entities(), 3)
+	assert.Contains(t, rs.PrintAs(framework.FormatDefault), "--- Total Segments: 3, row count: 3600")
+
+	_, err = show.SegmentCommand(ctx, &SegmentParam{Format: "unknown"})
+	assert.Error(t, err)
+
+	// entities could be filtered with framework query options
+	rs.SetEntities(rs.Entities().([]*models.Segment)[:1])
+	assert.Contains(t, rs.PrintAs(framework.FormatDefault), "--- Total Segments: 1, row count: 3000")
+}
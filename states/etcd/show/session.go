@@ -15,7 +15,7 @@ import (
 )
 
 type SessionParam struct {
-	framework.ParamBase `use:"show session" desc:"list online milvus components" alias:"sessions"`
+	framework.ParamBase `use:"show session" desc:"list online milvus components" alias:"sessions" readonly:"true"`
 }
 
 // SessionCommand returns show session command.
@@ -0,0 +1,82 @@
+package show
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+)
+
+type StatsTaskParam struct {
+	framework.ParamBase `use:"show stats-task" desc:"display stats task(sort/text index/bm25 stats) meta from DataCoord" alias:"stats-tasks" readonly:"true"`
+	CollectionID        int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id to filter with"`
+	SegmentID           int64  `name:"segment" default:"0" desc:"segment id to filter with"`
+	TaskID              int64  `name:"task" default:"0" desc:"task id to filter with"`
+	NodeID              int64  `name:"node" default:"0" desc:"worker node id to filter with"`
+	State               string `name:"state" default:"" desc:"task state to filter with, [JobStateInit, JobStateInProgress, JobStateFinished, JobStateFailed, JobStateRetry]"`
+	SubJobType          string `name:"type" default:"" desc:"sub job type to filter with, [Sort, TextIndexJob, BM25Job, JsonKeyIndexJob]"`
+}
+
+// StatsTaskCommand implements `show stats-task` command.
+func (c *ComponentShow) StatsTaskCommand(ctx context.Context, p *StatsTaskParam) (*StatsTasks, error) {
+	tasks, err := common.ListStatsTask(ctx, c.client, c.metaPath, func(task *models.StatsTask) bool {
+		t := task.GetProto()
+		return (p.CollectionID == 0 || t.GetCollectionID() == p.CollectionID) &&
+			(p.SegmentID == 0 || t.GetSegmentID() == p.SegmentID || t.GetTargetSegmentID() == p.SegmentID) &&
+			(p.TaskID == 0 || t.GetTaskID() == p.TaskID) &&
+			(p.NodeID == 0 || t.GetNodeID() == p.NodeID) &&
+			(p.State == "" || strings.EqualFold(t.GetState().String(), p.State)) &&
+			(p.SubJobType == "" || strings.EqualFold(t.GetSubJobType().String(), p.SubJobType))
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].GetProto().GetTaskID() < tasks[j].GetProto().GetTaskID()
+	})
+	return framework.NewListResult[StatsTasks](tasks), nil
+}
+
+type StatsTasks struct {
+	framework.ListResultSet[*models.StatsTask]
+}
+
+func (rs *StatsTasks) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatDefault, framework.FormatPlain:
+		sb := &strings.Builder{}
+		for _, task := range rs.Data {
+			t := task.GetProto()
+			fmt.Fprintf(sb, "TaskID: %d\tType: %s\tState: %s\tCollectionID: %d\tPartitionID: %d\tSegmentID: %d -> %d\tChannel: %s\tNodeID: %d\tVersion: %d\tCanRecycle: %t\n",
+				t.GetTaskID(), t.GetSubJobType().String(), t.GetState().String(), t.GetCollectionID(), t.GetPartitionID(),
+				t.GetSegmentID(), t.GetTargetSegmentID(), t.GetInsertChannel(), t.GetNodeID(), t.GetVersion(), t.GetCanRecycle())
+			if t.GetFailReason() != "" {
+				fmt.Fprintf(sb, "\tFail Reason: %s\n", t.GetFailReason())
+			}
+		}
+		printStateCount(sb, "stats task", lo.Map(rs.Data, func(task *models.StatsTask, _ int) string {
+			return task.GetProto().GetState().String()
+		}))
+		return sb.String()
+	default:
+	}
+	return ""
+}
+
+// printStateCount prints total number and count per state.
+func printStateCount(sb *strings.Builder, name string, states []string) {
+	counts := lo.MapValues(lo.GroupBy(states, func(s string) string { return s }), func(s []string, _ string) int { return len(s) })
+	fmt.Fprintf(sb, "--- Total %s(s): %d", name, len(states))
+	keys := lo.Keys(counts)
+	sort.Strings(keys)
+	for _, state := range keys {
+		fmt.Fprintf(sb, " %s:%d", state, counts[state])
+	}
+	fmt.Fprintln(sb)
+}
@@ -3,9 +3,11 @@ package show
 import (
 	"context"
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
 
 	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
@@ -13,23 +15,38 @@ import (
 )
 
 type UserParam struct {
-	framework.ParamBase `use:"show user" desc:"display user info from rootcoord meta"`
-	// DatabaseName        string `name:"name" default:"" desc:"database name to filter with"`
+	framework.ParamBase `use:"show user" desc:"display user info from rootcoord meta" readonly:"true"`
+	WithRoles           bool `name:"with-roles" default:"false" desc:"display roles bound to each user"`
 }
 
-// DatabaseCommand returns show database comand.
+// UserCommand returns show user comand.
 func (c *ComponentShow) UserCommand(ctx context.Context, p *UserParam) (*Users, error) {
-	users, err := common.ListUsers(ctx, c.client, c.basePath)
+	users, err := common.ListUsers(ctx, c.client, c.metaPath)
 	if err != nil {
-		fmt.Println("failed to list database info", err.Error())
-		return nil, errors.Wrap(err, "failed to list database info")
+		fmt.Println("failed to list user info", err.Error())
+		return nil, errors.Wrap(err, "failed to list user info")
 	}
 
-	return framework.NewListResult[Users](users), nil
+	rs := framework.NewListResult[Users](users)
+	if p.WithRoles {
+		bindings, err := common.ListUserRoles(ctx, c.client, c.metaPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list user-role mapping")
+		}
+		rs.roles = lo.MapValues(lo.GroupBy(bindings, func(b *models.UserRole) string {
+			return path.Join(b.Tenant, b.Username)
+		}), func(bindings []*models.UserRole, _ string) []string {
+			return lo.Map(bindings, func(b *models.UserRole, _ int) string { return b.RoleName })
+		})
+	}
+
+	return rs, nil
 }
 
 type Users struct {
 	framework.ListResultSet[*models.UserInfo]
+	// roles is the user(with tenant) to role names mapping, nil if not requested
+	roles map[string][]string
 }
 
 func (rs *Users) PrintAs(format framework.Format) string {
@@ -37,8 +54,11 @@ func (rs *Users) PrintAs(format framework.Format) string {
 	case framework.FormatDefault, framework.FormatPlain:
 		sb := &strings.Builder{}
 		for _, user := range rs.Data {
-			// rs.printDatabaseInfo(sb, database)
-			sb.WriteString(fmt.Sprintf("Username: %s Tenant:%s\n", user.Username, user.Tenant))
+			fmt.Fprintf(sb, "Username: %s Tenant:%s", user.Username, user.Tenant)
+			if rs.roles != nil {
+				fmt.Fprintf(sb, " Roles: %v", rs.roles[path.Join(user.Tenant, user.Username)])
+			}
+			sb.WriteString("\n")
 		}
 		fmt.Fprintf(sb, "--- Total Users(s): %d\n", len(rs.Data))
 		return sb.String()
@@ -45,19 +45,66 @@ func GetTiKVClient(cp *ConnectParams) (*txnkv.Client, error) {
 			conf.Security = config.NewSecurity(cp.TiKVTLSCACert, cp.TiKVTLSCert, cp.TiKVTLSKey, []string{})
 		}
 		config.UpdateGlobal(f)
-		return txnkv.NewClient([]string{cp.TiKVAddr})
+		return txnkv.NewClient(strings.Split(cp.TiKVAddr, ","))
 	}
-	return txnkv.NewClient([]string{cp.TiKVAddr})
+	return txnkv.NewClient(strings.Split(cp.TiKVAddr, ","))
 }
 
 func (app *ApplicationState) ConnectCommand(ctx context.Context, cp *ConnectParams) error {
+	var mc *configs.MilvusConfig
+	if cp.MilvusConfig != "" {
+		var err error
+		mc, err = configs.LoadMilvusConfig(cp.MilvusConfig)
+		if err != nil {
+			return errors.Wrap(err, "failed to load milvus config")
+		}
+		applyMilvusConfig(cp, mc)
+	}
 	if cp.UseTiKV {
-		return app.connectTiKV(ctx, cp)
+		return app.connectTiKV(ctx, cp, mc)
+	}
+	return app.connectEtcd(ctx, cp, mc)
+}
+
+// applyMilvusConfig fills connect params with milvus config items,
+// flags explicitly provided in command line take precedence over milvus config.
+func applyMilvusConfig(cp *ConnectParams, mc *configs.MilvusConfig) {
+	fillParam(cp, "use_tikv", &cp.UseTiKV, mc.UseTiKV())
+	if cp.UseTiKV {
+		fillParam(cp, "tikv", &cp.TiKVAddr, strings.Join(mc.TiKVEndpoints(), ","))
+		fillParam(cp, "rootPath", &cp.RootPath, mc.Get("tikv.rootPath"))
+		fillParam(cp, "metaPath", &cp.MetaPath, mc.Get("tikv.metaSubPath"))
+		fillParam(cp, "tikv_use_ssl", &cp.TiKVUseSSL, mc.GetBool("tikv.ssl.enabled"))
+		fillParam(cp, "tikvCert", &cp.TiKVTLSCert, mc.Get("tikv.ssl.tlsCert"))
+		fillParam(cp, "tikvKey", &cp.TiKVTLSKey, mc.Get("tikv.ssl.tlsKey"))
+		fillParam(cp, "tikvCACert", &cp.TiKVTLSCACert, mc.Get("tikv.ssl.tlsCACert"))
+	} else {
+		fillParam(cp, "etcd", &cp.EtcdAddr, strings.Join(mc.EtcdEndpoints(), ","))
+		fillParam(cp, "rootPath", &cp.RootPath, mc.Get("etcd.rootPath"))
+		fillParam(cp, "metaPath", &cp.MetaPath, mc.Get("etcd.metaSubPath"))
+		fillParam(cp, "enableTLS", &cp.EnableTLS, mc.GetBool("etcd.ssl.enabled"))
+		fillParam(cp, "etcdCert", &cp.ETCDPem, mc.Get("etcd.ssl.tlsCert"))
+		fillParam(cp, "etcdKey", &cp.ETCDKey, mc.Get("etcd.ssl.tlsKey"))
+		fillParam(cp, "rootCAPem", &cp.RootCA, mc.Get("etcd.ssl.tlsCACert"))
+		fillParam(cp, "min_version", &cp.TLSMinVersion, mc.Get("etcd.ssl.tlsMinVersion"))
+		if mc.GetBool("etcd.auth.enabled") {
+			fillParam(cp, "etcdUserName", &cp.ETCDUserName, mc.Get("etcd.auth.userName"))
+			fillParam(cp, "etcdPassword", &cp.ETCDPassword, mc.Get("etcd.auth.password"))
+		}
 	}
-	return app.connectEtcd(ctx, cp)
+	fmt.Printf("Using milvus config %s, metastore: %s, storage: %s, mq: %s(%s)\n",
+		cp.MilvusConfig, mc.Get("metastore.type"), mc.Get("common.storageType"), mc.MQType(), mc.MQAddress())
 }
 
-func (app *ApplicationState) connectTiKV(ctx context.Context, cp *ConnectParams) error {
+// fillParam sets connect param field with config value unless the flag is provided in command line.
+func fillParam[T any](cp *ConnectParams, flag string, field *T, value T) {
+	if cp.changed[flag] {
+		return
+	}
+	*field = value
+}
+
+func (app *ApplicationState) connectTiKV(ctx context.Context, cp *ConnectParams, mc *configs.MilvusConfig) error {
 	tikvCli, err := GetTiKVClient(cp)
 	if err != nil {
 		return err
@@ -85,7 +132,11 @@ func (app *ApplicationState) connectTiKV(ctx context.Context, cp *ConnectParams)
 		fmt.Println("Using meta path:", fmt.Sprintf("%s/%s/", cp.RootPath, metaPath))
 
 		// use rootPath as instanceName
-		app.SetTagNext(tikvTag, getInstanceState(app.core, cli, cp.RootPath, cp.MetaPath, kvState, app.config))
+		instance := getInstanceState(app.core, cli, cp.RootPath, cp.MetaPath, kvState, app.config, mc)
+		if cp.As != "" {
+			app.registerInstance(cp.As, tikvTag, instance)
+		}
+		app.SetTagNext(tikvTag, instance)
 	} else {
 		fmt.Println("using dry mode, ignore rootPath and metaPath")
 		// rootPath empty fall back to metastore connected state
@@ -105,23 +156,27 @@ func (app *ApplicationState) readEnv(cp *ConnectParams) {
 	}
 }
 
-func (app *ApplicationState) connectEtcd(ctx context.Context, cp *ConnectParams) error {
+func (app *ApplicationState) connectEtcd(ctx context.Context, cp *ConnectParams, mc *configs.MilvusConfig) error {
 	app.readEnv(cp)
 	tls, err := app.getTLSConfig(cp)
 	if err != nil {
 		return err
 	}
-	_, _, err = net.SplitHostPort(cp.EtcdAddr)
-	if err != nil {
-		if strings.Contains(err.Error(), "missing port in address") {
-			cp.EtcdAddr += ":2379"
-		} else {
-			return errors.Wrap(err, "invalid etcd address")
+	endpoints := strings.Split(cp.EtcdAddr, ",")
+	for i, endpoint := range endpoints {
+		_, _, err = net.SplitHostPort(endpoint)
+		if err != nil {
+			if strings.Contains(err.Error(), "missing port in address") {
+				endpoints[i] += ":2379"
+			} else {
+				return errors.Wrap(err, "invalid etcd address")
+			}
 		}
 	}
+	cp.EtcdAddr = strings.Join(endpoints, ",")
 
 	cfg := clientv3.Config{
-		Endpoints:   []string{cp.EtcdAddr},
+		Endpoints:   endpoints,
 		DialTimeout: time.Second * 10,
 		DialOptions: []grpc.DialOption{
 			grpc.WithBlock(),
@@ -178,7 +233,11 @@ func (app *ApplicationState) connectEtcd(ctx context.Context, cp *ConnectParams)
 		fmt.Println("Using meta path:", fmt.Sprintf("%s/%s/", cp.RootPath, metaPath))
 
 		// use rootPath as instanceName
-		app.SetTagNext(etcdTag, getInstanceState(app.core, cli, cp.RootPath, cp.MetaPath, kvState, app.config))
+		instance := getInstanceState(app.core, cli, cp.RootPath, cp.MetaPath, kvState, app.config, mc)
+		if cp.As != "" {
+			app.registerInstance(cp.As, etcdTag, instance)
+		}
+		app.SetTagNext(etcdTag, instance)
 	} else {
 		fmt.Println("using dry mode, ignore rootPath and metaPath")
 		// rootPath empty fall back to etcd connected state
@@ -189,7 +248,7 @@ func (app *ApplicationState) connectEtcd(ctx context.Context, cp *ConnectParams)
 
 type ConnectParams struct {
 	framework.ParamBase `use:"connect" desc:"Connect to metastore"`
-	EtcdAddr            string `name:"etcd" default:"127.0.0.1:2379" desc:"the etcd endpoint to connect"`
+	EtcdAddr            string `name:"etcd" default:"127.0.0.1:2379" desc:"the etcd endpoint to connect, comma separated for multiple endpoints"`
 	RootPath            string `name:"rootPath" default:"by-dev" desc:"meta root paht milvus is using"`
 	MetaPath            string `name:"metaPath" default:"meta" desc:"meta path prefix"`
 	Force               bool   `name:"force" default:"false" desc:"force connect ignoring ping Etcd & rootPath check"`
@@ -211,6 +270,17 @@ type ConnectParams struct {
 	TiKVAddr      string `name:"tikv" default:"127.0.0.1:2389" desc:"the tikv endpoint to connect"`
 
 	Auto bool `name:"auto" default:"false" desc:"auto detect rootPath if possible"`
+
+	MilvusConfig string `name:"milvus-config" default:"" desc:"milvus.yaml or helm values file to derive connection settings from"`
+	As           string `name:"as" default:"" desc:"name of the instance handle, instance root path is used if not provided, suffixed with #n if already in use"`
+
+	// flags explicitly provided in command line
+	changed map[string]bool
+}
+
+// RecordFlags implements framework.FlagRecorder.
+func (cp *ConnectParams) RecordFlags(changed map[string]bool) {
+	cp.changed = changed
 }
 
 func (app *ApplicationState) getTLSConfig(cp *ConnectParams) (*tls.Config, error) {
@@ -264,6 +334,13 @@ func (app *ApplicationState) getTLSConfig(cp *ConnectParams) (*tls.Config, error
 	}, nil
 }
 
+// metaStoreState is implemented by states connected to metastore,
+// which could switch to milvus instance with root path.
+type metaStoreState interface {
+	framework.State
+	useInstance(ctx context.Context, p *UseInstanceParam) (framework.State, error)
+}
+
 type kvConnectedState struct {
 	*framework.CmdState
 	client     kv.MetaKV
@@ -309,39 +386,24 @@ func (s *kvConnectedState) FindMilvusCommand(ctx context.Context, p *FindMilvusP
 	return nil
 }
 
-type UseParam struct {
-	framework.ParamBase `use:"use [instance-name]" desc:"use specified milvus instance"`
-	instanceName        string
-	Force               bool   `name:"force" default:"false" desc:"force connect ignoring ping result"`
-	MetaPath            string `name:"metaPath" default:"meta" desc:"meta path prefix"`
-}
-
-func (p *UseParam) ParseArgs(args []string) error {
-	if len(args) != 1 {
-		return errors.New("instance shall be provided")
-	}
-	p.instanceName = args[0]
-	return nil
-}
-
-func (s *kvConnectedState) UseCommand(ctx context.Context, p *UseParam) error {
-	err := pingMetaStore(ctx, s.client, p.instanceName, p.MetaPath)
+// useInstance connects to milvus instance with root path, invoked by `use` command.
+func (s *kvConnectedState) useInstance(ctx context.Context, p *UseInstanceParam) (framework.State, error) {
+	err := pingMetaStore(ctx, s.client, p.InstanceName, p.MetaPath)
 	if err != nil {
 		if errors.Is(err, ErrNotMilvsuRootPath) {
 			if !p.Force {
 				fmt.Printf("Connection established, but %s, please check your config or use Dry mode\n", err.Error())
-				return err
+				return nil, err
 			}
 		} else {
 			fmt.Println("failed to ping metastore", err.Error())
-			return err
+			return nil, err
 		}
 	}
 
-	fmt.Printf("Using meta path: %s/%s/\n", p.instanceName, p.MetaPath)
+	fmt.Printf("Using meta path: %s/%s/\n", p.InstanceName, p.MetaPath)
 
-	s.SetNext(etcdTag, getInstanceState(s.CmdState, s.client, p.instanceName, p.MetaPath, s, s.config))
-	return nil
+	return getInstanceState(s.CmdState, s.client, p.InstanceName, p.MetaPath, s, s.config, nil), nil
 }
 
 // findMilvusInstance iterate all possible rootPath
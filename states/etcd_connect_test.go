@@ -0,0 +1,34 @@
+package states
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/configs"
+)
+
+func TestApplyMilvusConfig(t *testing.T) {
+	mc, err := configs.ParseMilvusConfig([]byte(`
+etcd:
+  endpoints: etcd-0:2379
+  rootPath: prod
+  metaSubPath: meta-prod
+`), nil)
+	require.NoError(t, err)
+
+	cp := &ConnectParams{EtcdAddr: "127.0.0.1:2379", RootPath: "by-dev", MetaPath: "meta"}
+	applyMilvusConfig(cp, mc)
+	assert.Equal(t, "etcd-0:2379", cp.EtcdAddr)
+	assert.Equal(t, "prod", cp.RootPath)
+	assert.Equal(t, "meta-prod", cp.MetaPath)
+
+	// flags provided in command line take precedence
+	cp = &ConnectParams{EtcdAddr: "10.0.0.1:2379", RootPath: "custom", MetaPath: "meta"}
+	cp.RecordFlags(map[string]bool{"etcd": true, "rootPath": true})
+	applyMilvusConfig(cp, mc)
+	assert.Equal(t, "10.0.0.1:2379", cp.EtcdAddr)
+	assert.Equal(t, "custom", cp.RootPath)
+	assert.Equal(t, "meta-prod", cp.MetaPath)
+}
@@ -0,0 +1,441 @@
+package states
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/oss"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/storage"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+)
+
+const (
+	exportParquet = "parquet"
+	exportCSV     = "csv"
+	exportJSONL   = "jsonl"
+
+	// allPartitionsID is the partition id of L0 segments holding collection level deletes.
+	allPartitionsID = -1
+)
+
+type ExportParam struct {
+	framework.ParamBase `use:"export" desc:"export collection rows from binlogs with deletes applied at timestamp into parquet, csv or jsonl file"`
+	CollectionID        int64    `name:"collection" resolve:"collection" default:"0" desc:"collection id to export"`
+	Partitions          []int64  `name:"partitions" desc:"partition ids to export, all partitions if not provided"`
+	Segments            []int64  `name:"segments" desc:"segment ids to export, all segments if not provided"`
+	Expr                string   `name:"expr" default:"" desc:"row filter expression, fields referenced by name, $pk & $timestamp also available"`
+	TS                  string   `name:"ts" default:"" desc:"export rows visible at hybrid ts or time, e.g. now-1h, latest if not provided, rows deleted or upserted after ts may be purged by compaction already"`
+	Fields              []string `name:"fields" desc:"fields to export, all fields if not provided, pk is always exported"`
+	Format              string   `name:"format" default:"parquet" enum:"parquet,csv,jsonl" desc:"output file format"`
+	Output              string   `name:"output" default:"" desc:"output file path, default to <collection id>.<format>"`
+	RowGroupSize        int64    `name:"row-group-size" default:"65536" desc:"max rows of one parquet row group"`
+	MinioAddress        string   `name:"minioAddr" default:"" desc:"the minio address to override, leave empty to use milvus.yaml value"`
+	SkipBucketCheck     bool     `name:"skipBucketCheck" default:"false" desc:"skip bucket exist check due to permission issue"`
+}
+
+// ExportCommand merges insert binlogs with segment & L0 deltalogs, applies MVCC at provided timestamp
+// and streams visible rows into output file, only the latest version of each pk is exported.
+// Note that export at past timestamp is best effort, rows deleted or upserted after it could
+// already be purged by compaction and cannot be restored from binlogs.
+func (s *InstanceState) ExportCommand(ctx context.Context, p *ExportParam) error {
+	if p.CollectionID == 0 {
+		return errors.New("collection shall be provided")
+	}
+	if p.RowGroupSize <= 0 {
+		return errors.Newf("invalid row group size %d", p.RowGroupSize)
+	}
+	ts := uint64(math.MaxUint64)
+	if p.TS != "" {
+		var err error
+		ts, err = parseTSArg(p.TS)
+		if err != nil {
+			return err
+		}
+		fmt.Println("[WARNING] rows deleted or upserted after ts may be purged by compaction already and missing in export")
+	}
+
+	collection, err := common.GetCollectionByIDVersion(ctx, s.client, s.basePath, p.CollectionID)
+	if err != nil {
+		return err
+	}
+	fields, err := exportFields(collection, p.Fields)
+	if err != nil {
+		return err
+	}
+	pkField, _ := collection.GetPKField()
+
+	var program *vm.Program
+	if p.Expr != "" {
+		program, err = expr.Compile(p.Expr, expr.AsBool())
+		if err != nil {
+			return errors.Wrap(err, "invalid filter expression")
+		}
+	}
+
+	partitions := lo.SliceToMap(p.Partitions, func(id int64) (int64, struct{}) { return id, struct{}{} })
+	selectedSegments := lo.SliceToMap(p.Segments, func(id int64) (int64, struct{}) { return id, struct{}{} })
+	segments, err := common.ListSegments(ctx, s.client, s.basePath, func(segment *models.Segment) bool {
+		if segment.CollectionID != p.CollectionID || segment.State == commonpb.SegmentState_Dropped || segment.State == commonpb.SegmentState_NotExist {
+			return false
+		}
+		if _, ok := partitions[segment.PartitionID]; len(partitions) > 0 && !ok && segment.PartitionID != allPartitionsID {
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	l0Segments := lo.Filter(segments, func(segment *models.Segment, _ int) bool {
+		return segment.Level == datapb.SegmentLevel_L0
+	})
+	normalSegments := lo.Filter(segments, func(segment *models.Segment, _ int) bool {
+		_, ok := selectedSegments[segment.ID]
+		return segment.Level != datapb.SegmentLevel_L0 && (len(selectedSegments) == 0 || ok)
+	})
+
+	params := []oss.MinioConnectParam{oss.WithSkipCheckBucket(p.SkipBucketCheck)}
+	if p.MinioAddress != "" {
+		params = append(params, oss.WithMinioAddr(p.MinioAddress))
+	}
+	minioClient, bucketName, rootPath, err := s.GetMinioClientFromCfg(ctx, params...)
+	if err != nil {
+		return err
+	}
+
+	deletes := newExportDeletes(ts)
+	for _, segment := range lo.Flatten([][]*models.Segment{l0Segments, normalSegments}) {
+		deltaData, err := s.DownloadDeltalogs(ctx, minioClient, bucketName, rootPath, collection, segment)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load deltalogs of segment %d", segment.ID)
+		}
+		deletes.add(segment.PartitionID, deltaData)
+	}
+
+	// upsert & duplicated inserts leave several versions of same pk, find latest visible one first
+	versions := newExportVersions()
+	for _, segment := range normalSegments {
+		err := scanSegmentBinlogs(ctx, minioClient, bucketName, rootPath, collection, segment, func(int64) bool { return false }, func(readers map[int64]*storage.BinlogReader) error {
+			iter, err := NewBinlogIterator(collection, readers)
+			if err != nil {
+				return err
+			}
+			return iter.Range(func(_, rowTs int64, pk storage.PrimaryKey, _ map[int64]any) error {
+				if uint64(rowTs) <= ts && !deletes.deleted(segment.PartitionID, pk.GetValue(), uint64(rowTs)) {
+					versions.add(pk.GetValue(), uint64(rowTs))
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to scan pks of segment %d", segment.ID)
+		}
+	}
+
+	output := p.Output
+	if output == "" {
+		output = fmt.Sprintf("%d.%s", p.CollectionID, p.Format)
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	writer, err := newExportWriter(p.Format, f, collection, fields, p.RowGroupSize)
+	if err != nil {
+		return err
+	}
+
+	selected := lo.SliceToMap(fields, func(field models.FieldSchema) (int64, struct{}) { return field.FieldID, struct{}{} })
+	fieldNames := lo.SliceToMap(collection.GetProto().Schema.Fields, func(field *schemapb.FieldSchema) (int64, string) {
+		return field.GetFieldID(), field.GetName()
+	})
+	var total int64
+	for _, segment := range normalSegments {
+		var exported, deleted, invisible, overwritten, filtered int64
+		err := scanSegmentBinlogs(ctx, minioClient, bucketName, rootPath, collection, segment, func(fieldID int64) bool {
+			_, ok := selected[fieldID]
+			// all fields could be referenced in filter expression
+			return ok || program != nil
+		}, func(readers map[int64]*storage.BinlogReader) error {
+			iter, err := NewBinlogIterator(collection, readers)
+			if err != nil {
+				return err
+			}
+			return iter.Range(func(_, rowTs int64, pk storage.PrimaryKey, data map[int64]any) error {
+				if uint64(rowTs) > ts {
+					invisible++
+					return nil
+				}
+				if deletes.deleted(segment.PartitionID, pk.GetValue(), uint64(rowTs)) {
+					deleted++
+					return nil
+				}
+				if !versions.take(pk.GetValue(), uint64(rowTs)) {
+					overwritten++
+					return nil
+				}
+				if program != nil {
+					env := lo.MapKeys(data, func(_ any, fieldID int64) string { return fieldNames[fieldID] })
+					env[pkField.Name] = pk.GetValue()
+					env["$pk"] = pk.GetValue()
+					env["$timestamp"] = rowTs
+					match, err := expr.Run(program, env)
+					if err != nil {
+						return errors.Wrap(err, "failed to run filter expression")
+					}
+					if !match.(bool) {
+						filtered++
+						return nil
+					}
+				}
+				row := make(map[string]any, len(fields))
+				for _, field := range fields {
+					row[field.Name] = data[field.FieldID]
+				}
+				row[pkField.Name] = pk.GetValue()
+				exported++
+				return writer.Write(row)
+			})
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to export segment %d", segment.ID)
+		}
+		total += exported
+		fmt.Printf("Segment %d: %d rows exported, %d deleted, %d invisible at ts, %d overwritten, %d filtered\n",
+			segment.ID, exported, deleted, invisible, overwritten, filtered)
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("%d rows of %d segment(s) exported to %s, %d L0 segment(s) applied\n", total, len(normalSegments), output, len(l0Segments))
+	return nil
+}
+
+// exportFields returns fields to export in schema order, system fields are excluded and pk is always included.
+func exportFields(collection *models.Collection, names []string) ([]models.FieldSchema, error) {
+	byName := make(map[string]models.FieldSchema)
+	var all []models.FieldSchema
+	for _, field := range collection.GetProto().Schema.Fields {
+		if field.GetFieldID() < 100 {
+			continue
+		}
+		fs := models.NewFieldSchemaFromBase(field)
+		byName[fs.Name] = fs
+		all = append(all, fs)
+	}
+	selected := all
+	if len(names) > 0 {
+		for _, name := range names {
+			if _, ok := byName[name]; !ok {
+				return nil, errors.Newf("field %s not found in collection", name)
+			}
+		}
+		nameSet := lo.SliceToMap(names, func(name string) (string, struct{}) { return name, struct{}{} })
+		selected = lo.Filter(all, func(field models.FieldSchema, _ int) bool {
+			_, ok := nameSet[field.Name]
+			return ok || field.IsPrimaryKey
+		})
+	}
+	for _, field := range selected {
+		switch field.DataType {
+		case models.DataTypeBool, models.DataTypeInt8, models.DataTypeInt16, models.DataTypeInt32, models.DataTypeInt64,
+			models.DataTypeFloat, models.DataTypeDouble, models.DataTypeString, models.DataTypeVarChar, models.DataTypeJSON,
+			models.DataTypeFloatVector, models.DataTypeBinaryVector:
+		default:
+			return nil, errors.Newf("field %s data type %s not supported, exclude it with --fields", field.Name, field.DataType.String())
+		}
+	}
+	if _, ok := lo.Find(selected, func(field models.FieldSchema) bool { return field.IsPrimaryKey }); !ok {
+		return nil, errors.New("pk field not found")
+	}
+	return selected, nil
+}
+
+// exportDeletes keeps latest delete ts visible at export ts of each pk per partition.
+type exportDeletes struct {
+	ts      uint64
+	records map[int64]map[any]uint64
+}
+
+func newExportDeletes(ts uint64) *exportDeletes {
+	return &exportDeletes{ts: ts, records: make(map[int64]map[any]uint64)}
+}
+
+func (d *exportDeletes) add(partitionID int64, data *storage.DeltaData) {
+	records, ok := d.records[partitionID]
+	if !ok {
+		records = make(map[any]uint64)
+		d.records[partitionID] = records
+	}
+	data.Range(func(pk storage.PrimaryKey, ts uint64) bool {
+		if ts <= d.ts && ts > records[pk.GetValue()] {
+			records[pk.GetValue()] = ts
+		}
+		return true
+	})
+}
+
+// deleted returns whether row inserted at ts is deleted by later delete in same partition or collection level one.
+func (d *exportDeletes) deleted(partitionID int64, pk any, ts uint64) bool {
+	return d.records[partitionID][pk] > ts || d.records[allPartitionsID][pk] > ts
+}
+
+// exportVersions keeps latest visible & not deleted insert ts of each pk among all segments.
+type exportVersions struct {
+	latest map[any]uint64
+}
+
+func newExportVersions() *exportVersions {
+	return &exportVersions{latest: make(map[any]uint64)}
+}
+
+func (v *exportVersions) add(pk any, ts uint64) {
+	if latest, ok := v.latest[pk]; !ok || ts > latest {
+		v.latest[pk] = ts
+	}
+}
+
+// take returns whether row inserted at ts is the latest version of pk, each pk is taken once
+// so rows duplicated with same ts are exported only once.
+func (v *exportVersions) take(pk any, ts uint64) bool {
+	latest, ok := v.latest[pk]
+	if !ok || ts != latest {
+		return false
+	}
+	delete(v.latest, pk)
+	return true
+}
+
+// exportWriter writes rows keyed by field name into output file.
+type exportWriter interface {
+	Write(row map[string]any) error
+	Close() error
+}
+
+func newExportWriter(format string, f *os.File, collection *models.Collection, fields []models.FieldSchema, rowGroupSize int64) (exportWriter, error) {
+	switch format {
+	case exportParquet:
+		pqWriter := storage.NewParquetWriter(collection,
+			storage.WithParquetFields(lo.Map(fields, func(field models.FieldSchema, _ int) string { return field.Name })...),
+			storage.WithRowGroupSize(rowGroupSize))
+		if err := pqWriter.Open(f); err != nil {
+			return nil, err
+		}
+		return &parquetExportWriter{writer: pqWriter, fields: fields}, nil
+	case exportCSV:
+		w := bufio.NewWriter(f)
+		csvWriter := csv.NewWriter(w)
+		if err := csvWriter.Write(lo.Map(fields, func(field models.FieldSchema, _ int) string { return field.Name })); err != nil {
+			return nil, err
+		}
+		return &csvExportWriter{buf: w, writer: csvWriter, fields: fields}, nil
+	case exportJSONL:
+		w := bufio.NewWriter(f)
+		return &jsonlExportWriter{buf: w, encoder: json.NewEncoder(w), fields: fields}, nil
+	default:
+		return nil, errors.Newf("unknown export format %s", format)
+	}
+}
+
+type parquetExportWriter struct {
+	writer *storage.ParquetWriter
+	fields []models.FieldSchema
+}
+
+func (w *parquetExportWriter) Write(row map[string]any) error {
+	for _, field := range w.fields {
+		if row[field.Name] == nil {
+			return errors.Newf("field %s value not found, exclude it with --fields", field.Name)
+		}
+		if err := writeParquetField(w.writer, field, row[field.Name]); err != nil {
+			return err
+		}
+	}
+	return w.writer.EndRow()
+}
+
+func (w *parquetExportWriter) Close() error {
+	return w.writer.Close()
+}
+
+type csvExportWriter struct {
+	buf    *bufio.Writer
+	writer *csv.Writer
+	fields []models.FieldSchema
+}
+
+func (w *csvExportWriter) Write(row map[string]any) error {
+	record := make([]string, 0, len(w.fields))
+	for _, field := range w.fields {
+		v, err := formatExportValue(field, row[field.Name])
+		if err != nil {
+			return err
+		}
+		record = append(record, v)
+	}
+	return w.writer.Write(record)
+}
+
+func (w *csvExportWriter) Close() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		return err
+	}
+	return w.buf.Flush()
+}
+
+// formatExportValue formats value as csv cell, json & vectors are written in json.
+func formatExportValue(field models.FieldSchema, v any) (string, error) {
+	switch value := v.(type) {
+	case string:
+		return value, nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case []byte:
+		if field.DataType == models.DataTypeJSON {
+			return string(value), nil
+		}
+	}
+	switch v.(type) {
+	case []float32, []byte:
+		bs, err := json.Marshal(v)
+		return string(bs), err
+	}
+	return fmt.Sprint(v), nil
+}
+
+type jsonlExportWriter struct {
+	buf     *bufio.Writer
+	encoder *json.Encoder
+	fields  []models.FieldSchema
+}
+
+func (w *jsonlExportWriter) Write(row map[string]any) error {
+	for _, field := range w.fields {
+		if bs, ok := row[field.Name].([]byte); ok && field.DataType == models.DataTypeJSON && json.Valid(bs) {
+			row[field.Name] = json.RawMessage(bs)
+		}
+	}
+	return w.encoder.Encode(row)
+}
+
+func (w *jsonlExportWriter) Close() error {
+	return w.buf.Flush()
+}
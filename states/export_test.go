@@ -0,0 +1,121 @@
+package states
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+	"github.com/milvus-io/birdwatcher/storage"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+)
+
+func TestExport(t *testing.T) {
+	ctx := context.Background()
+	c := fakecluster.New(t)
+	s3 := c.StartS3()
+	c.StartServer(fakecluster.RoleRootCoord, 1)
+
+	// rows with row id, ts, pk & age all set to 0..9
+	segment := fakecluster.NewSegment(1001, 100, 101).WithRows(10).
+		WithBinlog(0, 1).WithBinlog(1, 2).WithBinlog(100, 3).WithBinlog(101, 4).
+		WithDeltalog(1, 5, 6)
+	// pk 4..6 upserted at ts 50..52 with age 100..102
+	upserted := fakecluster.NewSegment(1003, 100, 101).WithRows(3).
+		WithBinlog(0, 11).WithBinlog(1, 12).WithBinlog(100, 13).WithBinlog(101, 14)
+	l0 := fakecluster.NewSegment(1002, 100, allPartitionsID).WithLevel(datapb.SegmentLevel_L0).WithDeltalog(1, 7)
+	c.AddCollection(fakecluster.NewCollection(100, "coll").WithPrimaryKey(100, "pk").
+		WithField(101, "age", schemapb.DataType_Int64).WithPartition(101, "_default")).
+		AddSegment(segment).AddSegment(upserted).AddSegment(l0)
+	require.NoError(t, s3.PutInsertBinlogs(segment))
+	require.NoError(t, s3.PutInsertBinlogsFunc(upserted, func(fieldID, row int64) int64 {
+		switch fieldID {
+		case 1:
+			return row + 50
+		case 100:
+			return row + 4
+		case 101:
+			return row + 100
+		}
+		return row
+	}))
+	require.NoError(t, s3.PutDeltalog(segment, 5, 20, 1, 2))
+	// deleted before inserted, shall not take effect
+	require.NoError(t, s3.PutDeltalog(segment, 6, 3, 8))
+	require.NoError(t, s3.PutDeltalog(l0, 7, 30, 3))
+
+	s := &InstanceState{client: c.KV(), basePath: c.BasePath()}
+	dir := t.TempDir()
+
+	readParquet := func(file string) []map[string]any {
+		f, err := os.Open(file)
+		require.NoError(t, err)
+		defer f.Close()
+		reader, err := storage.NewParquetReader(f)
+		require.NoError(t, err)
+		defer reader.Close()
+		var rows []map[string]any
+		for {
+			row, err := reader.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			rows = append(rows, row)
+		}
+		assert.EqualValues(t, len(rows), reader.NumRows())
+		return rows
+	}
+	pks := func(rows []map[string]any) []int64 {
+		var result []int64
+		for _, row := range rows {
+			result = append(result, row["pk"].(int64))
+		}
+		return result
+	}
+
+	output := filepath.Join(dir, "latest.parquet")
+	require.NoError(t, s.ExportCommand(ctx, &ExportParam{CollectionID: 100, Format: exportParquet, Output: output, RowGroupSize: 3}))
+	rows := readParquet(output)
+	assert.Equal(t, []int64{0, 7, 8, 9, 4, 5, 6}, pks(rows))
+	assert.Equal(t, map[string]any{"pk": int64(4), "age": int64(100)}, rows[4])
+
+	output = filepath.Join(dir, "ts.parquet")
+	require.NoError(t, s.ExportCommand(ctx, &ExportParam{CollectionID: 100, Format: exportParquet, Output: output, RowGroupSize: 100, TS: "25"}))
+	assert.Equal(t, []int64{0, 3, 4, 5, 6, 7, 8, 9}, pks(readParquet(output)))
+
+	output = filepath.Join(dir, "expr.jsonl")
+	require.NoError(t, s.ExportCommand(ctx, &ExportParam{CollectionID: 100, Format: exportJSONL, Output: output, RowGroupSize: 100, Expr: "age >= 5 && $pk < 8"}))
+	f, err := os.Open(output)
+	require.NoError(t, err)
+	defer f.Close()
+	var lines []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := make(map[string]any)
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	// filter applies on latest version only
+	assert.Equal(t, []map[string]any{{"pk": 7.0, "age": 7.0}, {"pk": 4.0, "age": 100.0}, {"pk": 5.0, "age": 101.0}, {"pk": 6.0, "age": 102.0}}, lines)
+
+	output = filepath.Join(dir, "fields.csv")
+	require.NoError(t, s.ExportCommand(ctx, &ExportParam{CollectionID: 100, Format: exportCSV, Output: output, RowGroupSize: 100, TS: "5", Fields: []string{"pk"}}))
+	f, err = os.Open(output)
+	require.NoError(t, err)
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"pk"}, {"0"}, {"1"}, {"2"}, {"3"}, {"4"}, {"5"}}, records)
+
+	assert.Error(t, s.ExportCommand(ctx, &ExportParam{CollectionID: 100, Format: exportCSV, Output: output, RowGroupSize: 100, Fields: []string{"unknown"}}))
+}
@@ -0,0 +1,357 @@
+package fakecluster
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/etcdpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/indexpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+)
+
+// CollectionBuilder builds collection meta with fields and partitions.
+type CollectionBuilder struct {
+	info       *etcdpb.CollectionInfo
+	fields     []*schemapb.FieldSchema
+	partitions []*etcdpb.PartitionInfo
+}
+
+// NewCollection returns collection builder with one vchannel.
+func NewCollection(id int64, name string) *CollectionBuilder {
+	return &CollectionBuilder{
+		info: &etcdpb.CollectionInfo{
+			ID:                   id,
+			Schema:               &schemapb.CollectionSchema{Name: name},
+			VirtualChannelNames:  []string{fmt.Sprintf("%s-rootcoord-dml_0_%dv0", RootPath, id)},
+			PhysicalChannelNames: []string{fmt.Sprintf("%s-rootcoord-dml_0", RootPath)},
+			ShardsNum:            1,
+			State:                etcdpb.CollectionState_CollectionCreated,
+		},
+	}
+}
+
+// WithDB sets database id of collection.
+func (b *CollectionBuilder) WithDB(dbID int64) *CollectionBuilder {
+	b.info.DbId = dbID
+	return b
+}
+
+// WithState sets collection state.
+func (b *CollectionBuilder) WithState(state etcdpb.CollectionState) *CollectionBuilder {
+	b.info.State = state
+	return b
+}
+
+// WithChannels sets vchannels of collection, pchannels are derived from vchannel names.
+func (b *CollectionBuilder) WithChannels(vchannels ...string) *CollectionBuilder {
+	b.info.VirtualChannelNames = vchannels
+	b.info.PhysicalChannelNames = lo.Map(vchannels, func(vchannel string, _ int) string {
+		if idx := strings.LastIndex(vchannel, "_"); idx > 0 {
+			return vchannel[:idx]
+		}
+		return vchannel
+	})
+	b.info.ShardsNum = int32(len(vchannels))
+	return b
+}
+
+// WithField adds a field with type params.
+func (b *CollectionBuilder) WithField(id int64, name string, dataType schemapb.DataType, typeParams ...*commonpb.KeyValuePair) *CollectionBuilder {
+	b.fields = append(b.fields, &schemapb.FieldSchema{
+		FieldID:    id,
+		Name:       name,
+		DataType:   dataType,
+		TypeParams: typeParams,
+	})
+	return b
+}
+
+// WithPrimaryKey adds int64 primary key field.
+func (b *CollectionBuilder) WithPrimaryKey(id int64, name string) *CollectionBuilder {
+	b.fields = append(b.fields, &schemapb.FieldSchema{
+		FieldID:      id,
+		Name:         name,
+		DataType:     schemapb.DataType_Int64,
+		IsPrimaryKey: true,
+	})
+	return b
+}
+
+// WithVector adds float vector field with provided dim.
+func (b *CollectionBuilder) WithVector(id int64, name string, dim int) *CollectionBuilder {
+	return b.WithField(id, name, schemapb.DataType_FloatVector, &commonpb.KeyValuePair{Key: "dim", Value: fmt.Sprint(dim)})
+}
+
+// WithPartition adds a partition.
+func (b *CollectionBuilder) WithPartition(id int64, name string) *CollectionBuilder {
+	b.partitions = append(b.partitions, &etcdpb.PartitionInfo{
+		PartitionID:   id,
+		PartitionName: name,
+		CollectionId:  b.info.ID,
+		State:         etcdpb.PartitionState_PartitionCreated,
+	})
+	return b
+}
+
+// Build returns collection info, fields and partitions are stored separately.
+func (b *CollectionBuilder) Build() *etcdpb.CollectionInfo {
+	return b.info
+}
+
+// SegmentBuilder builds datacoord segment meta.
+type SegmentBuilder struct {
+	info *datapb.SegmentInfo
+}
+
+// NewSegment returns flushed L1 segment builder on collection first vchannel.
+func NewSegment(id, collectionID, partitionID int64) *SegmentBuilder {
+	return &SegmentBuilder{
+		info: &datapb.SegmentInfo{
+			ID:            id,
+			CollectionID:  collectionID,
+			PartitionID:   partitionID,
+			InsertChannel: fmt.Sprintf("%s-rootcoord-dml_0_%dv0", RootPath, collectionID),
+			State:         commonpb.SegmentState_Flushed,
+			Level:         datapb.SegmentLevel_L1,
+		},
+	}
+}
+
+// WithChannel sets segment insert channel.
+func (b *SegmentBuilder) WithChannel(channel string) *SegmentBuilder {
+	b.info.InsertChannel = channel
+	return b
+}
+
+// WithState sets segment state.
+func (b *SegmentBuilder) WithState(state commonpb.SegmentState) *SegmentBuilder {
+	b.info.State = state
+	return b
+}
+
+// WithLevel sets segment level.
+func (b *SegmentBuilder) WithLevel(level datapb.SegmentLevel) *SegmentBuilder {
+	b.info.Level = level
+	return b
+}
+
+// WithRows sets segment row number.
+func (b *SegmentBuilder) WithRows(rows int64) *SegmentBuilder {
+	b.info.NumOfRows = rows
+	return b
+}
+
+// WithCompactionFrom marks segment as compacted from provided segments.
+func (b *SegmentBuilder) WithCompactionFrom(segmentIDs ...int64) *SegmentBuilder {
+	b.info.CreatedByCompaction = true
+	b.info.CompactionFrom = segmentIDs
+	return b
+}
+
+// WithBinlog adds insert binlogs for field, each log has all rows set by WithRows.
+func (b *SegmentBuilder) WithBinlog(fieldID int64, logIDs ...int64) *SegmentBuilder {
+	b.info.Binlogs = append(b.info.Binlogs, b.fieldBinlog(fieldID, logIDs))
+	return b
+}
+
+// WithStatslog adds stats logs for field.
+func (b *SegmentBuilder) WithStatslog(fieldID int64, logIDs ...int64) *SegmentBuilder {
+	b.info.Statslogs = append(b.info.Statslogs, b.fieldBinlog(fieldID, logIDs))
+	return b
+}
+
+// WithBm25Statslog adds bm25 stats logs for field.
+func (b *SegmentBuilder) WithBm25Statslog(fieldID int64, logIDs ...int64) *SegmentBuilder {
+	b.info.Bm25Statslogs = append(b.info.Bm25Statslogs, b.fieldBinlog(fieldID, logIDs))
+	return b
+}
+
+// WithDroppedAt sets segment drop time.
+func (b *SegmentBuilder) WithDroppedAt(t time.Time) *SegmentBuilder {
+	b.info.DroppedAt = uint64(t.UnixNano())
+	return b
+}
+
+// WithDeltalog adds delta logs with provided entry number.
+func (b *SegmentBuilder) WithDeltalog(entries int64, logIDs ...int64) *SegmentBuilder {
+	fieldBinlog := b.fieldBinlog(0, logIDs)
+	for _, binlog := range fieldBinlog.GetBinlogs() {
+		binlog.EntriesNum = entries
+	}
+	b.info.Deltalogs = append(b.info.Deltalogs, fieldBinlog)
+	return b
+}
+
+func (b *SegmentBuilder) fieldBinlog(fieldID int64, logIDs []int64) *datapb.FieldBinlog {
+	return &datapb.FieldBinlog{
+		FieldID: fieldID,
+		Binlogs: lo.Map(logIDs, func(logID int64, _ int) *datapb.Binlog {
+			return &datapb.Binlog{
+				LogID:      logID,
+				EntriesNum: b.info.NumOfRows,
+			}
+		}),
+	}
+}
+
+// Build returns segment info with binlogs attached.
+func (b *SegmentBuilder) Build() *datapb.SegmentInfo {
+	return b.info
+}
+
+// IndexBuilder builds field index meta.
+type IndexBuilder struct {
+	index *indexpb.FieldIndex
+}
+
+// NewIndex returns field index builder.
+func NewIndex(collectionID, fieldID, indexID int64, name string) *IndexBuilder {
+	return &IndexBuilder{
+		index: &indexpb.FieldIndex{
+			IndexInfo: &indexpb.IndexInfo{
+				CollectionID: collectionID,
+				FieldID:      fieldID,
+				IndexID:      indexID,
+				IndexName:    name,
+			},
+		},
+	}
+}
+
+// WithParams sets index params in key value pairs, e.g. "index_type", "HNSW", "metric_type", "L2".
+func (b *IndexBuilder) WithParams(kvs ...string) *IndexBuilder {
+	for i := 0; i+1 < len(kvs); i += 2 {
+		b.index.IndexInfo.IndexParams = append(b.index.IndexInfo.IndexParams, &commonpb.KeyValuePair{Key: kvs[i], Value: kvs[i+1]})
+	}
+	return b
+}
+
+// WithDeleted marks index as deleted.
+func (b *IndexBuilder) WithDeleted() *IndexBuilder {
+	b.index.Deleted = true
+	return b
+}
+
+// Build returns field index.
+func (b *IndexBuilder) Build() *indexpb.FieldIndex {
+	return b.index
+}
+
+// SegmentIndexBuilder builds segment index meta.
+type SegmentIndexBuilder struct {
+	segIdx *indexpb.SegmentIndex
+}
+
+// NewSegmentIndex returns finished segment index builder for segment.
+func NewSegmentIndex(segment *SegmentBuilder, indexID, buildID int64) *SegmentIndexBuilder {
+	info := segment.Build()
+	return &SegmentIndexBuilder{
+		segIdx: &indexpb.SegmentIndex{
+			CollectionID: info.GetCollectionID(),
+			PartitionID:  info.GetPartitionID(),
+			SegmentID:    info.GetID(),
+			NumRows:      info.GetNumOfRows(),
+			IndexID:      indexID,
+			BuildID:      buildID,
+			IndexVersion: 1,
+			State:        commonpb.IndexState_Finished,
+		},
+	}
+}
+
+// WithState sets segment index state.
+func (b *SegmentIndexBuilder) WithState(state commonpb.IndexState) *SegmentIndexBuilder {
+	b.segIdx.State = state
+	return b
+}
+
+// WithFailReason marks segment index build failed with reason.
+func (b *SegmentIndexBuilder) WithFailReason(reason string) *SegmentIndexBuilder {
+	b.segIdx.State = commonpb.IndexState_Failed
+	b.segIdx.FailReason = reason
+	return b
+}
+
+// WithFiles sets index file keys.
+func (b *SegmentIndexBuilder) WithFiles(keys ...string) *SegmentIndexBuilder {
+	b.segIdx.IndexFileKeys = keys
+	return b
+}
+
+// Build returns segment index.
+func (b *SegmentIndexBuilder) Build() *indexpb.SegmentIndex {
+	return b.segIdx
+}
+
+// ReplicaBuilder builds querycoord replica meta.
+type ReplicaBuilder struct {
+	replica *querypb.Replica
+}
+
+// NewReplica returns replica builder in default resource group.
+func NewReplica(id, collectionID int64) *ReplicaBuilder {
+	return &ReplicaBuilder{
+		replica: &querypb.Replica{
+			ID:            id,
+			CollectionID:  collectionID,
+			ResourceGroup: "__default_resource_group",
+		},
+	}
+}
+
+// WithNodes sets replica querynodes.
+func (b *ReplicaBuilder) WithNodes(nodeIDs ...int64) *ReplicaBuilder {
+	b.replica.Nodes = nodeIDs
+	return b
+}
+
+// WithResourceGroup sets replica resource group.
+func (b *ReplicaBuilder) WithResourceGroup(rg string) *ReplicaBuilder {
+	b.replica.ResourceGroup = rg
+	return b
+}
+
+// Build returns replica.
+func (b *ReplicaBuilder) Build() *querypb.Replica {
+	return b.replica
+}
+
+// CollectionLoadBuilder builds querycoord collection load info.
+type CollectionLoadBuilder struct {
+	info *querypb.CollectionLoadInfo
+}
+
+// NewCollectionLoad returns loaded collection builder with one replica.
+func NewCollectionLoad(collectionID int64) *CollectionLoadBuilder {
+	return &CollectionLoadBuilder{
+		info: &querypb.CollectionLoadInfo{
+			CollectionID:  collectionID,
+			ReplicaNumber: 1,
+			Status:        querypb.LoadStatus_Loaded,
+			LoadType:      querypb.LoadType_LoadCollection,
+		},
+	}
+}
+
+// WithReplicaNumber sets replica number.
+func (b *CollectionLoadBuilder) WithReplicaNumber(n int32) *CollectionLoadBuilder {
+	b.info.ReplicaNumber = n
+	return b
+}
+
+// WithStatus sets load status.
+func (b *CollectionLoadBuilder) WithStatus(status querypb.LoadStatus) *CollectionLoadBuilder {
+	b.info.Status = status
+	return b
+}
+
+// Build returns collection load info.
+func (b *CollectionLoadBuilder) Build() *querypb.CollectionLoadInfo {
+	return b.info
+}
@@ -0,0 +1,198 @@
+// Package fakecluster provides a hermetic fake milvus cluster for command integration tests.
+// It consists of an embedded etcd seeded with milvus meta, in-process fake coordinator/node
+// gRPC servers registered as sessions and an in-memory S3 compatible object storage.
+package fakecluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"testing"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3client"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+)
+
+const (
+	// RootPath is the milvus instance name used by fake cluster.
+	RootPath = "by-dev"
+	// MetaPath is the meta sub path used by fake cluster.
+	MetaPath = "meta"
+)
+
+// Cluster is a fake milvus cluster backed by embedded etcd.
+type Cluster struct {
+	t      testing.TB
+	server *embed.Etcd
+	client *clientv3.Client
+	kv     kv.MetaKV
+
+	// S3 is the in-memory object storage, nil before StartS3 called.
+	S3 *S3
+}
+
+// New starts an embedded etcd server for fake cluster, resources are released with test cleanup.
+func New(t testing.TB) *Cluster {
+	t.Helper()
+	config := embed.NewConfig()
+	config.Dir = t.TempDir()
+	config.LogLevel = "warn"
+	config.LogOutputs = []string{"default"}
+	u, _ := url.Parse("http://localhost:0")
+	config.LCUrls = []url.URL{*u}
+	config.LPUrls = []url.URL{*u}
+
+	server, err := embed.StartEtcd(config)
+	if err != nil {
+		t.Fatalf("failed to start embed etcd: %s", err.Error())
+	}
+	select {
+	case <-server.Server.ReadyNotify():
+	case <-time.After(60 * time.Second):
+		server.Close()
+		t.Fatal("embed etcd took too long to start")
+	}
+
+	client := v3client.New(server.Server)
+	c := &Cluster{
+		t:      t,
+		server: server,
+		client: client,
+		kv:     kv.NewEtcdKV(client),
+	}
+	t.Cleanup(c.Close)
+	return c
+}
+
+// KV returns the MetaKV connected to fake cluster etcd.
+func (c *Cluster) KV() kv.MetaKV {
+	return c.kv
+}
+
+// Client returns raw etcd client of fake cluster.
+func (c *Cluster) Client() *clientv3.Client {
+	return c.client
+}
+
+// BasePath returns meta base path, which is `{RootPath}/{MetaPath}`.
+func (c *Cluster) BasePath() string {
+	return path.Join(RootPath, MetaPath)
+}
+
+// Close stops fake cluster etcd server.
+func (c *Cluster) Close() {
+	c.client.Close()
+	c.server.Close()
+}
+
+// Put saves raw value under base path with provided relative key.
+func (c *Cluster) Put(key string, value string) {
+	c.t.Helper()
+	if err := c.kv.Save(context.Background(), path.Join(c.BasePath(), key), value); err != nil {
+		c.t.Fatalf("failed to save %s: %s", key, err.Error())
+	}
+}
+
+// PutProto saves marshaled proto message under base path with provided relative key.
+func (c *Cluster) PutProto(key string, msg proto.Message) {
+	c.t.Helper()
+	bs, err := proto.Marshal(msg)
+	if err != nil {
+		c.t.Fatalf("failed to marshal %s: %s", key, err.Error())
+	}
+	c.Put(key, string(bs))
+}
+
+// AddCollection seeds collection info, fields and partitions meta.
+func (c *Cluster) AddCollection(b *CollectionBuilder) *Cluster {
+	c.t.Helper()
+	info := b.Build()
+	c.PutProto(path.Join(common.DBCollectionMetaPrefix, fmt.Sprintf("%d/%d", info.GetDbId(), info.GetID())), info)
+	for _, field := range b.fields {
+		c.PutProto(path.Join(common.FieldMetaPrefix, fmt.Sprintf("%d/%d", info.GetID(), field.GetFieldID())), field)
+	}
+	for _, partition := range b.partitions {
+		c.PutProto(path.Join(common.RCPrefix, common.PartitionPrefix, fmt.Sprintf("%d/%d", info.GetID(), partition.GetPartitionID())), partition)
+	}
+	return c
+}
+
+// AddSegment seeds segment info with binlog, statslog, deltalog and bm25 log meta saved separately as milvus does.
+func (c *Cluster) AddSegment(b *SegmentBuilder) *Cluster {
+	c.t.Helper()
+	info := proto.Clone(b.Build()).(*datapb.SegmentInfo)
+	binlogs, statslogs, deltalogs, bm25logs := info.GetBinlogs(), info.GetStatslogs(), info.GetDeltalogs(), info.GetBm25Statslogs()
+	info.Binlogs, info.Statslogs, info.Deltalogs, info.Bm25Statslogs = nil, nil, nil, nil
+
+	segmentKey := fmt.Sprintf("%d/%d/%d", info.GetCollectionID(), info.GetPartitionID(), info.GetID())
+	c.PutProto(path.Join(common.DCPrefix, common.SegmentMetaPrefix, segmentKey), info)
+	for prefix, fieldBinlogs := range map[string][]*datapb.FieldBinlog{
+		"binlog":   binlogs,
+		"statslog": statslogs,
+		"deltalog": deltalogs,
+		"bm25log":  bm25logs,
+	} {
+		for _, fieldBinlog := range fieldBinlogs {
+			c.PutProto(path.Join(common.DCPrefix, prefix, segmentKey, fmt.Sprint(fieldBinlog.GetFieldID())), fieldBinlog)
+		}
+	}
+	return c
+}
+
+// AddIndex seeds field index meta.
+func (c *Cluster) AddIndex(b *IndexBuilder) *Cluster {
+	c.t.Helper()
+	index := b.Build()
+	c.PutProto(path.Join(common.IndexPrefix, fmt.Sprintf("%d/%d", index.GetIndexInfo().GetCollectionID(), index.GetIndexInfo().GetIndexID())), index)
+	return c
+}
+
+// AddSegmentIndex seeds segment index meta.
+func (c *Cluster) AddSegmentIndex(b *SegmentIndexBuilder) *Cluster {
+	c.t.Helper()
+	segIdx := b.Build()
+	c.PutProto(path.Join(common.SegmentIndexPrefix, fmt.Sprintf("%d/%d/%d/%d", segIdx.GetCollectionID(), segIdx.GetPartitionID(), segIdx.GetSegmentID(), segIdx.GetBuildID())), segIdx)
+	return c
+}
+
+// AddReplica seeds querycoord replica meta.
+func (c *Cluster) AddReplica(b *ReplicaBuilder) *Cluster {
+	c.t.Helper()
+	replica := b.Build()
+	c.PutProto(path.Join(common.ReplicaPrefix, fmt.Sprintf("%d/%d", replica.GetCollectionID(), replica.GetID())), replica)
+	return c
+}
+
+// AddCollectionLoaded seeds querycoord collection load info.
+func (c *Cluster) AddCollectionLoaded(b *CollectionLoadBuilder) *Cluster {
+	c.t.Helper()
+	info := b.Build()
+	c.PutProto(path.Join(common.CollectionLoadPrefixV2, fmt.Sprint(info.GetCollectionID())), info)
+	return c
+}
+
+// AddSession registers session for milvus component.
+// Coordinators use `session/{role}` key while other components use `session/{role}-{id}`.
+func (c *Cluster) AddSession(session *models.Session) *Cluster {
+	c.t.Helper()
+	bs, err := json.Marshal(session)
+	if err != nil {
+		c.t.Fatalf("failed to marshal session: %s", err.Error())
+	}
+	key := session.ServerName
+	if !session.Exclusive {
+		key = fmt.Sprintf("%s-%d", session.ServerName, session.ServerID)
+	}
+	c.Put(path.Join("session", key), string(bs))
+	return c
+}
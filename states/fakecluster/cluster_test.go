@@ -0,0 +1,112 @@
+package fakecluster
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/oss"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/mgrpc"
+	"github.com/milvus-io/birdwatcher/storage"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/rootcoordpb"
+)
+
+func TestClusterMeta(t *testing.T) {
+	c := New(t)
+	ctx := context.Background()
+
+	segment := NewSegment(1001, 100, 101).WithRows(10).WithBinlog(100, 1).WithDeltalog(2, 3)
+	c.AddCollection(NewCollection(100, "coll").WithPrimaryKey(100, "pk").WithVector(101, "vec", 8).WithPartition(101, "_default")).
+		AddSegment(segment).
+		AddSegment(NewSegment(1002, 100, 101).WithState(commonpb.SegmentState_Dropped)).
+		AddReplica(NewReplica(1, 100).WithNodes(5)).
+		AddSession(&models.Session{ServerID: 5, ServerName: "querynode", Address: "localhost:21123"})
+
+	collections, err := common.ListCollections(ctx, c.KV(), c.BasePath())
+	require.NoError(t, err)
+	require.Len(t, collections, 1)
+	assert.Equal(t, "coll", collections[0].GetProto().GetSchema().GetName())
+	assert.Len(t, collections[0].GetProto().GetSchema().GetFields(), 2)
+
+	segments, err := common.ListSegments(ctx, c.KV(), c.BasePath())
+	require.NoError(t, err)
+	require.Len(t, segments, 2)
+
+	replicas, err := common.ListReplicas(ctx, c.KV(), c.BasePath())
+	require.NoError(t, err)
+	require.Len(t, replicas, 1)
+	assert.ElementsMatch(t, []int64{5}, replicas[0].GetProto().GetNodes())
+
+	sessions, err := common.ListSessions(ctx, c.KV(), c.BasePath())
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, int64(5), sessions[0].ServerID)
+}
+
+func TestClusterStorage(t *testing.T) {
+	c := New(t)
+	ctx := context.Background()
+	s3 := c.StartS3()
+	rc := c.StartServer(RoleRootCoord, 1)
+
+	segment := NewSegment(1001, 100, 101).WithRows(10).WithBinlog(100, 1)
+	c.AddSegment(segment)
+	require.NoError(t, s3.PutInsertBinlogs(segment))
+
+	conn, err := grpc.DialContext(ctx, rc.Address, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	require.NoError(t, err)
+	defer conn.Close()
+	configs, err := mgrpc.GetConfiguration(ctx, rootcoordpb.NewRootCoordClient(conn), rc.ID)
+	require.NoError(t, err)
+	mp := oss.MinioClientParam{}
+	for _, config := range configs {
+		switch config.GetKey() {
+		case "minio.address":
+			mp.Addr = config.GetValue()
+		case "minio.port":
+			mp.Port = config.GetValue()
+		case "minio.accesskeyid":
+			mp.AK = config.GetValue()
+		case "minio.secretaccesskey":
+			mp.SK = config.GetValue()
+		case "minio.bucketname":
+			mp.BucketName = config.GetValue()
+		case "minio.cloudprovider":
+			mp.CloudProvider = config.GetValue()
+		}
+	}
+
+	client, err := oss.NewMinioClient(ctx, mp)
+	require.NoError(t, err)
+
+	var keys []string
+	for info := range client.Client.ListObjects(ctx, S3Bucket, minio.ListObjectsOptions{Prefix: S3RootPath + "/insert_log/", Recursive: true}) {
+		require.NoError(t, info.Err)
+		keys = append(keys, info.Key)
+	}
+	require.Equal(t, []string{"files/insert_log/100/101/1001/100/1"}, keys)
+
+	obj, err := client.Client.GetObject(ctx, S3Bucket, keys[0], minio.GetObjectOptions{})
+	require.NoError(t, err)
+	reader, desc, err := storage.NewBinlogReader(obj)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1001), desc.SegmentID)
+	values, err := reader.NextInt64EventReader()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, values)
+
+	_, err = client.Client.PutObject(ctx, S3Bucket, "files/test", strings.NewReader("hello"), 5, minio.PutObjectOptions{})
+	require.NoError(t, err)
+	data, ok := s3.GetObject(S3Bucket, "files/test")
+	require.True(t, ok)
+	assert.Equal(t, "hello", string(data))
+}
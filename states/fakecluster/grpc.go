@@ -0,0 +1,149 @@
+package fakecluster
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/rootcoordpb"
+)
+
+// Role is the milvus component role, which is also the session server name.
+type Role string
+
+const (
+	RoleRootCoord  Role = "rootcoord"
+	RoleDataCoord  Role = "datacoord"
+	RoleQueryCoord Role = "querycoord"
+	RoleQueryNode  Role = "querynode"
+)
+
+// HandlerFunc handles unary gRPC request, req is the request message of method.
+type HandlerFunc func(ctx context.Context, req any) (any, error)
+
+// Server is an in-process fake milvus component gRPC server.
+// Methods not scripted with Handle or Respond return Unimplemented error,
+// except ShowConfigurations which returns configurations set by SetConfig.
+type Server struct {
+	Role    Role
+	ID      int64
+	Address string
+
+	mut      sync.RWMutex
+	handlers map[string]HandlerFunc
+	configs  []*commonpb.KeyValuePair
+
+	server *grpc.Server
+}
+
+// StartServer starts fake component server and registers its session.
+// Root coord reports minio configurations pointing to fake S3 if S3 started.
+func (c *Cluster) StartServer(role Role, id int64) *Server {
+	c.t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		c.t.Fatalf("failed to listen: %s", err.Error())
+	}
+
+	s := &Server{
+		Role:     role,
+		ID:       id,
+		Address:  lis.Addr().String(),
+		handlers: make(map[string]HandlerFunc),
+	}
+	s.server = grpc.NewServer(grpc.UnaryInterceptor(s.intercept))
+	switch role {
+	case RoleRootCoord:
+		rootcoordpb.RegisterRootCoordServer(s.server, &rootcoordpb.UnimplementedRootCoordServer{})
+	case RoleDataCoord:
+		datapb.RegisterDataCoordServer(s.server, &datapb.UnimplementedDataCoordServer{})
+	case RoleQueryCoord:
+		querypb.RegisterQueryCoordServer(s.server, &querypb.UnimplementedQueryCoordServer{})
+	case RoleQueryNode:
+		querypb.RegisterQueryNodeServer(s.server, &querypb.UnimplementedQueryNodeServer{})
+	default:
+		c.t.Fatalf("unknown role %s", role)
+	}
+	s.Handle("ShowConfigurations", s.showConfigurations)
+
+	if role == RoleRootCoord && c.S3 != nil {
+		host, port, _ := net.SplitHostPort(c.S3.Endpoint())
+		s.SetConfig("minio.address", host)
+		s.SetConfig("minio.port", port)
+		s.SetConfig("minio.accesskeyid", S3AccessKey)
+		s.SetConfig("minio.secretaccesskey", S3SecretKey)
+		s.SetConfig("minio.bucketname", S3Bucket)
+		s.SetConfig("minio.rootpath", S3RootPath)
+		s.SetConfig("minio.usessl", "false")
+		s.SetConfig("minio.useiam", "false")
+		s.SetConfig("minio.cloudprovider", "aws")
+	}
+
+	go s.server.Serve(lis)
+	c.t.Cleanup(s.server.Stop)
+
+	c.AddSession(&models.Session{
+		ServerID:   id,
+		ServerName: string(role),
+		Address:    s.Address,
+		Exclusive:  role != RoleQueryNode,
+		Version:    "2.5.0",
+	})
+	return s
+}
+
+// Handle scripts handler of method, method is the short method name like "GetDataDistribution".
+func (s *Server) Handle(method string, fn HandlerFunc) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.handlers[method] = fn
+}
+
+// Respond scripts fixed response of method.
+func (s *Server) Respond(method string, resp proto.Message) {
+	s.Handle(method, func(ctx context.Context, req any) (any, error) {
+		return resp, nil
+	})
+}
+
+// SetConfig sets configuration item returned by ShowConfigurations.
+func (s *Server) SetConfig(key, value string) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	for _, config := range s.configs {
+		if config.GetKey() == key {
+			config.Value = value
+			return
+		}
+	}
+	s.configs = append(s.configs, &commonpb.KeyValuePair{Key: key, Value: value})
+}
+
+func (s *Server) showConfigurations(ctx context.Context, req any) (any, error) {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	return &internalpb.ShowConfigurationsResponse{
+		Status:        &commonpb.Status{},
+		Configuations: s.configs,
+	}, nil
+}
+
+func (s *Server) intercept(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	method := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+	s.mut.RLock()
+	fn, ok := s.handlers[method]
+	s.mut.RUnlock()
+	if ok {
+		return fn(ctx, req)
+	}
+	return handler(ctx, req)
+}
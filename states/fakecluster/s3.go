@@ -0,0 +1,453 @@
+package fakecluster
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+
+	"github.com/milvus-io/birdwatcher/internal/testutil"
+	"github.com/milvus-io/birdwatcher/storage"
+)
+
+const (
+	// S3AccessKey is the access key accepted by fake S3, credentials are not verified.
+	S3AccessKey = "minioadmin"
+	// S3SecretKey is the secret key accepted by fake S3, credentials are not verified.
+	S3SecretKey = "minioadmin"
+	// S3Bucket is the bucket name fake cluster uses.
+	S3Bucket = "a-bucket"
+	// S3RootPath is the object root path fake cluster uses.
+	S3RootPath = "files"
+)
+
+type s3Object struct {
+	data    []byte
+	etag    string
+	modTime time.Time
+}
+
+// S3 is an in-memory S3 compatible object storage serving path-style requests.
+// Bucket check, list objects v2, get/head with range, put and delete are supported.
+type S3 struct {
+	mut     sync.RWMutex
+	buckets map[string]map[string]*s3Object
+	server  *httptest.Server
+}
+
+// StartS3 starts in-memory S3 service with default bucket created, root coord started afterwards
+// reports the minio configurations pointing to it.
+func (c *Cluster) StartS3() *S3 {
+	s := &S3{buckets: make(map[string]map[string]*s3Object)}
+	s.CreateBucket(S3Bucket)
+	s.server = httptest.NewServer(s)
+	c.t.Cleanup(s.server.Close)
+	c.S3 = s
+	return s
+}
+
+// Endpoint returns the `host:port` address of S3 service.
+func (s *S3) Endpoint() string {
+	return strings.TrimPrefix(s.server.URL, "http://")
+}
+
+// CreateBucket creates bucket if not exists.
+func (s *S3) CreateBucket(bucket string) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if _, ok := s.buckets[bucket]; !ok {
+		s.buckets[bucket] = make(map[string]*s3Object)
+	}
+}
+
+// PutObject saves object content.
+func (s *S3) PutObject(bucket, key string, data []byte) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	objects, ok := s.buckets[bucket]
+	if !ok {
+		objects = make(map[string]*s3Object)
+		s.buckets[bucket] = objects
+	}
+	sum := md5.Sum(data)
+	objects[key] = &s3Object{data: data, etag: hex.EncodeToString(sum[:]), modTime: time.Now()}
+}
+
+// GetObject returns object content.
+func (s *S3) GetObject(bucket, key string) ([]byte, bool) {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	obj, ok := s.buckets[bucket][key]
+	if !ok {
+		return nil, false
+	}
+	return obj.data, true
+}
+
+// Keys returns sorted object keys with provided prefix.
+func (s *S3) Keys(bucket, prefix string) []string {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	return s.keys(bucket, prefix)
+}
+
+func (s *S3) keys(bucket, prefix string) []string {
+	var keys []string
+	for key := range s.buckets[bucket] {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// PutInsertBinlogs generates insert binlog files for all binlogs of segment in default bucket.
+// Each binlog contains sequential int64 values with entries number rows.
+func (s *S3) PutInsertBinlogs(segment *SegmentBuilder) error {
+	return s.PutInsertBinlogsFunc(segment, func(_, row int64) int64 { return row })
+}
+
+// PutInsertBinlogsFunc generates insert binlog files for all binlogs of segment in default bucket,
+// value of each row is returned by provided func with field id & row offset in binlog.
+func (s *S3) PutInsertBinlogsFunc(segment *SegmentBuilder, value func(fieldID, row int64) int64) error {
+	info := segment.Build()
+	for _, fieldBinlog := range info.GetBinlogs() {
+		for _, binlog := range fieldBinlog.GetBinlogs() {
+			builder := array.NewInt64Builder(memory.DefaultAllocator)
+			for i := int64(0); i < binlog.GetEntriesNum(); i++ {
+				builder.Append(value(fieldBinlog.GetFieldID(), i))
+			}
+			column := builder.NewArray()
+			builder.Release()
+
+			buf := &bytes.Buffer{}
+			err := testutil.WriteInsertBinlog(buf, storage.DescriptorEventDataFixPart{
+				CollectionID: info.GetCollectionID(),
+				PartitionID:  info.GetPartitionID(),
+				SegmentID:    info.GetID(),
+				FieldID:      fieldBinlog.GetFieldID(),
+				// insert event requires non-zero timestamps
+				StartTimestamp:  max(binlog.GetTimestampFrom(), 1),
+				EndTimestamp:    max(binlog.GetTimestampTo(), 1),
+				PayloadDataType: schemapb.DataType_Int64,
+			}, column)
+			column.Release()
+			if err != nil {
+				return err
+			}
+			key := path.Join(S3RootPath, "insert_log", fmt.Sprintf("%d/%d/%d/%d/%d",
+				info.GetCollectionID(), info.GetPartitionID(), info.GetID(), fieldBinlog.GetFieldID(), binlog.GetLogID()))
+			s.PutObject(S3Bucket, key, buf.Bytes())
+		}
+	}
+	return nil
+}
+
+// PutDeltalog generates deltalog file of segment in default bucket, deleting int64 pks at timestamp ts.
+func (s *S3) PutDeltalog(segment *SegmentBuilder, logID int64, ts uint64, pks ...int64) error {
+	info := segment.Build()
+	data := storage.NewDeltaData(schemapb.DataType_Int64, len(pks))
+	for _, pk := range pks {
+		data.Append(storage.NewInt64PrimaryKey(pk), ts)
+	}
+	buf := &bytes.Buffer{}
+	err := testutil.WriteDeltalog(buf, storage.DescriptorEventDataFixPart{
+		CollectionID:   info.GetCollectionID(),
+		PartitionID:    info.GetPartitionID(),
+		SegmentID:      info.GetID(),
+		StartTimestamp: ts,
+		EndTimestamp:   ts,
+	}, data)
+	if err != nil {
+		return err
+	}
+	key := path.Join(S3RootPath, "delta_log", fmt.Sprintf("%d/%d/%d/%d",
+		info.GetCollectionID(), info.GetPartitionID(), info.GetID(), logID))
+	s.PutObject(S3Bucket, key, buf.Bytes())
+	return nil
+}
+
+// PutIndexFile writes index data as binlog-wrapped index file of segment index in default bucket.
+func (s *S3) PutIndexFile(segIdx *SegmentIndexBuilder, key string, data []byte) error {
+	info := segIdx.Build()
+	buf := &bytes.Buffer{}
+	err := testutil.WriteIndexFile(buf, storage.DescriptorEventDataFixPart{
+		CollectionID:   info.GetCollectionID(),
+		PartitionID:    info.GetPartitionID(),
+		SegmentID:      info.GetSegmentID(),
+		StartTimestamp: 1,
+		EndTimestamp:   1,
+	}, key, data)
+	if err != nil {
+		return err
+	}
+	objKey := path.Join(S3RootPath, "index_files", fmt.Sprintf("%d/%d/%d/%d/%s",
+		info.GetBuildID(), info.GetIndexVersion(), info.GetPartitionID(), info.GetSegmentID(), key))
+	s.PutObject(S3Bucket, objKey, buf.Bytes())
+	return nil
+}
+
+type s3Error struct {
+	XMLName    xml.Name `xml:"Error"`
+	Code       string   `xml:"Code"`
+	Message    string   `xml:"Message"`
+	BucketName string   `xml:"BucketName,omitempty"`
+	Key        string   `xml:"Key,omitempty"`
+}
+
+type listBucketResult struct {
+	XMLName               xml.Name       `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+	Name                  string         `xml:"Name"`
+	Prefix                string         `xml:"Prefix"`
+	KeyCount              int            `xml:"KeyCount"`
+	MaxKeys               int            `xml:"MaxKeys"`
+	Delimiter             string         `xml:"Delimiter,omitempty"`
+	IsTruncated           bool           `xml:"IsTruncated"`
+	NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+	Contents              []objectInfo   `xml:"Contents"`
+	CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+}
+
+type objectInfo struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int    `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+type deleteRequest struct {
+	Objects []struct {
+		Key string `xml:"Key"`
+	} `xml:"Object"`
+}
+
+type deleteResult struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ DeleteResult"`
+	Deleted []struct {
+		Key string `xml:"Key"`
+	} `xml:"Deleted"`
+}
+
+// ServeHTTP implements http.Handler.
+func (s *S3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket == "" {
+		writeS3Error(w, r, http.StatusNotImplemented, "NotImplemented", "", "")
+		return
+	}
+
+	if key == "" {
+		s.serveBucket(w, r, bucket)
+		return
+	}
+
+	s.mut.RLock()
+	objects, ok := s.buckets[bucket]
+	s.mut.RUnlock()
+	if !ok {
+		writeS3Error(w, r, http.StatusNotFound, "NoSuchBucket", bucket, "")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := readS3Body(r)
+		if err != nil {
+			writeS3Error(w, r, http.StatusBadRequest, "IncompleteBody", bucket, key)
+			return
+		}
+		s.PutObject(bucket, key, data)
+		sum := md5.Sum(data)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		s.mut.RLock()
+		obj, ok := objects[key]
+		s.mut.RUnlock()
+		if !ok {
+			writeS3Error(w, r, http.StatusNotFound, "NoSuchKey", bucket, key)
+			return
+		}
+		w.Header().Set("ETag", `"`+obj.etag+`"`)
+		w.Header().Set("Content-Type", "application/octet-stream")
+		http.ServeContent(w, r, "", obj.modTime, bytes.NewReader(obj.data))
+	case http.MethodDelete:
+		s.mut.Lock()
+		delete(objects, key)
+		s.mut.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeS3Error(w, r, http.StatusNotImplemented, "NotImplemented", bucket, key)
+	}
+}
+
+func (s *S3) serveBucket(w http.ResponseWriter, r *http.Request, bucket string) {
+	query := r.URL.Query()
+	if r.Method == http.MethodPut {
+		s.CreateBucket(bucket)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	s.mut.RLock()
+	_, ok := s.buckets[bucket]
+	s.mut.RUnlock()
+	if !ok {
+		writeS3Error(w, r, http.StatusNotFound, "NoSuchBucket", bucket, "")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodGet && query.Has("location"):
+		writeXML(w, struct {
+			XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LocationConstraint"`
+		}{})
+	case r.Method == http.MethodGet:
+		s.listObjects(w, bucket, query.Get("prefix"), query.Get("delimiter"), query.Get("start-after"), query.Get("continuation-token"), query.Get("max-keys"))
+	case r.Method == http.MethodPost && query.Has("delete"):
+		req := &deleteRequest{}
+		if err := xml.NewDecoder(r.Body).Decode(req); err != nil {
+			writeS3Error(w, r, http.StatusBadRequest, "MalformedXML", bucket, "")
+			return
+		}
+		result := &deleteResult{}
+		s.mut.Lock()
+		for _, obj := range req.Objects {
+			delete(s.buckets[bucket], obj.Key)
+			result.Deleted = append(result.Deleted, struct {
+				Key string `xml:"Key"`
+			}{Key: obj.Key})
+		}
+		s.mut.Unlock()
+		writeXML(w, result)
+	default:
+		writeS3Error(w, r, http.StatusNotImplemented, "NotImplemented", bucket, "")
+	}
+}
+
+func (s *S3) listObjects(w http.ResponseWriter, bucket, prefix, delimiter, startAfter, token, maxKeysStr string) {
+	maxKeys := 1000
+	if n, err := strconv.Atoi(maxKeysStr); err == nil && n > 0 {
+		maxKeys = n
+	}
+	if token != "" {
+		startAfter = token
+	}
+
+	result := &listBucketResult{Name: bucket, Prefix: prefix, Delimiter: delimiter, MaxKeys: maxKeys}
+	seenPrefix := make(map[string]struct{})
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	for _, key := range s.keys(bucket, prefix) {
+		if key <= startAfter {
+			continue
+		}
+		if result.KeyCount >= maxKeys {
+			result.IsTruncated = true
+			break
+		}
+		if delimiter != "" {
+			if idx := strings.Index(key[len(prefix):], delimiter); idx >= 0 {
+				p := key[:len(prefix)+idx+len(delimiter)]
+				if _, ok := seenPrefix[p]; !ok {
+					seenPrefix[p] = struct{}{}
+					result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{Prefix: p})
+					result.KeyCount++
+					result.NextContinuationToken = key
+				}
+				continue
+			}
+		}
+		obj := s.buckets[bucket][key]
+		result.Contents = append(result.Contents, objectInfo{
+			Key:          key,
+			LastModified: obj.modTime.UTC().Format(time.RFC3339),
+			ETag:         `"` + obj.etag + `"`,
+			Size:         len(obj.data),
+			StorageClass: "STANDARD",
+		})
+		result.KeyCount++
+		result.NextContinuationToken = key
+	}
+	if !result.IsTruncated {
+		result.NextContinuationToken = ""
+	}
+	writeXML(w, result)
+}
+
+// readS3Body reads request body, `aws-chunked` streaming payload is decoded.
+func readS3Body(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+
+	// chunk format: hex(size);chunk-signature=...\r\n<data>\r\n
+	reader := bufio.NewReader(r.Body)
+	buf := &bytes.Buffer{}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeStr, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		size, err := strconv.ParseInt(sizeStr, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return buf.Bytes(), nil
+		}
+		if _, err := io.CopyN(buf, reader, size); err != nil {
+			return nil, err
+		}
+		if _, err := reader.Discard(2); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func writeXML(w http.ResponseWriter, v any) {
+	bs, err := xml.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	w.Write(bs)
+}
+
+func writeS3Error(w http.ResponseWriter, r *http.Request, status int, code, bucket, key string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead {
+		return
+	}
+	bs, _ := xml.Marshal(&s3Error{Code: code, Message: code, BucketName: bucket, Key: key})
+	w.Write([]byte(xml.Header))
+	w.Write(bs)
+}
@@ -0,0 +1,287 @@
+package states
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gosuri/uilive"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+)
+
+const (
+	findTypeInt64  = "int64"
+	findTypeString = "string"
+
+	// findKeyPath & findRawPath are the field paths reported for value found in key or undecoded value.
+	findKeyPath = "<key>"
+	findRawPath = "<raw>"
+)
+
+var digitsPattern = regexp.MustCompile(`\d+`)
+
+type FindParam struct {
+	framework.ParamBase `use:"find" desc:"find keys & decoded meta fields containing value, e.g. an id from milvus log"`
+	Value               string `arg:"0" name:"value" required:"true"`
+	Type                string `name:"type" default:"" enum:"int64,string" desc:"value type to match with, detected from value if not provided"`
+	Prefix              string `name:"prefix" default:"" desc:"meta prefix to scan, relative to instance meta path"`
+	Workers             int64  `name:"workers" default:"8" desc:"number of parallel decoding workers"`
+}
+
+// FindMatch is the key & field path where the value appears.
+type FindMatch struct {
+	Key       string
+	Type      string
+	FieldPath string
+	Value     string
+}
+
+type FindMatches struct {
+	framework.ListResultSet[*FindMatch]
+	scanned int64
+}
+
+func (rs *FindMatches) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatDefault, framework.FormatPlain:
+		sb := &strings.Builder{}
+		keys := make(map[string]struct{})
+		for _, match := range rs.Data {
+			keys[match.Key] = struct{}{}
+			fmt.Fprintf(sb, "%s\t[%s]\t%s = %s\n", match.Key, match.Type, match.FieldPath, match.Value)
+		}
+		fmt.Fprintf(sb, "--- Total matches: %d in %d keys, %d keys scanned\n", len(rs.Data), len(keys), rs.scanned)
+		return sb.String()
+	default:
+	}
+	return ""
+}
+
+// FindCommand scans instance meta in parallel and reports where value appears.
+func (s *InstanceState) FindCommand(ctx context.Context, p *FindParam) (*FindMatches, error) {
+	if p.Workers <= 0 {
+		return nil, errors.Newf("invalid workers number %d", p.Workers)
+	}
+	matcher, err := newFindMatcher(p.Value, p.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := path.Join(s.basePath, p.Prefix) + "/"
+
+	// walked is the number of keys read from meta store, scanned is the number of keys matched by workers
+	var walked, scanned atomic.Int64
+	var mut sync.Mutex
+	var matches []*FindMatch
+
+	type kvPair struct {
+		key   string
+		value []byte
+	}
+	pairs := make(chan kvPair, p.Workers*100)
+	var wg sync.WaitGroup
+	for i := 0; i < int(p.Workers); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pair := range pairs {
+				found := matcher.matchKV(strings.TrimPrefix(pair.key, s.basePath+"/"), pair.value)
+				for _, match := range found {
+					match.Key = pair.key
+				}
+				if len(found) > 0 {
+					mut.Lock()
+					matches = append(matches, found...)
+					mut.Unlock()
+				}
+				scanned.Add(1)
+			}
+		}()
+	}
+
+	progressDisplay := uilive.New()
+	progressFmt := "Scanning meta ... %d/%d keys scanned, %d matches\n"
+	progressDisplay.Start()
+	printProgress := func() {
+		current := int(scanned.Load())
+		mut.Lock()
+		found := len(matches)
+		mut.Unlock()
+		fmt.Fprintf(progressDisplay, progressFmt, current, walked.Load(), found)
+	}
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				printProgress()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	walkErr := s.client.WalkWithPrefix(ctx, prefix, 1000, func(k, v []byte) error {
+		select {
+		case pairs <- kvPair{key: string(k), value: bytes.Clone(v)}:
+			walked.Add(1)
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	close(pairs)
+	wg.Wait()
+	close(done)
+	printProgress()
+	progressDisplay.Stop()
+	if walkErr != nil {
+		return nil, errors.Wrap(walkErr, "failed to scan meta")
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Key != matches[j].Key {
+			return matches[i].Key < matches[j].Key
+		}
+		return matches[i].FieldPath < matches[j].FieldPath
+	})
+	rs := framework.NewListResult[FindMatches](matches)
+	rs.scanned = scanned.Load()
+	return rs, nil
+}
+
+// findMatcher matches int64 value with numeric fields & digit runs in text,
+// or string value as substring of string & bytes fields.
+type findMatcher struct {
+	raw    string
+	isInt  bool
+	intVal int64
+}
+
+func newFindMatcher(value, tp string) (*findMatcher, error) {
+	if value == "" {
+		return nil, errors.New("value to find shall not be empty")
+	}
+	m := &findMatcher{raw: value}
+	if tp == "" {
+		tp = findTypeString
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+			tp = findTypeInt64
+		}
+	}
+	if tp == findTypeInt64 {
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "value %s is not int64", value)
+		}
+		m.isInt, m.intVal, m.raw = true, v, strconv.FormatInt(v, 10)
+	}
+	return m, nil
+}
+
+// matchText matches substring for string value, whole digit run for int64 value.
+func (m *findMatcher) matchText(text string) bool {
+	if !strings.Contains(text, m.raw) {
+		return false
+	}
+	if !m.isInt {
+		return true
+	}
+	for _, digits := range digitsPattern.FindAllString(text, -1) {
+		if digits == m.raw {
+			return true
+		}
+	}
+	return false
+}
+
+// matchKV matches key & value, value is decoded with proto type registered for key prefix if possible.
+func (m *findMatcher) matchKV(relKey string, value []byte) []*FindMatch {
+	var result []*FindMatch
+	if m.matchText(relKey) {
+		result = append(result, &FindMatch{Type: "key", FieldPath: findKeyPath, Value: relKey})
+	}
+
+	msg, ok := common.NewMetaMessage(relKey)
+	if ok && proto.Unmarshal(value, msg) == nil {
+		typeName := string(msg.ProtoReflect().Descriptor().FullName())
+		m.matchMessage(msg.ProtoReflect(), "", func(fieldPath string, v string) {
+			result = append(result, &FindMatch{Type: typeName, FieldPath: fieldPath, Value: v})
+		})
+		return result
+	}
+
+	if m.matchText(string(value)) {
+		result = append(result, &FindMatch{Type: "raw", FieldPath: findRawPath, Value: truncateValue(string(value), 128)})
+	}
+	return result
+}
+
+func (m *findMatcher) matchMessage(msg protoreflect.Message, prefix string, report func(fieldPath string, v string)) {
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := prefix + string(fd.Name())
+		switch {
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				m.matchValue(fd, list.Get(i), fmt.Sprintf("%s[%d]", name, i), report)
+			}
+		case fd.IsMap():
+			v.Map().Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+				elemPath := fmt.Sprintf("%s[%v]", name, key.Interface())
+				m.matchValue(fd.MapKey(), key.Value(), elemPath+".<key>", report)
+				m.matchValue(fd.MapValue(), value, elemPath, report)
+				return true
+			})
+		default:
+			m.matchValue(fd, v, name, report)
+		}
+		return true
+	})
+}
+
+func (m *findMatcher) matchValue(fd protoreflect.FieldDescriptor, v protoreflect.Value, fieldPath string, report func(fieldPath string, v string)) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		m.matchMessage(v.Message(), fieldPath+".", report)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if m.isInt && v.Int() == m.intVal {
+			report(fieldPath, strconv.FormatInt(v.Int(), 10))
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if m.isInt && m.intVal >= 0 && v.Uint() == uint64(m.intVal) {
+			report(fieldPath, strconv.FormatUint(v.Uint(), 10))
+		}
+	case protoreflect.StringKind:
+		if m.matchText(v.String()) {
+			report(fieldPath, truncateValue(v.String(), 128))
+		}
+	case protoreflect.BytesKind:
+		if m.matchText(string(v.Bytes())) {
+			report(fieldPath, truncateValue(string(v.Bytes()), 128))
+		}
+	}
+}
+
+func truncateValue(v string, limit int) string {
+	if len(v) <= limit {
+		return v
+	}
+	return v[:limit] + "..."
+}
@@ -0,0 +1,54 @@
+package states
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+)
+
+func TestFind(t *testing.T) {
+	ctx := context.Background()
+	c := fakecluster.New(t)
+	segment := fakecluster.NewSegment(1001, 100, 101).WithBinlog(102, 1001)
+	c.AddCollection(fakecluster.NewCollection(100, "coll").WithPartition(101, "_default")).
+		AddSegment(segment).
+		AddSegment(fakecluster.NewSegment(1002, 100, 101).WithCompactionFrom(1001)).
+		AddSegmentIndex(fakecluster.NewSegmentIndex(segment, 10, 11))
+	c.Put("session/unknown", `{"ServerID": 1001}`)
+	s := &InstanceState{client: c.KV(), basePath: c.BasePath()}
+
+	rs, err := s.FindCommand(ctx, &FindParam{Value: "1001", Workers: 4})
+	require.NoError(t, err)
+	found := lo.Map(rs.Data, func(match *FindMatch, _ int) string { return match.Type + ":" + match.FieldPath })
+	assert.Contains(t, found, "key:"+findKeyPath)
+	assert.Contains(t, found, "milvus.proto.data.SegmentInfo:ID")
+	assert.Contains(t, found, "milvus.proto.data.SegmentInfo:compactionFrom[0]")
+	assert.Contains(t, found, "milvus.proto.data.FieldBinlog:binlogs[0].logID")
+	assert.Contains(t, found, "milvus.proto.index.SegmentIndex:segmentID")
+	assert.Contains(t, found, "raw:"+findRawPath)
+	// segment 10010 or collection 100 shall not match
+	assert.NotContains(t, found, "milvus.proto.etcd.CollectionInfo:ID")
+	keys, _, err := c.KV().LoadWithPrefix(ctx, c.BasePath()+"/")
+	require.NoError(t, err)
+	assert.EqualValues(t, len(keys), rs.scanned)
+
+	rs, err = s.FindCommand(ctx, &FindParam{Value: "dml_0_100v0", Workers: 2})
+	require.NoError(t, err)
+	assert.True(t, lo.ContainsBy(rs.Data, func(match *FindMatch) bool {
+		return match.Type == "milvus.proto.data.SegmentInfo" && match.FieldPath == "insert_channel"
+	}))
+
+	_, err = s.FindCommand(ctx, &FindParam{Value: "abc", Type: findTypeInt64, Workers: 1})
+	assert.Error(t, err)
+
+	// scan stops when context canceled
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = s.FindCommand(canceled, &FindParam{Value: "1001", Workers: 1})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -17,7 +17,7 @@ import (
 
 type ForceReleaseParam struct {
 	framework.ParamBase `use:"force-release"`
-	CollectionID        int64 `name:"collection" default:"0" desc:"collection id to force release"`
+	CollectionID        int64 `name:"collection" resolve:"collection" default:"0" desc:"collection id to force release"`
 	All                 bool  `name:"all" default:"false" desc:"force release all collections loaded"`
 }
 
@@ -18,7 +18,7 @@ import (
 )
 
 type HealthzCheckParam struct {
-	framework.ParamBase `use:"healthz-check" desc:"perform healthz check for connect instance"`
+	framework.ParamBase `use:"healthz-check" desc:"perform healthz check for connect instance" readonly:"true"`
 }
 
 type HealthzCheckReports struct {
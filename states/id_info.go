@@ -0,0 +1,110 @@
+package states
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
+	"github.com/milvus-io/birdwatcher/utils"
+)
+
+// idKeyLayout describes meta key layout, roles are the id roles of key parts after prefix.
+type idKeyLayout struct {
+	object string
+	prefix string
+	roles  []string
+}
+
+var idKeyLayouts = []idKeyLayout{
+	{object: "database", prefix: common.DataBaseMetaPrefix, roles: []string{"database"}},
+	{object: "collection", prefix: common.DBCollectionMetaPrefix, roles: []string{"database", "collection"}},
+	{object: "collection", prefix: common.CollectionMetaPrefix, roles: []string{"collection"}},
+	{object: "partition", prefix: path.Join(common.RCPrefix, common.PartitionPrefix), roles: []string{"collection", "partition"}},
+	{object: "alias", prefix: common.AliasPrefixDB, roles: []string{"database"}},
+	{object: "segment", prefix: path.Join(common.DCPrefix, common.SegmentMetaPrefix), roles: []string{"collection", "partition", "segment"}},
+	{object: "index", prefix: common.IndexPrefix, roles: []string{"collection", "index"}},
+	{object: "segment index", prefix: common.SegmentIndexPrefix, roles: []string{"collection", "partition", "segment", "build"}},
+	{object: "compaction task", prefix: path.Join(common.DCPrefix, common.CompactionTaskPrefix), roles: []string{"type", "trigger", "plan"}},
+	{object: "partition stats", prefix: path.Join(common.DCPrefix, common.PartitionStatsPrefix), roles: []string{"collection", "partition", "channel", "version"}},
+	{object: "analyze task", prefix: path.Join(common.DCPrefix, common.AnalyzeTaskPrefix), roles: []string{"task"}},
+	{object: "stats task", prefix: path.Join(common.DCPrefix, common.StatsTaskPrefix), roles: []string{"task"}},
+	{object: "import job", prefix: common.ImportJobPrefix, roles: []string{"job"}},
+	{object: "preimport task", prefix: common.PreImportTaskPrefix, roles: []string{"job", "task"}},
+	{object: "import task", prefix: common.ImportTaskPrefix, roles: []string{"job", "task"}},
+	{object: "replica", prefix: common.ReplicaPrefix, roles: []string{"collection", "replica"}},
+	{object: "collection loaded", prefix: common.CollectionLoadPrefixV2, roles: []string{"collection"}},
+	{object: "partition loaded", prefix: common.PartitionLoadedPrefix, roles: []string{"collection", "partition"}},
+}
+
+// IDReference is the meta object referencing id.
+type IDReference struct {
+	Object string
+	Role   string
+	Key    string
+}
+
+type IDReferences struct {
+	framework.ListResultSet[*IDReference]
+	id int64
+}
+
+func (rs *IDReferences) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatDefault, framework.FormatPlain:
+		sb := &strings.Builder{}
+		allocated, _ := utils.ParseTS(uint64(rs.id))
+		fmt.Fprintf(sb, "ID: %d, allocated around %s\n", rs.id, allocated.Format(time.RFC3339))
+		for _, ref := range rs.Data {
+			fmt.Fprintf(sb, "%s id of %s\t%s\n", ref.Role, ref.Object, ref.Key)
+		}
+		fmt.Fprintf(sb, "--- Total references: %d\n", len(rs.Data))
+		return sb.String()
+	default:
+	}
+	return ""
+}
+
+type IDInfoParam struct {
+	framework.ParamBase `use:"id info" desc:"find meta objects using provided id, e.g. collection, partition, segment, index, build, plan or job"`
+	ID                  int64 `arg:"0" name:"id" required:"true"`
+}
+
+// IDInfoCommand returns `id info` command.
+func (s *InstanceState) IDInfoCommand(ctx context.Context, p *IDInfoParam) (*IDReferences, error) {
+	refs, err := findIDReferences(ctx, s.client, s.basePath, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	rs := framework.NewListResult[IDReferences](refs)
+	rs.id = p.ID
+	return rs, nil
+}
+
+// findIDReferences scans keys of known meta prefixes and reports key parts matching id.
+func findIDReferences(ctx context.Context, cli kv.MetaKV, basePath string, id int64) ([]*IDReference, error) {
+	target := strconv.FormatInt(id, 10)
+	var refs []*IDReference
+	for _, layout := range idKeyLayouts {
+		prefix := path.Join(basePath, layout.prefix) + "/"
+		keys, _, err := cli.LoadWithPrefix(ctx, prefix, kv.WithKeysOnly())
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			parts := strings.Split(strings.TrimPrefix(key, prefix), "/")
+			for i, part := range parts {
+				if part != target || i >= len(layout.roles) {
+					continue
+				}
+				refs = append(refs, &IDReference{Object: layout.object, Role: layout.roles[i], Key: key})
+			}
+		}
+	}
+	return refs, nil
+}
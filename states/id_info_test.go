@@ -0,0 +1,58 @@
+package states
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+	"github.com/milvus-io/birdwatcher/utils"
+)
+
+func TestIDInfo(t *testing.T) {
+	ctx := context.Background()
+	c := fakecluster.New(t)
+	segment := fakecluster.NewSegment(1001, 100, 101)
+	c.AddCollection(fakecluster.NewCollection(100, "coll").WithPartition(101, "_default")).
+		AddSegment(segment).
+		AddIndex(fakecluster.NewIndex(100, 102, 10, "idx")).
+		AddSegmentIndex(fakecluster.NewSegmentIndex(segment, 10, 1001))
+
+	s := &InstanceState{client: c.KV(), basePath: c.BasePath()}
+	rs, err := s.IDInfoCommand(ctx, &IDInfoParam{ID: 1001})
+	require.NoError(t, err)
+	refs := lo.Map(rs.Data, func(ref *IDReference, _ int) string { return ref.Role + " id of " + ref.Object })
+	assert.ElementsMatch(t, []string{"segment id of segment", "segment id of segment index", "build id of segment index"}, refs)
+
+	rs, err = s.IDInfoCommand(ctx, &IDInfoParam{ID: 101})
+	require.NoError(t, err)
+	assert.Contains(t, lo.Map(rs.Data, func(ref *IDReference, _ int) string { return ref.Object }), "partition")
+}
+
+func TestTSCommands(t *testing.T) {
+	ctx := context.Background()
+	app := &ApplicationState{}
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	ts := utils.MakeTS(now, 3)
+
+	rs, err := app.TSMakeCommand(ctx, &TSMakeParam{Time: now, Logical: 3})
+	require.NoError(t, err)
+	assert.Equal(t, ts, rs.Data[0].TS)
+
+	rs, err = app.TSParseCommand(ctx, &TSParseParam{TS: []string{"449664672706691075"}})
+	require.NoError(t, err)
+	physical, logical := utils.ParseTS(449664672706691075)
+	assert.True(t, physical.Equal(rs.Data[0].Physical))
+	assert.Equal(t, logical, rs.Data[0].Logical)
+
+	_, err = app.TSParseCommand(ctx, &TSParseParam{TS: []string{"not-a-ts"}})
+	assert.Error(t, err)
+
+	from, err := parseTSArg("2024-05-01T08:00:00Z")
+	require.NoError(t, err)
+	assert.Equal(t, utils.MakeTS(now, 0), from)
+}
@@ -0,0 +1,402 @@
+package states
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/minio/minio-go/v7"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/storage"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+)
+
+const (
+	indexParamsKey = "indexParams"
+	sliceMetaKey   = "SLICE_META"
+)
+
+type IndexInspectParam struct {
+	framework.ParamBase `use:"index inspect" desc:"inspect index files of finished segment index, reporting index type, params, vectors, sizes and checksums"`
+	CollectionID        int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id to inspect"`
+	SegmentID           int64  `name:"segment" default:"0" desc:"segment id to inspect"`
+	IndexID             int64  `name:"indexID" default:"0" desc:"index id to inspect"`
+	BuildID             int64  `name:"buildID" default:"0" desc:"build id to inspect"`
+	CompareSegment      bool   `name:"compare-segment" default:"false" desc:"verify index row count matches segment row count"`
+	Local               string `name:"local" default:"" desc:"directory of downloaded index files named by file key, instead of object storage"`
+}
+
+// IndexFileInfo is one object of segment index file set.
+type IndexFileInfo struct {
+	Key      string `json:"key"`
+	Size     int64  `json:"size"`
+	DataSize int64  `json:"data_size"`
+	MD5      string `json:"md5"`
+}
+
+// IndexBinary is one index binary, sliced ones are reassembled.
+type IndexBinary struct {
+	Name    string `json:"name"`
+	Slices  int    `json:"slices"`
+	Size    int64  `json:"size"`
+	CRC32   string `json:"crc32"`
+	Format  string `json:"format"`
+	Dim     int64  `json:"dim"`
+	Vectors int64  `json:"vectors"`
+}
+
+// IndexInspection is the inspection result of one segment index build.
+type IndexInspection struct {
+	CollectionID int64             `json:"collection_id"`
+	Collection   string            `json:"collection"`
+	PartitionID  int64             `json:"partition_id"`
+	SegmentID    int64             `json:"segment_id"`
+	IndexID      int64             `json:"index_id"`
+	IndexName    string            `json:"index_name"`
+	BuildID      int64             `json:"build_id"`
+	IndexVersion int64             `json:"index_version"`
+	IndexType    string            `json:"index_type"`
+	Family       string            `json:"family"`
+	Params       map[string]string `json:"params"`
+	MetaRows     int64             `json:"meta_rows"`
+	IndexRows    int64             `json:"index_rows"`
+	SegmentRows  int64             `json:"segment_rows"`
+	Files        []*IndexFileInfo  `json:"files"`
+	Binaries     []*IndexBinary    `json:"binaries"`
+	Warnings     []string          `json:"warnings"`
+}
+
+func (i *IndexInspection) warn(format string, args ...any) {
+	i.Warnings = append(i.Warnings, fmt.Sprintf(format, args...))
+}
+
+type IndexInspections struct {
+	framework.ListResultSet[*IndexInspection]
+}
+
+func (rs *IndexInspections) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatJSON:
+		bs, err := json.MarshalIndent(rs.Data, "", "  ")
+		if err != nil {
+			return err.Error()
+		}
+		return string(bs)
+	case framework.FormatDefault, framework.FormatPlain:
+		sb := &strings.Builder{}
+		for _, r := range rs.Data {
+			printIndexInspection(sb, r)
+		}
+		fmt.Fprintf(sb, "--- Total segment index inspected: %d\n", len(rs.Data))
+		return sb.String()
+	default:
+	}
+	return ""
+}
+
+func printIndexInspection(sb *strings.Builder, r *IndexInspection) {
+	fmt.Fprintln(sb, "==================================================================")
+	fmt.Fprintf(sb, "Collection: %s\tPartition: %d\tSegment: %d\n", r.Collection, r.PartitionID, r.SegmentID)
+	fmt.Fprintf(sb, "Index: %s(%d)\tBuild ID: %d\tIndex Version: %d\n", r.IndexName, r.IndexID, r.BuildID, r.IndexVersion)
+	fmt.Fprintf(sb, "Index Type: %s\tFamily: %s\n", r.IndexType, r.Family)
+	keys := lo.Keys(r.Params)
+	sort.Strings(keys)
+	fmt.Fprintf(sb, "Params: %s\n", strings.Join(lo.Map(keys, func(k string, _ int) string {
+		return fmt.Sprintf("%s=%s", k, r.Params[k])
+	}), ", "))
+	fmt.Fprintf(sb, "Rows: meta %d, index %s", r.MetaRows, rowsText(r.IndexRows))
+	if r.SegmentRows >= 0 {
+		fmt.Fprintf(sb, ", segment %d", r.SegmentRows)
+	}
+	fmt.Fprintln(sb)
+	fmt.Fprintln(sb, "Files:")
+	for _, f := range r.Files {
+		fmt.Fprintf(sb, "\t%-24s size %-20s data %-20s md5 %s\n", f.Key, hrSize(f.Size), hrSize(f.DataSize), f.MD5)
+	}
+	fmt.Fprintln(sb, "Binaries:")
+	for _, b := range r.Binaries {
+		fmt.Fprintf(sb, "\t%-24s slices %d\tsize %-20s crc32 %s\t%s", b.Name, b.Slices, hrSize(b.Size), b.CRC32, b.Format)
+		if b.Dim >= 0 {
+			fmt.Fprintf(sb, " dim %d", b.Dim)
+		}
+		if b.Vectors >= 0 {
+			fmt.Fprintf(sb, " vectors %d", b.Vectors)
+		}
+		fmt.Fprintln(sb)
+	}
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(sb, "[Warning] %s\n", warning)
+	}
+}
+
+func rowsText(rows int64) string {
+	if rows < 0 {
+		return "unknown"
+	}
+	return fmt.Sprint(rows)
+}
+
+// IndexInspectCommand reads index file sets of segment index from object storage or local directory,
+// decodes and reassembles index binaries and reports index headers.
+func (s *InstanceState) IndexInspectCommand(ctx context.Context, p *IndexInspectParam) (*IndexInspections, error) {
+	if p.CollectionID == 0 && p.SegmentID == 0 && p.BuildID == 0 {
+		return nil, errors.New("collection, segment or buildID shall be provided")
+	}
+	segIdxes, err := common.ListSegmentIndex(ctx, s.client, s.basePath, func(segIdx *models.SegmentIndex) bool {
+		info := segIdx.GetProto()
+		return (p.CollectionID == 0 || info.GetCollectionID() == p.CollectionID) &&
+			(p.SegmentID == 0 || info.GetSegmentID() == p.SegmentID) &&
+			(p.IndexID == 0 || info.GetIndexID() == p.IndexID) &&
+			(p.BuildID == 0 || info.GetBuildID() == p.BuildID) &&
+			!info.GetDeleted() && info.GetState() == commonpb.IndexState_Finished
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list segment indexes")
+	}
+	if p.Local != "" && len(segIdxes) > 1 {
+		return nil, errors.Newf("%d segment indexes found, local files could only be inspected with one, use --buildID to specify", len(segIdxes))
+	}
+	indexes, err := common.ListIndex(ctx, s.client, s.basePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list indexes")
+	}
+	fieldIndexes := lo.SliceToMap(indexes, func(index *models.FieldIndex) (int64, *models.FieldIndex) {
+		return index.GetProto().GetIndexInfo().GetIndexID(), index
+	})
+	segmentRows := make(map[int64]int64)
+	if p.CompareSegment {
+		segmentIDs := lo.SliceToMap(segIdxes, func(segIdx *models.SegmentIndex) (int64, struct{}) {
+			return segIdx.GetProto().GetSegmentID(), struct{}{}
+		})
+		segments, err := common.ListSegments(ctx, s.client, s.basePath, func(segment *models.Segment) bool {
+			_, ok := segmentIDs[segment.ID]
+			return ok
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list segments")
+		}
+		for _, segment := range segments {
+			segmentRows[segment.ID] = segment.GetNumOfRows()
+		}
+	}
+
+	var read func(segIdx *models.SegmentIndex, key string) ([]byte, error)
+	if p.Local != "" {
+		if err := testFolder(p.Local); err != nil {
+			return nil, err
+		}
+		read = func(_ *models.SegmentIndex, key string) ([]byte, error) {
+			return os.ReadFile(path.Join(p.Local, key))
+		}
+	} else if len(segIdxes) > 0 {
+		minioClient, bucketName, rootPath, err := s.GetMinioClientFromCfg(ctx)
+		if err != nil {
+			return nil, err
+		}
+		read = func(segIdx *models.SegmentIndex, key string) ([]byte, error) {
+			info := segIdx.GetProto()
+			objKey := path.Join(rootPath, "index_files", fmt.Sprint(info.GetBuildID()), fmt.Sprint(info.GetIndexVersion()),
+				fmt.Sprint(info.GetPartitionID()), fmt.Sprint(info.GetSegmentID()), key)
+			obj, err := minioClient.GetObject(ctx, bucketName, objKey, minio.GetObjectOptions{})
+			if err != nil {
+				return nil, err
+			}
+			defer obj.Close()
+			return io.ReadAll(obj)
+		}
+	}
+
+	names := common.CollectionNames(ctx, s.client, s.basePath)
+	var results []*IndexInspection
+	for _, segIdx := range segIdxes {
+		r := inspectSegmentIndex(segIdx, fieldIndexes[segIdx.GetProto().GetIndexID()], func(key string) ([]byte, error) {
+			return read(segIdx, key)
+		})
+		r.Collection = common.CollectionLabel(names, r.CollectionID)
+		r.SegmentRows = -1
+		if rows, ok := segmentRows[r.SegmentID]; ok {
+			r.SegmentRows = rows
+		}
+		if p.CompareSegment {
+			compareSegmentRows(r)
+		}
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].SegmentID != results[j].SegmentID {
+			return results[i].SegmentID < results[j].SegmentID
+		}
+		return results[i].BuildID < results[j].BuildID
+	})
+	return framework.NewListResult[IndexInspections](results), nil
+}
+
+// compareSegmentRows verifies index row count, header one preferred, matches segment row count.
+func compareSegmentRows(r *IndexInspection) {
+	if r.SegmentRows < 0 {
+		r.warn("segment %d meta not found", r.SegmentID)
+		return
+	}
+	rows := r.IndexRows
+	if rows < 0 {
+		rows = r.MetaRows
+	}
+	if rows != r.SegmentRows {
+		r.warn("index rows %d do not match segment rows %d", rows, r.SegmentRows)
+	}
+}
+
+// inspectSegmentIndex reads & decodes all files of segment index, sliced binaries are reassembled
+// with SLICE_META before header is parsed.
+func inspectSegmentIndex(segIdx *models.SegmentIndex, fieldIndex *models.FieldIndex, read func(key string) ([]byte, error)) *IndexInspection {
+	info := segIdx.GetProto()
+	r := &IndexInspection{
+		CollectionID: info.GetCollectionID(),
+		PartitionID:  info.GetPartitionID(),
+		SegmentID:    info.GetSegmentID(),
+		IndexID:      info.GetIndexID(),
+		BuildID:      info.GetBuildID(),
+		IndexVersion: info.GetIndexVersion(),
+		MetaRows:     info.GetNumRows(),
+		IndexRows:    -1,
+		Params:       make(map[string]string),
+	}
+	if fieldIndex != nil {
+		r.IndexName = fieldIndex.GetProto().GetIndexInfo().GetIndexName()
+		for _, kv := range fieldIndex.GetProto().GetIndexInfo().GetIndexParams() {
+			r.Params[kv.GetKey()] = kv.GetValue()
+		}
+	} else {
+		r.warn("field index %d meta not found", r.IndexID)
+	}
+
+	contents := make(map[string][]byte)
+	var keys []string
+	for _, key := range info.GetIndexFileKeys() {
+		raw, err := read(key)
+		if err != nil {
+			r.warn("failed to read index file %s: %s", key, err.Error())
+			continue
+		}
+		sum := md5.Sum(raw)
+		file := &IndexFileInfo{Key: key, Size: int64(len(raw)), MD5: hex.EncodeToString(sum[:])}
+		r.Files = append(r.Files, file)
+		indexFile, err := storage.ReadIndexFile(bytes.NewReader(raw))
+		if err != nil {
+			r.warn("failed to decode index file %s: %s", key, err.Error())
+			continue
+		}
+		file.DataSize = int64(len(indexFile.Data))
+		if indexFile.Key != "" && indexFile.Key != key {
+			r.warn("index file %s records key %s", key, indexFile.Key)
+		}
+		contents[key] = indexFile.Data
+		keys = append(keys, key)
+	}
+
+	if data, ok := contents[indexParamsKey]; ok {
+		params := make(map[string]string)
+		if err := json.Unmarshal(data, &params); err != nil {
+			r.warn("failed to parse indexParams: %s", err.Error())
+		}
+		for k, v := range params {
+			r.Params[k] = v
+		}
+	}
+	r.IndexType = r.Params["index_type"]
+
+	assembled := make(map[string]struct{})
+	if data, ok := contents[sliceMetaKey]; ok {
+		meta := &SliceMeta{}
+		if err := json.Unmarshal(bytes.Trim(data, "\x00"), meta); err != nil {
+			r.warn("failed to parse SLICE_META: %s", err.Error())
+		}
+		for _, item := range meta.Meta {
+			binary := &IndexBinary{Name: item.Name, Slices: item.SliceNum}
+			var buf []byte
+			complete := true
+			for i := 0; i < item.SliceNum; i++ {
+				key := fmt.Sprintf("%s_%d", item.Name, i)
+				slice, ok := contents[key]
+				if !ok {
+					r.warn("slice %s of %s not found", key, item.Name)
+					complete = false
+					continue
+				}
+				assembled[key] = struct{}{}
+				buf = append(buf, slice...)
+			}
+			if complete && int64(len(buf)) != item.TotalLength {
+				r.warn("assembled %s length %d does not match SLICE_META total_len %d", item.Name, len(buf), item.TotalLength)
+			}
+			r.addBinary(binary, buf)
+		}
+	}
+	for _, key := range keys {
+		if _, ok := assembled[key]; ok || key == indexParamsKey || key == sliceMetaKey {
+			continue
+		}
+		r.addBinary(&IndexBinary{Name: key, Slices: 1}, contents[key])
+	}
+
+	r.Family = indexFamily(r.IndexType, r.Binaries)
+	if r.IndexRows >= 0 && r.IndexRows != r.MetaRows {
+		r.warn("index header rows %d do not match segment index meta rows %d", r.IndexRows, r.MetaRows)
+	}
+	return r
+}
+
+func (r *IndexInspection) addBinary(binary *IndexBinary, data []byte) {
+	header := storage.ParseIndexHeader(binary.Name, data)
+	binary.Size = int64(len(data))
+	binary.CRC32 = fmt.Sprintf("%08x", crc32.ChecksumIEEE(data))
+	binary.Format, binary.Dim, binary.Vectors = header.Format, header.Dim, header.Vectors
+	if binary.Vectors >= 0 && r.IndexRows < 0 {
+		r.IndexRows = binary.Vectors
+	}
+	r.Binaries = append(r.Binaries, binary)
+}
+
+// indexFamily returns index family with index type param, falls back to binary formats.
+func indexFamily(indexType string, binaries []*IndexBinary) string {
+	upper := strings.ToUpper(indexType)
+	switch {
+	case strings.Contains(upper, "HNSW"):
+		return "Knowhere HNSW"
+	case strings.HasPrefix(upper, "IVF") || strings.HasPrefix(upper, "BIN_IVF"):
+		return "Knowhere IVF"
+	case upper == "DISKANN":
+		return "Knowhere DiskANN"
+	case strings.Contains(upper, "FLAT"):
+		return "Knowhere FLAT"
+	case lo.Contains([]string{"STL_SORT", "TRIE", "MARISA-TRIE", "INVERTED", "BITMAP", "HYBRID", "SORTED"}, upper):
+		return "scalar"
+	}
+	for _, b := range binaries {
+		switch {
+		case strings.Contains(b.Format, "HNSW"):
+			return "Knowhere HNSW"
+		case strings.Contains(b.Format, "IVF"):
+			return "Knowhere IVF"
+		case strings.HasPrefix(b.Format, "DiskANN"):
+			return "Knowhere DiskANN"
+		case strings.HasPrefix(b.Format, "scalar"):
+			return "scalar"
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,92 @@
+package states
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+)
+
+func TestIndexInspect(t *testing.T) {
+	ctx := context.Background()
+	c := fakecluster.New(t)
+	s3 := c.StartS3()
+	c.StartServer(fakecluster.RoleRootCoord, 1)
+
+	vector := fakecluster.NewSegment(1001, 100, 101).WithRows(10)
+	scalar := fakecluster.NewSegment(1002, 100, 101).WithRows(20)
+	hnsw := fakecluster.NewSegmentIndex(vector, 10, 11).WithFiles("SLICE_META", "HNSW_0", "HNSW_1")
+	sorted := fakecluster.NewSegmentIndex(scalar, 20, 21).WithFiles("index_length", "index_data", "missing")
+	c.AddCollection(fakecluster.NewCollection(100, "coll").WithPrimaryKey(100, "pk").WithVector(101, "vec", 4).WithPartition(101, "_default")).
+		AddSegment(vector).AddSegment(scalar).
+		AddIndex(fakecluster.NewIndex(100, 101, 10, "vec_idx").WithParams("index_type", "HNSW", "metric_type", "L2")).
+		AddIndex(fakecluster.NewIndex(100, 100, 20, "pk_idx").WithParams("index_type", "STL_SORT")).
+		AddSegmentIndex(hnsw).AddSegmentIndex(sorted)
+
+	// faiss header: fourcc, dim, ntotal, followed by index data
+	header := make([]byte, 64)
+	copy(header, "IHNf")
+	binary.LittleEndian.PutUint32(header[4:], 4)
+	binary.LittleEndian.PutUint64(header[8:], 10)
+	require.NoError(t, s3.PutIndexFile(hnsw, "HNSW_0", header[:40]))
+	require.NoError(t, s3.PutIndexFile(hnsw, "HNSW_1", header[40:]))
+	require.NoError(t, s3.PutIndexFile(hnsw, "SLICE_META", []byte(`{"meta":[{"name":"HNSW","slice_num":2,"total_len":64}]}`)))
+
+	length := make([]byte, 8)
+	binary.LittleEndian.PutUint64(length, 10)
+	require.NoError(t, s3.PutIndexFile(sorted, "index_length", length))
+	require.NoError(t, s3.PutIndexFile(sorted, "index_data", make([]byte, 80)))
+
+	s := &InstanceState{client: c.KV(), basePath: c.BasePath()}
+	_, err := s.IndexInspectCommand(ctx, &IndexInspectParam{})
+	assert.Error(t, err)
+
+	rs, err := s.IndexInspectCommand(ctx, &IndexInspectParam{CollectionID: 100, CompareSegment: true})
+	require.NoError(t, err)
+	require.Len(t, rs.Data, 2)
+
+	vec := rs.Data[0]
+	assert.Equal(t, "HNSW", vec.IndexType)
+	assert.Equal(t, "Knowhere HNSW", vec.Family)
+	assert.Equal(t, "L2", vec.Params["metric_type"])
+	assert.Len(t, vec.Files, 3)
+	require.Len(t, vec.Binaries, 1)
+	assert.Equal(t, "HNSW", vec.Binaries[0].Name)
+	assert.Equal(t, 2, vec.Binaries[0].Slices)
+	assert.EqualValues(t, 64, vec.Binaries[0].Size)
+	assert.Equal(t, "faiss IndexHNSWFlat", vec.Binaries[0].Format)
+	assert.EqualValues(t, 4, vec.Binaries[0].Dim)
+	assert.EqualValues(t, 10, vec.IndexRows)
+	assert.EqualValues(t, 10, vec.SegmentRows)
+	assert.Empty(t, vec.Warnings)
+
+	pk := rs.Data[1]
+	assert.Equal(t, "scalar", pk.Family)
+	assert.EqualValues(t, 10, pk.IndexRows)
+	assert.Len(t, pk.Binaries, 2)
+	assert.Contains(t, pk.Warnings, "index header rows 10 do not match segment index meta rows 20")
+	assert.Contains(t, pk.Warnings, "index rows 10 do not match segment rows 20")
+	assert.Len(t, pk.Warnings, 3)
+	assert.Contains(t, rs.PrintAs(framework.FormatDefault), "Collection: default.coll(100)")
+
+	// local files
+	dir := t.TempDir()
+	for _, key := range []string{"index_length", "index_data"} {
+		obj, ok := s3.GetObject(fakecluster.S3Bucket, fmt.Sprintf("files/index_files/21/1/101/1002/%s", key))
+		require.True(t, ok)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, key), obj, 0o600))
+	}
+	rs, err = s.IndexInspectCommand(ctx, &IndexInspectParam{BuildID: 21, Local: dir})
+	require.NoError(t, err)
+	require.Len(t, rs.Data, 1)
+	assert.Len(t, rs.Data[0].Files, 2)
+	assert.EqualValues(t, 10, rs.Data[0].IndexRows)
+}
@@ -25,7 +25,7 @@ type InspectPKParam struct {
 	Remote              bool   `name:"remote" default:"false" desc:"inspect remote pk"`
 	LocalPath           string `name:"localPath" default:"" desc:"local pk file path"`
 	// remote related params
-	CollectionID int64  `name:"collection" default:"0" desc:"collection id to inspect"`
+	CollectionID int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id to inspect"`
 	SegmentID    int64  `name:"segment" default:"0" desc:"segment id to inspect"`
 	MinioAddress string `name:"minioAddr" default:"" desc:"the minio address to override, leave empty to use milvus.yaml value"`
 
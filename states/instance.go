@@ -7,6 +7,8 @@ import (
 	"path"
 	"time"
 
+	"github.com/cockroachdb/errors"
+
 	"github.com/milvus-io/birdwatcher/configs"
 	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/states/etcd"
@@ -31,6 +33,8 @@ type InstanceState struct {
 	etcdState framework.State
 	config    *configs.Config
 	basePath  string
+	// milvusConfig is the milvus config provided when connecting, could be nil
+	milvusConfig *configs.MilvusConfig
 }
 
 func (s *InstanceState) Close() {
@@ -109,7 +113,16 @@ func (s *InstanceState) DryModeCommand(ctx context.Context, p *DryModeParam) {
 	s.SetNext(etcdTag, s.etcdState)
 }
 
-func getInstanceState(parent *framework.CmdState, cli metakv.MetaKV, instanceName, metaPath string, etcdState framework.State, config *configs.Config) framework.State {
+// useInstance switches to another milvus instance on the same metastore connection.
+func (s *InstanceState) useInstance(ctx context.Context, p *UseInstanceParam) (framework.State, error) {
+	ms, ok := s.etcdState.(metaStoreState)
+	if !ok {
+		return nil, errors.Newf("instance %s not connected", p.InstanceName)
+	}
+	return ms.useInstance(ctx, p)
+}
+
+func getInstanceState(parent *framework.CmdState, cli metakv.MetaKV, instanceName, metaPath string, etcdState framework.State, config *configs.Config, milvusConfig *configs.MilvusConfig) framework.State {
 	var kv metakv.MetaKV
 	name := fmt.Sprintf("audit_%s.log", time.Now().Format("2006_0102_150405"))
 	file, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
@@ -126,16 +139,17 @@ func getInstanceState(parent *framework.CmdState, cli metakv.MetaKV, instanceNam
 	state := &InstanceState{
 		CmdState:        parent.Spawn(fmt.Sprintf("Milvus(%s)", instanceName)),
 		ComponentShow:   show.NewComponent(cli, config, instanceName, metaPath),
-		ComponentRemove: remove.NewComponent(cli, config, basePath),
+		ComponentRemove: remove.NewComponent(kv, config, basePath),
 		ComponentRepair: repair.NewComponent(cli, config, basePath),
 		ComponentSet:    set.NewComponent(cli, config, basePath),
 		instanceName:    instanceName,
 		client:          kv,
 		auditFile:       file,
 
-		etcdState: etcdState,
-		config:    config,
-		basePath:  basePath,
+		etcdState:    etcdState,
+		config:       config,
+		basePath:     basePath,
+		milvusConfig: milvusConfig,
 	}
 
 	return state
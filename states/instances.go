@@ -0,0 +1,229 @@
+package states
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/birdwatcher/framework"
+)
+
+// instanceHandle is a named milvus instance connection.
+type instanceHandle struct {
+	name  string
+	tag   string
+	state framework.State
+}
+
+// registerInstance adds named instance handle and returns the registered name.
+// Name used by another connection is suffixed with sequence number, e.g. by-dev#2,
+// existing handles are never replaced.
+func (app *ApplicationState) registerInstance(name, tag string, state framework.State) string {
+	registered := name
+	for i := 2; ; i++ {
+		prev, ok := app.instances[registered]
+		if !ok {
+			break
+		}
+		if prev.state == state {
+			return registered
+		}
+		registered = fmt.Sprintf("%s#%d", name, i)
+	}
+	if registered != name {
+		fmt.Printf("instance name %s already in use, registered as %s\n", name, registered)
+	}
+	app.instances[registered] = &instanceHandle{name: registered, tag: tag, state: state}
+	return registered
+}
+
+// trackInstance records which named instance is current after metastore state changed.
+// unnamed milvus instance is registered with its instance name.
+func (app *ApplicationState) trackInstance(tag string, state framework.State) {
+	for name, handle := range app.instances {
+		if handle.state == state {
+			app.current = name
+			return
+		}
+	}
+	instance, ok := state.(*InstanceState)
+	if !ok {
+		app.current = ""
+		return
+	}
+	app.current = app.registerInstance(instance.instanceName, tag, state)
+}
+
+// mountInstance sets provided instance as the only metastore state.
+// returned function restores the states before mounting.
+func (app *ApplicationState) mountInstance(handle *instanceHandle) func() {
+	saved := make(map[string]framework.State)
+	for _, tag := range []string{etcdTag, tikvTag} {
+		if state, ok := app.states[tag]; ok {
+			saved[tag] = state
+			delete(app.states, tag)
+		}
+	}
+	app.states[handle.tag] = handle.state
+	app.SetupCommands()
+	return func() {
+		delete(app.states, handle.tag)
+		for tag, state := range saved {
+			app.states[tag] = state
+		}
+		app.SetupCommands()
+	}
+}
+
+func (app *ApplicationState) sortedInstances() []*instanceHandle {
+	handles := lo.Values(app.instances)
+	sort.Slice(handles, func(i, j int) bool { return handles[i].name < handles[j].name })
+	return handles
+}
+
+// processOnInstance runs command against named instance without switching current instance.
+func (app *ApplicationState) processOnInstance(name string, cmd string) error {
+	handle, ok := app.instances[name]
+	if !ok {
+		return errors.Newf("instance %s not connected", name)
+	}
+	restore := app.mountInstance(handle)
+	defer restore()
+
+	_, err := app.core.Process(cmd)
+	// state switching is not supported when targeting instance
+	handle.state.SetNext("", nil)
+	return err
+}
+
+type UseInstanceParam struct {
+	framework.ParamBase `use:"use [instance-name]" desc:"switch to named instance or use specified milvus instance in dry mode, list instances if name not provided"`
+	InstanceName        string `arg:"0" name:"instance-name"`
+	Force               bool   `name:"force" default:"false" desc:"force connect ignoring ping result"`
+	MetaPath            string `name:"metaPath" default:"meta" desc:"meta path prefix"`
+}
+
+// UseCommand implements `use` command.
+func (app *ApplicationState) UseCommand(ctx context.Context, p *UseInstanceParam) error {
+	if p.InstanceName == "" {
+		for _, handle := range app.sortedInstances() {
+			marker := " "
+			if handle.name == app.current {
+				marker = "*"
+			}
+			fmt.Printf("%s %s\t%s\n", marker, handle.name, handle.state.Label())
+		}
+		return nil
+	}
+
+	if handle, ok := app.instances[p.InstanceName]; ok {
+		for _, tag := range []string{etcdTag, tikvTag} {
+			delete(app.states, tag)
+		}
+		app.SetTagNext(handle.tag, handle.state)
+		fmt.Printf("Using instance %s\n", handle.name)
+		return nil
+	}
+
+	// use instance with root path on connected metastore
+	for _, tag := range []string{etcdTag, tikvTag} {
+		state, ok := app.states[tag].(metaStoreState)
+		if !ok {
+			continue
+		}
+		instance, err := state.useInstance(ctx, p)
+		if err != nil {
+			return err
+		}
+		app.SetTagNext(tag, instance)
+		return nil
+	}
+	return errors.Newf("instance %s not connected", p.InstanceName)
+}
+
+type ForeachInstanceParam struct {
+	framework.ParamBase `use:"foreach-instance [command]" desc:"run read-only command across all connected instances, e.g. foreach-instance show collections"`
+}
+
+// ForeachInstanceCommand is only reached when command not provided, see processForeachInstance.
+func (app *ApplicationState) ForeachInstanceCommand(ctx context.Context, p *ForeachInstanceParam) error {
+	return errors.New("command to run not provided")
+}
+
+// processForeachInstance runs command on each named instance and merges result sets.
+func (app *ApplicationState) processForeachInstance(cmd string) error {
+	if len(app.instances) == 0 {
+		return errors.New("no instance connected")
+	}
+
+	var results []*InstanceResult
+	for _, handle := range app.sortedInstances() {
+		restore := app.mountInstance(handle)
+		target, _, err := app.core.RootCmd.Find(strings.Split(cmd, " "))
+		if err != nil || !framework.IsReadOnly(target) {
+			restore()
+			return errors.Newf("command %q is not declared read-only, only read-only commands are supported", cmd)
+		}
+		err = app.core.Collect(cmd, func(rs framework.ResultSet, err error) {
+			results = append(results, &InstanceResult{Instance: handle.name, Result: rs, Err: err})
+		})
+		restore()
+		if err != nil {
+			results = append(results, &InstanceResult{Instance: handle.name, Err: err})
+		}
+	}
+	fmt.Println(framework.NewListResult[InstanceResults](results).PrintAs(framework.FormatDefault))
+	return nil
+}
+
+// InstanceResult is the command result from one named instance.
+type InstanceResult struct {
+	Instance string
+	Result   framework.ResultSet
+	Err      error
+}
+
+type InstanceResults struct {
+	framework.ListResultSet[*InstanceResult]
+}
+
+func (rs *InstanceResults) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatDefault, framework.FormatPlain:
+		sb := &strings.Builder{}
+		for _, result := range rs.Data {
+			if result.Err != nil {
+				fmt.Fprintf(sb, "%s\tError: %s\n", result.Instance, result.Err.Error())
+				continue
+			}
+			var output string
+			if preset, ok := result.Result.(*framework.PresetResultSet); ok {
+				output = preset.String()
+			} else {
+				output = result.Result.PrintAs(format)
+			}
+			for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+				fmt.Fprintf(sb, "%s\t%s\n", result.Instance, line)
+			}
+		}
+		return sb.String()
+	default:
+	}
+	return ""
+}
+
+func (rs *InstanceResults) Entities() any {
+	return lo.Map(rs.Data, func(result *InstanceResult, _ int) map[string]any {
+		entity := map[string]any{"instance": result.Instance}
+		if result.Err != nil {
+			entity["error"] = result.Err.Error()
+		} else {
+			entity["entities"] = result.Result.Entities()
+		}
+		return entity
+	})
+}
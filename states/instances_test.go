@@ -0,0 +1,58 @@
+package states
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/configs"
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+)
+
+func TestUseAndForeachInstance(t *testing.T) {
+	// instance state writes audit log into working directory
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer os.Chdir(wd)
+
+	c := fakecluster.New(t)
+	c.Put("session/id", "1")
+	c.AddSession(&models.Session{ServerID: 1, ServerName: "rootcoord", Address: "127.0.0.1:1", Exclusive: true})
+
+	app := Start(&configs.Config{}, false).(*ApplicationState)
+	ctx := context.Background()
+
+	// not connected
+	assert.Error(t, app.UseCommand(ctx, &UseInstanceParam{InstanceName: fakecluster.RootPath, MetaPath: fakecluster.MetaPath}))
+
+	// metastore connected with tikv tag in dry mode
+	app.SetTagNext(tikvTag, getKVConnectedState(app.core, c.KV(), "tikv", app.config))
+	require.NoError(t, app.UseCommand(ctx, &UseInstanceParam{InstanceName: fakecluster.RootPath, MetaPath: fakecluster.MetaPath}))
+	instance, ok := app.states[tikvTag].(*InstanceState)
+	require.True(t, ok)
+	assert.Equal(t, fakecluster.RootPath, instance.instanceName)
+	assert.Equal(t, fakecluster.RootPath, app.current)
+
+	// use again from instance state
+	require.NoError(t, app.UseCommand(ctx, &UseInstanceParam{InstanceName: fakecluster.RootPath, MetaPath: fakecluster.MetaPath}))
+	assert.Error(t, app.UseCommand(ctx, &UseInstanceParam{InstanceName: "unknown", MetaPath: fakecluster.MetaPath}))
+
+	// only commands declared read-only are allowed
+	assert.NoError(t, app.processForeachInstance("show session"))
+	assert.Error(t, app.processForeachInstance("remove session --run"))
+	assert.Error(t, app.processForeachInstance("show"))
+
+	// another cluster with same root path gets distinct name, first handle kept
+	other := getInstanceState(app.core, c.KV(), fakecluster.RootPath, fakecluster.MetaPath, nil, app.config, nil)
+	app.SetTagNext(tikvTag, other)
+	assert.Equal(t, fakecluster.RootPath+"#2", app.current)
+	assert.Same(t, instance, app.instances[fakecluster.RootPath].state)
+	assert.Same(t, other, app.instances[fakecluster.RootPath+"#2"].state)
+	require.NoError(t, app.UseCommand(ctx, &UseInstanceParam{InstanceName: fakecluster.RootPath}))
+	assert.Equal(t, fakecluster.RootPath, app.current)
+}
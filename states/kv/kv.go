@@ -14,8 +14,12 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/gosuri/uilive"
+	"github.com/samber/lo"
+	tikverr "github.com/tikv/client-go/v2/error"
 	tikv "github.com/tikv/client-go/v2/kv"
+	tilib "github.com/tikv/client-go/v2/tikv"
 	"github.com/tikv/client-go/v2/txnkv"
+	"github.com/tikv/client-go/v2/txnkv/transaction"
 	"go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"google.golang.org/protobuf/proto"
@@ -41,6 +45,19 @@ const (
 
 var EmptyValueByte = []byte(EmptyValueString)
 
+var (
+	// ErrKeyNotFound is returned when loaded key not exists.
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrKeyModified is returned when conditional write aborted due to concurrent modification.
+	ErrKeyModified = errors.New("key modified concurrently")
+)
+
+// RevisionGuard requires Key not modified since Revision returned by LoadWithRevision.
+type RevisionGuard struct {
+	Key      string
+	Revision int64
+}
+
 // MetaKV contains base operations of kv. Include save, load and remove etc.
 type MetaKV interface {
 	Load(ctx context.Context, key string, opts ...LoadOption) (string, error)
@@ -49,6 +66,15 @@ type MetaKV interface {
 	MultiSave(ctx context.Context, keys, values []string) error
 	Remove(ctx context.Context, key string) error
 	RemoveWithPrefix(ctx context.Context, key string) error
+	// LoadWithRevision returns value and revision of key, the revision could be used in conditional writes.
+	// When key not exists, error wraps ErrKeyNotFound and the revision is still valid for guards.
+	LoadWithRevision(ctx context.Context, key string) (string, int64, error)
+	// CompareAndSwap saves the new value only if current value of key equals to oldValue.
+	CompareAndSwap(ctx context.Context, key, oldValue, newValue string) error
+	// SaveIfNotModifiedSince saves the value only if key is not modified since rev loaded.
+	SaveIfNotModifiedSince(ctx context.Context, key, value string, rev int64) error
+	// MultiSaveAndRemoveIf saves & removes keys atomically only if all guarded keys are not modified.
+	MultiSaveAndRemoveIf(ctx context.Context, saves map[string]string, removals []string, guards ...RevisionGuard) error
 	removeWithPrevKV(ctx context.Context, key string) (*mvccpb.KeyValue, error)
 	removeWithPrefixAndPrevKV(ctx context.Context, prefix string) ([]*mvccpb.KeyValue, error)
 	GetAllRootPath(ctx context.Context) ([]string, error)
@@ -125,9 +151,12 @@ func (kv *etcdKV) Save(ctx context.Context, key, value string) error {
 }
 
 func (kv *etcdKV) MultiSave(ctx context.Context, keys, values []string) error {
+	if len(keys) != len(values) {
+		return errors.Newf("keys and values length not match, %d vs %d", len(keys), len(values))
+	}
 	var ops []clientv3.Op
 	for i, key := range keys {
-		ops = append(ops, clientv3.OpPut(key, values[i]))
+		ops = append(ops, clientv3.OpPut(joinPath(kv.rootPath, key), values[i]))
 	}
 
 	_, err := kv.client.Txn(ctx).If().Then(ops...).Commit()
@@ -148,6 +177,63 @@ func (kv *etcdKV) RemoveWithPrefix(ctx context.Context, prefix string) error {
 	return err
 }
 
+// LoadWithRevision returns value and mod revision of key, revision is 0 if key not exists.
+func (kv *etcdKV) LoadWithRevision(ctx context.Context, key string) (string, int64, error) {
+	resp, err := kv.client.Get(ctx, joinPath(kv.rootPath, key))
+	if err != nil {
+		return "", 0, err
+	}
+	if len(resp.Kvs) == 0 {
+		return "", 0, errors.Wrapf(ErrKeyNotFound, "key %s", key)
+	}
+	return string(resp.Kvs[0].Value), resp.Kvs[0].ModRevision, nil
+}
+
+// CompareAndSwap saves the new value only if current value of key equals to oldValue.
+func (kv *etcdKV) CompareAndSwap(ctx context.Context, key, oldValue, newValue string) error {
+	key = joinPath(kv.rootPath, key)
+	resp, err := kv.client.Txn(ctx).If(
+		clientv3.Compare(clientv3.CreateRevision(key), ">", 0),
+		clientv3.Compare(clientv3.Value(key), "=", oldValue),
+	).Then(clientv3.OpPut(key, newValue)).Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return errors.Wrapf(ErrKeyModified, "key %s", key)
+	}
+	return nil
+}
+
+// SaveIfNotModifiedSince saves the value only if mod revision of key still equals to rev.
+func (kv *etcdKV) SaveIfNotModifiedSince(ctx context.Context, key, value string, rev int64) error {
+	return kv.MultiSaveAndRemoveIf(ctx, map[string]string{key: value}, nil, RevisionGuard{Key: key, Revision: rev})
+}
+
+// MultiSaveAndRemoveIf saves & removes keys in one txn guarded by mod revision of each guarded key.
+func (kv *etcdKV) MultiSaveAndRemoveIf(ctx context.Context, saves map[string]string, removals []string, guards ...RevisionGuard) error {
+	cmps := make([]clientv3.Cmp, 0, len(guards))
+	for _, guard := range guards {
+		cmps = append(cmps, clientv3.Compare(clientv3.ModRevision(joinPath(kv.rootPath, guard.Key)), "=", guard.Revision))
+	}
+	ops := make([]clientv3.Op, 0, len(saves)+len(removals))
+	for key, value := range saves {
+		ops = append(ops, clientv3.OpPut(joinPath(kv.rootPath, key), value))
+	}
+	for _, key := range removals {
+		ops = append(ops, clientv3.OpDelete(joinPath(kv.rootPath, key)))
+	}
+
+	resp, err := kv.client.Txn(ctx).If(cmps...).Then(ops...).Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return ErrKeyModified
+	}
+	return nil
+}
+
 func (kv *etcdKV) removeWithPrevKV(ctx context.Context, key string) (*mvccpb.KeyValue, error) {
 	key = joinPath(kv.rootPath, key)
 	resp, err := kv.client.Delete(ctx, key, clientv3.WithPrevKV())
@@ -214,33 +300,10 @@ func (kv *etcdKV) BackupKV(base, prefix string, w *bufio.Writer, ignoreRevision
 		fmt.Println("WARNING!!! doing backup ignore revision! please make sure no instance of milvus is online!")
 	}
 
-	// meta stored in extra
-	meta := make(map[string]string)
-
 	cnt := resp.Count
 	rev := resp.Header.Revision
-	meta["cnt"] = fmt.Sprintf("%d", cnt)
-	meta["rev"] = fmt.Sprintf("%d", rev)
-	var instance, metaPath string
-	parts := strings.Split(base, "/")
-	if len(parts) > 1 {
-		metaPath = parts[len(parts)-1]
-		instance = joinPath(parts[:len(parts)-1]...)
-	} else {
-		instance = base
-	}
-	meta["instance"] = instance
-	meta["metaPath"] = metaPath
-
-	fmt.Println("meta path ", metaPath)
-
-	bs, _ := json.Marshal(meta)
-	ph := models.PartHeader{
-		PartType: models.PartType_EtcdBackup,
-		PartLen:  -1, // not sure for length
-		Extra:    bs,
-	}
-	bs, err = proto.Marshal(&ph)
+	ph := backupPartHeader(base, cnt, rev)
+	bs, err := proto.Marshal(ph)
 	if err != nil {
 		fmt.Println("failed to marshal part header for etcd backup", err.Error())
 		return err
@@ -457,8 +520,16 @@ func (kv *txnTiKV) Save(ctx context.Context, key, value string) error {
 	return txn.Commit(ctx)
 }
 
+// MultiSave saves the input key-value pairs in one transaction.
 func (kv *txnTiKV) MultiSave(ctx context.Context, keys, values []string) error {
-	return errors.New("not implemented")
+	if len(keys) != len(values) {
+		return errors.Newf("keys and values length not match, %d vs %d", len(keys), len(values))
+	}
+	saves := make(map[string]string, len(keys))
+	for i, key := range keys {
+		saves[key] = values[i]
+	}
+	return kv.MultiSaveAndRemoveIf(ctx, saves, nil)
 }
 
 // Remove removes the input key.
@@ -489,6 +560,117 @@ func (kv *txnTiKV) RemoveWithPrefix(ctx context.Context, prefix string) error {
 	return err
 }
 
+// LoadWithRevision returns value of key and the timestamp it was read at.
+func (kv *txnTiKV) LoadWithRevision(ctx context.Context, key string) (string, int64, error) {
+	txn, err := kv.client.Begin()
+	if err != nil {
+		return "", 0, errors.Wrap(err, "Failed to build transaction for LoadWithRevision")
+	}
+	defer txn.Rollback()
+
+	rev := int64(txn.StartTS())
+	val, err := txn.Get(ctx, []byte(joinPath(kv.rootPath, key)))
+	if tikverr.IsErrNotFound(err) {
+		return "", rev, errors.Wrapf(ErrKeyNotFound, "key %s", key)
+	}
+	if err != nil {
+		return "", 0, errors.Wrap(err, fmt.Sprintf("Failed to load value for key %s", key))
+	}
+	return convertEmptyByteToString(val), rev, nil
+}
+
+// CompareAndSwap saves the new value only if current value of key equals to oldValue.
+func (kv *txnTiKV) CompareAndSwap(ctx context.Context, key, oldValue, newValue string) error {
+	txn, err := kv.client.Begin()
+	if err != nil {
+		return errors.Wrap(err, "Failed to build transaction for CompareAndSwap")
+	}
+
+	byteKey := []byte(joinPath(kv.rootPath, key))
+	val, err := txn.Get(ctx, byteKey)
+	if err != nil || convertEmptyByteToString(val) != oldValue {
+		txn.Rollback()
+		if err != nil && !tikverr.IsErrNotFound(err) {
+			return errors.Wrap(err, fmt.Sprintf("Failed to load value for key %s", key))
+		}
+		return errors.Wrapf(ErrKeyModified, "key %s", key)
+	}
+	return kv.commit(ctx, txn, map[string]string{key: newValue}, nil)
+}
+
+// SaveIfNotModifiedSince saves the value only if key not written after rev.
+func (kv *txnTiKV) SaveIfNotModifiedSince(ctx context.Context, key, value string, rev int64) error {
+	return kv.MultiSaveAndRemoveIf(ctx, map[string]string{key: value}, nil, RevisionGuard{Key: key, Revision: rev})
+}
+
+// MultiSaveAndRemoveIf saves & removes keys in one optimistic transaction.
+// The transaction starts at the smallest guard revision and guarded keys are written as well,
+// so any write to them after that revision fails the commit with write conflict.
+func (kv *txnTiKV) MultiSaveAndRemoveIf(ctx context.Context, saves map[string]string, removals []string, guards ...RevisionGuard) error {
+	var opts []tilib.TxnOption
+	if len(guards) > 0 {
+		startTS := guards[0].Revision
+		for _, guard := range guards {
+			startTS = min(startTS, guard.Revision)
+		}
+		opts = append(opts, tilib.WithStartTS(uint64(startTS)))
+	}
+	txn, err := kv.client.Begin(opts...)
+	if err != nil {
+		return errors.Wrap(err, "Failed to build transaction for MultiSaveAndRemoveIf")
+	}
+
+	// rewrite guarded keys not changed to detect conflict
+	touched := lo.SliceToMap(removals, func(key string) (string, struct{}) { return key, struct{}{} })
+	for key := range saves {
+		touched[key] = struct{}{}
+	}
+	for _, guard := range guards {
+		if _, ok := touched[guard.Key]; ok {
+			continue
+		}
+		touched[guard.Key] = struct{}{}
+		byteKey := []byte(joinPath(kv.rootPath, guard.Key))
+		val, err := txn.Get(ctx, byteKey)
+		switch {
+		case tikverr.IsErrNotFound(err):
+			err = txn.Delete(byteKey)
+		case err == nil:
+			err = txn.Set(byteKey, val)
+		}
+		if err != nil {
+			txn.Rollback()
+			return errors.Wrap(err, fmt.Sprintf("Failed to guard key %s", guard.Key))
+		}
+	}
+	return kv.commit(ctx, txn, saves, removals)
+}
+
+// commit writes saves & removals in txn and commits it, write conflict is converted to ErrKeyModified.
+func (kv *txnTiKV) commit(ctx context.Context, txn *transaction.KVTxn, saves map[string]string, removals []string) error {
+	for key, value := range saves {
+		byteValue, err := convertEmptyStringToByte(value)
+		if err == nil {
+			err = txn.Set([]byte(joinPath(kv.rootPath, key)), byteValue)
+		}
+		if err != nil {
+			txn.Rollback()
+			return errors.Wrap(err, fmt.Sprintf("Failed to set value for key %s", key))
+		}
+	}
+	for _, key := range removals {
+		if err := txn.Delete([]byte(joinPath(kv.rootPath, key))); err != nil {
+			txn.Rollback()
+			return errors.Wrap(err, fmt.Sprintf("Failed to remove key %s", key))
+		}
+	}
+	err := txn.Commit(ctx)
+	if tikverr.IsErrWriteConflict(err) {
+		return errors.Wrap(ErrKeyModified, err.Error())
+	}
+	return err
+}
+
 func (kv *txnTiKV) removeWithPrevKV(ctx context.Context, key string) (*mvccpb.KeyValue, error) {
 	preV, err := kv.Load(ctx, key)
 	if err != nil {
@@ -7,7 +7,7 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
 	"go.etcd.io/etcd/api/v3/mvccpb"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/protoadapt"
@@ -51,7 +51,15 @@ func (c *FileAuditKV) Save(ctx context.Context, key, value string) error {
 }
 
 func (c *FileAuditKV) MultiSave(ctx context.Context, keys, values []string) error {
-	return errors.New("not implemented")
+	befores := c.loadBefores(ctx, keys)
+	err := c.cli.MultiSave(ctx, keys, values)
+	if err != nil {
+		return err
+	}
+	for i, key := range keys {
+		c.writePut(key, befores[i], values[i])
+	}
+	return nil
 }
 
 func (c *FileAuditKV) Remove(ctx context.Context, key string) error {
@@ -89,6 +97,50 @@ func (c *FileAuditKV) RemoveWithPrefix(ctx context.Context, key string) error {
 	return nil
 }
 
+func (c *FileAuditKV) LoadWithRevision(ctx context.Context, key string) (string, int64, error) {
+	return c.cli.LoadWithRevision(ctx, key)
+}
+
+func (c *FileAuditKV) CompareAndSwap(ctx context.Context, key, oldValue, newValue string) error {
+	err := c.cli.CompareAndSwap(ctx, key, oldValue, newValue)
+	if err != nil {
+		return err
+	}
+	c.writePut(key, oldValue, newValue)
+	return nil
+}
+
+func (c *FileAuditKV) SaveIfNotModifiedSince(ctx context.Context, key, value string, rev int64) error {
+	return c.MultiSaveAndRemoveIf(ctx, map[string]string{key: value}, nil, RevisionGuard{Key: key, Revision: rev})
+}
+
+func (c *FileAuditKV) MultiSaveAndRemoveIf(ctx context.Context, saves map[string]string, removals []string, guards ...RevisionGuard) error {
+	keys := lo.Keys(saves)
+	befores := c.loadBefores(ctx, append(keys, removals...))
+	err := c.cli.MultiSaveAndRemoveIf(ctx, saves, removals, guards...)
+	if err != nil {
+		return err
+	}
+	for i, key := range keys {
+		c.writePut(key, befores[i], saves[key])
+	}
+	for i, key := range removals {
+		fmt.Println("audit delete", key)
+		c.writeHeader(models.AuditOpType_OpDel, 1)
+		c.writeKeyValue(key, befores[len(keys)+i])
+	}
+	return nil
+}
+
+// loadBefores loads values of keys before written for auditing, missing key is recorded as empty.
+func (c *FileAuditKV) loadBefores(ctx context.Context, keys []string) []string {
+	befores := make([]string, len(keys))
+	for i, key := range keys {
+		befores[i], _ = c.cli.Load(ctx, key)
+	}
+	return befores
+}
+
 func (c *FileAuditKV) removeWithPrevKV(ctx context.Context, key string) (*mvccpb.KeyValue, error) {
 	return c.cli.removeWithPrevKV(ctx, key)
 }
@@ -119,6 +171,14 @@ func (c *FileAuditKV) writeHeader(op models.AuditOpType, entriesNum int32) {
 	c.writeData(bs)
 }
 
+func (c *FileAuditKV) writePut(key, before, after string) {
+	c.writeHeader(models.AuditOpType_OpPut, 2)
+	c.writeHeader(models.AuditOpType_OpPutBefore, 1)
+	c.writeKeyValue(key, before)
+	c.writeHeader(models.AuditOpType_OpPutAfter, 1)
+	c.writeKeyValue(key, after)
+}
+
 func (c *FileAuditKV) writeLogKV(kv *mvccpb.KeyValue) {
 	if kv == (*mvccpb.KeyValue)(nil) {
 		return
@@ -0,0 +1,212 @@
+package kv
+
+import (
+	"bufio"
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+)
+
+// implementation assertion
+var _ MetaKV = (*GuardedKV)(nil)
+
+// GuardedKV is a MetaKV turning writes into conditional writes.
+// Values read through it are remembered, writing a key read before succeeds only
+// if the key still has the value read, so analysis result based on stale meta
+// aborts instead of overwriting concurrent update from coordinators.
+// Keys not read before are guarded by their revision when written.
+type GuardedKV struct {
+	MetaKV
+
+	mut  sync.RWMutex
+	read map[string]string
+}
+
+// NewGuardedKV creates a guarded kv over cli.
+func NewGuardedKV(cli MetaKV) *GuardedKV {
+	return &GuardedKV{
+		MetaKV: cli,
+		read:   make(map[string]string),
+	}
+}
+
+// Load returns value of key and remembers it.
+func (kv *GuardedKV) Load(ctx context.Context, key string, opts ...LoadOption) (string, error) {
+	value, err := kv.MetaKV.Load(ctx, key, opts...)
+	if err == nil && !kv.keysOnly(opts) {
+		kv.remember(key, value)
+	}
+	return value, err
+}
+
+// LoadWithPrefix returns keys & values with prefix and remembers them.
+func (kv *GuardedKV) LoadWithPrefix(ctx context.Context, key string, opts ...LoadOption) ([]string, []string, error) {
+	keys, values, err := kv.MetaKV.LoadWithPrefix(ctx, key, opts...)
+	if err == nil && !kv.keysOnly(opts) {
+		for i, key := range keys {
+			kv.remember(key, values[i])
+		}
+	}
+	return keys, values, err
+}
+
+// WalkWithPrefix calls fn on each key-value with prefix and remembers them.
+func (kv *GuardedKV) WalkWithPrefix(ctx context.Context, prefix string, paginationSize int, fn func([]byte, []byte) error) error {
+	return kv.MetaKV.WalkWithPrefix(ctx, prefix, paginationSize, func(k, v []byte) error {
+		kv.remember(string(k), string(v))
+		return fn(k, v)
+	})
+}
+
+// Save saves value with CompareAndSwap if key read before, otherwise guarded by current revision.
+func (kv *GuardedKV) Save(ctx context.Context, key, value string) error {
+	old, ok := kv.lookup(key)
+	if ok {
+		err := kv.MetaKV.CompareAndSwap(ctx, key, old, value)
+		if err == nil {
+			kv.remember(key, value)
+		}
+		return err
+	}
+	_, rev, err := kv.MetaKV.LoadWithRevision(ctx, key)
+	if err != nil && !errors.Is(err, ErrKeyNotFound) {
+		return err
+	}
+	return kv.MetaKV.SaveIfNotModifiedSince(ctx, key, value, rev)
+}
+
+// MultiSave saves key-values in batches, each key guarded same as Save.
+func (kv *GuardedKV) MultiSave(ctx context.Context, keys, values []string) error {
+	if len(keys) != len(values) {
+		return errors.Newf("keys and values length not match, %d vs %d", len(keys), len(values))
+	}
+	saves := make(map[string]string, len(keys))
+	for i, key := range keys {
+		saves[key] = values[i]
+	}
+	return kv.guardedWrite(ctx, saves, nil)
+}
+
+// Remove removes the key if not modified since read.
+func (kv *GuardedKV) Remove(ctx context.Context, key string) error {
+	return kv.guardedWrite(ctx, nil, []string{key})
+}
+
+// RemoveWithPrefix removes all keys with prefix in batches, each key guarded same as Remove.
+func (kv *GuardedKV) RemoveWithPrefix(ctx context.Context, prefix string) error {
+	keys, _, err := kv.MetaKV.LoadWithPrefix(ctx, prefix, WithKeysOnly())
+	if err != nil {
+		return err
+	}
+	return kv.guardedWrite(ctx, nil, keys)
+}
+
+func (kv *GuardedKV) removeWithPrevKV(ctx context.Context, key string) (*mvccpb.KeyValue, error) {
+	value, err := kv.MetaKV.Load(ctx, key)
+	if err != nil {
+		// allow key not exist, same as etcd
+		return nil, nil
+	}
+	if err := kv.Remove(ctx, key); err != nil {
+		return nil, err
+	}
+	return &mvccpb.KeyValue{Key: []byte(key), Value: []byte(value)}, nil
+}
+
+func (kv *GuardedKV) removeWithPrefixAndPrevKV(ctx context.Context, prefix string) ([]*mvccpb.KeyValue, error) {
+	keys, values, err := kv.MetaKV.LoadWithPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if err := kv.guardedWrite(ctx, nil, keys); err != nil {
+		return nil, err
+	}
+	result := make([]*mvccpb.KeyValue, 0, len(keys))
+	for i, key := range keys {
+		result = append(result, &mvccpb.KeyValue{Key: []byte(key), Value: []byte(values[i])})
+	}
+	return result, nil
+}
+
+// BackupKV delegates to the underlying kv.
+func (kv *GuardedKV) BackupKV(base, prefix string, w *bufio.Writer, ignoreRevision bool, batchSize int64) error {
+	return kv.MetaKV.BackupKV(base, prefix, w, ignoreRevision, batchSize)
+}
+
+// guardedWrite loads revisions of all written keys, verifies keys read before still have the value read,
+// then writes in batches of MaxTxnOps keys, each batch guarded by the revisions of its keys.
+func (kv *GuardedKV) guardedWrite(ctx context.Context, saves map[string]string, removals []string) error {
+	ops := make([]guardedOp, 0, len(saves)+len(removals))
+	var existing int
+	guard := func(op guardedOp) error {
+		value, rev, err := kv.MetaKV.LoadWithRevision(ctx, op.key)
+		exists := err == nil
+		if err != nil && !errors.Is(err, ErrKeyNotFound) {
+			return err
+		}
+		if old, ok := kv.lookup(op.key); ok && (!exists || old != value) {
+			return errors.Wrapf(ErrKeyModified, "key %s", op.key)
+		}
+		if exists {
+			existing++
+		}
+		op.rev = rev
+		ops = append(ops, op)
+		return nil
+	}
+	keys := lo.Keys(saves)
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := guard(guardedOp{key: key, value: saves[key]}); err != nil {
+			return err
+		}
+	}
+	for _, key := range removals {
+		if err := guard(guardedOp{key: key, remove: true}); err != nil {
+			return err
+		}
+	}
+
+	// removing missing key is a no-op, same as unconditional remove
+	if len(saves) == 0 && existing == 0 {
+		return nil
+	}
+	if err := writeInBatches(ctx, kv.MetaKV, ops); err != nil {
+		return err
+	}
+
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	for key, value := range saves {
+		kv.read[key] = value
+	}
+	for _, key := range removals {
+		delete(kv.read, key)
+	}
+	return nil
+}
+
+func (kv *GuardedKV) remember(key, value string) {
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	kv.read[key] = value
+}
+
+func (kv *GuardedKV) lookup(key string) (string, bool) {
+	kv.mut.RLock()
+	defer kv.mut.RUnlock()
+	value, ok := kv.read[key]
+	return value, ok
+}
+
+func (kv *GuardedKV) keysOnly(opts []LoadOption) bool {
+	opt := defaultLoadOption()
+	for _, f := range opts {
+		f(opt)
+	}
+	return opt.withKeysOnly
+}
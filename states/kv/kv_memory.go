@@ -0,0 +1,297 @@
+package kv
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+)
+
+// implementation assertion
+var _ MetaKV = (*MemoryKV)(nil)
+
+// MemoryKV is a MetaKV storing all key-values in memory.
+// It could be used for unit tests or loading backup files without embed etcd.
+type MemoryKV struct {
+	mut  sync.RWMutex
+	data map[string]string
+	// rev is increased on each write, modRevs records the revision each key last written at.
+	rev     int64
+	modRevs map[string]int64
+}
+
+// NewMemoryKV creates an empty in-memory kv.
+func NewMemoryKV() *MemoryKV {
+	return &MemoryKV{
+		data:    make(map[string]string),
+		modRevs: make(map[string]int64),
+	}
+}
+
+// Load returns value of the key.
+func (kv *MemoryKV) Load(ctx context.Context, key string, opts ...LoadOption) (string, error) {
+	kv.mut.RLock()
+	defer kv.mut.RUnlock()
+	value, ok := kv.data[key]
+	if !ok {
+		return "", fmt.Errorf("key not found: %s", key)
+	}
+	return value, nil
+}
+
+// LoadWithPrefix returns all the keys and values with the given key prefix, sorted by key.
+func (kv *MemoryKV) LoadWithPrefix(ctx context.Context, key string, opts ...LoadOption) ([]string, []string, error) {
+	opt := defaultLoadOption()
+	for _, f := range opts {
+		f(opt)
+	}
+	kv.mut.RLock()
+	defer kv.mut.RUnlock()
+	keys := kv.keysWithPrefix(key)
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if opt.withKeysOnly {
+			values = append(values, "")
+			continue
+		}
+		values = append(values, kv.data[key])
+	}
+	return keys, values, nil
+}
+
+// Save saves the key-value pair.
+func (kv *MemoryKV) Save(ctx context.Context, key, value string) error {
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	kv.rev++
+	kv.put(key, value)
+	return nil
+}
+
+// MultiSave saves the key-value pairs atomically.
+func (kv *MemoryKV) MultiSave(ctx context.Context, keys, values []string) error {
+	if len(keys) != len(values) {
+		return errors.Newf("keys and values length not match, %d vs %d", len(keys), len(values))
+	}
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	kv.rev++
+	for i, key := range keys {
+		kv.put(key, values[i])
+	}
+	return nil
+}
+
+// Remove removes the key.
+func (kv *MemoryKV) Remove(ctx context.Context, key string) error {
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	kv.rev++
+	kv.del(key)
+	return nil
+}
+
+// RemoveWithPrefix removes the keys with given prefix.
+func (kv *MemoryKV) RemoveWithPrefix(ctx context.Context, prefix string) error {
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	kv.rev++
+	for _, key := range kv.keysWithPrefix(prefix) {
+		kv.del(key)
+	}
+	return nil
+}
+
+// LoadWithRevision returns value and mod revision of key, revision is 0 if key not exists.
+func (kv *MemoryKV) LoadWithRevision(ctx context.Context, key string) (string, int64, error) {
+	kv.mut.RLock()
+	defer kv.mut.RUnlock()
+	value, ok := kv.data[key]
+	if !ok {
+		return "", 0, errors.Wrapf(ErrKeyNotFound, "key %s", key)
+	}
+	return value, kv.modRevs[key], nil
+}
+
+// CompareAndSwap saves the new value only if current value of key equals to oldValue.
+func (kv *MemoryKV) CompareAndSwap(ctx context.Context, key, oldValue, newValue string) error {
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	value, ok := kv.data[key]
+	if !ok || value != oldValue {
+		return errors.Wrapf(ErrKeyModified, "key %s", key)
+	}
+	kv.rev++
+	kv.put(key, newValue)
+	return nil
+}
+
+// SaveIfNotModifiedSince saves the value only if mod revision of key still equals to rev.
+func (kv *MemoryKV) SaveIfNotModifiedSince(ctx context.Context, key, value string, rev int64) error {
+	return kv.MultiSaveAndRemoveIf(ctx, map[string]string{key: value}, nil, RevisionGuard{Key: key, Revision: rev})
+}
+
+// MultiSaveAndRemoveIf saves & removes keys atomically only if all guarded keys are not modified.
+func (kv *MemoryKV) MultiSaveAndRemoveIf(ctx context.Context, saves map[string]string, removals []string, guards ...RevisionGuard) error {
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	for _, guard := range guards {
+		if kv.modRevs[guard.Key] != guard.Revision {
+			return errors.Wrapf(ErrKeyModified, "key %s", guard.Key)
+		}
+	}
+	kv.rev++
+	for key, value := range saves {
+		kv.put(key, value)
+	}
+	for _, key := range removals {
+		kv.del(key)
+	}
+	return nil
+}
+
+func (kv *MemoryKV) removeWithPrevKV(ctx context.Context, key string) (*mvccpb.KeyValue, error) {
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	value, ok := kv.data[key]
+	if !ok {
+		return nil, nil
+	}
+	kv.rev++
+	kv.del(key)
+	return &mvccpb.KeyValue{Key: []byte(key), Value: []byte(value)}, nil
+}
+
+func (kv *MemoryKV) removeWithPrefixAndPrevKV(ctx context.Context, prefix string) ([]*mvccpb.KeyValue, error) {
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	var result []*mvccpb.KeyValue
+	kv.rev++
+	for _, key := range kv.keysWithPrefix(prefix) {
+		result = append(result, &mvccpb.KeyValue{Key: []byte(key), Value: []byte(kv.data[key])})
+		kv.del(key)
+	}
+	return result, nil
+}
+
+// GetAllRootPath returns the first level path of all keys.
+func (kv *MemoryKV) GetAllRootPath(ctx context.Context) ([]string, error) {
+	kv.mut.RLock()
+	defer kv.mut.RUnlock()
+	return rootPaths(kv.keysWithPrefix("")), nil
+}
+
+// BackupKV writes key-values with prefix in the same format as etcd backup.
+func (kv *MemoryKV) BackupKV(base, prefix string, w *bufio.Writer, ignoreRevision bool, batchSize int64) error {
+	kv.mut.RLock()
+	defer kv.mut.RUnlock()
+
+	keys := kv.keysWithPrefix(joinPath(base, prefix))
+	// in-memory kv has no revision
+	bs, err := proto.Marshal(backupPartHeader(base, int64(len(keys)), 0))
+	if err != nil {
+		fmt.Println("failed to marshal part header for etcd backup", err.Error())
+		return err
+	}
+	writeBackupBytes(w, bs)
+
+	for _, key := range keys {
+		entry := &commonpb.KeyDataPair{Key: key, Data: []byte(kv.data[key])}
+		bs, err = proto.Marshal(entry)
+		if err != nil {
+			fmt.Println("failed to marshal kv pair", err.Error())
+			return err
+		}
+		writeBackupBytes(w, bs)
+	}
+
+	// write stopper
+	writeBackupBytes(w, nil)
+	return w.Flush()
+}
+
+// WalkWithPrefix calls fn on each key-value with prefix in key order.
+func (kv *MemoryKV) WalkWithPrefix(ctx context.Context, prefix string, paginationSize int, fn func([]byte, []byte) error) error {
+	keys, values, _ := kv.LoadWithPrefix(ctx, prefix)
+	for i, key := range keys {
+		if err := fn([]byte(key), []byte(values[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements MetaKV, data is kept after closed.
+func (kv *MemoryKV) Close() {}
+
+// put & del write key at current revision, caller shall hold the lock.
+func (kv *MemoryKV) put(key, value string) {
+	kv.data[key] = value
+	kv.modRevs[key] = kv.rev
+}
+
+func (kv *MemoryKV) del(key string) {
+	delete(kv.data, key)
+	delete(kv.modRevs, key)
+}
+
+// keysWithPrefix returns sorted keys with prefix, caller shall hold the lock.
+func (kv *MemoryKV) keysWithPrefix(prefix string) []string {
+	keys := make([]string, 0)
+	for key := range kv.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// rootPaths returns distinct first level path of keys, keeping the order of first appearance.
+func rootPaths(keys []string) []string {
+	var roots []string
+	seen := make(map[string]struct{})
+	for _, key := range keys {
+		root, _, _ := strings.Cut(key, "/")
+		if _, ok := seen[root]; ok || root == "" {
+			continue
+		}
+		seen[root] = struct{}{}
+		roots = append(roots, root)
+	}
+	return roots
+}
+
+// backupPartHeader returns etcd backup part header, meta stored in extra.
+func backupPartHeader(base string, cnt, rev int64) *models.PartHeader {
+	meta := make(map[string]string)
+	meta["cnt"] = fmt.Sprintf("%d", cnt)
+	meta["rev"] = fmt.Sprintf("%d", rev)
+	var instance, metaPath string
+	parts := strings.Split(base, "/")
+	if len(parts) > 1 {
+		metaPath = parts[len(parts)-1]
+		instance = joinPath(parts[:len(parts)-1]...)
+	} else {
+		instance = base
+	}
+	meta["instance"] = instance
+	meta["metaPath"] = metaPath
+
+	bs, _ := json.Marshal(meta)
+	return &models.PartHeader{
+		PartType: models.PartType_EtcdBackup,
+		PartLen:  -1, // not sure for length
+		Extra:    bs,
+	}
+}
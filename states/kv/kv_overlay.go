@@ -0,0 +1,357 @@
+package kv
+
+import (
+	"bufio"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+)
+
+// implementation assertion
+var _ MetaKV = (*OverlayKV)(nil)
+
+// KVChangeType is the type of pending change in OverlayKV.
+type KVChangeType string
+
+const (
+	KVChangePut    KVChangeType = "PUT"
+	KVChangeDelete KVChangeType = "DELETE"
+)
+
+// KVChange is a pending change of one key in OverlayKV.
+type KVChange struct {
+	Key    string
+	Type   KVChangeType
+	Before string
+	// Existed is false when key not exists in base store.
+	Existed bool
+	After   string
+	// Reason is provided by WithChangeReason when the change is written.
+	Reason string
+}
+
+type overlayEntry struct {
+	value   string
+	deleted bool
+	reason  string
+}
+
+// OverlayKV is a copy-on-write MetaKV layering uncommitted writes over base store.
+// Reads see the pending writes, while base store is untouched until Commit.
+type OverlayKV struct {
+	base MetaKV
+
+	mut     sync.RWMutex
+	entries map[string]overlayEntry
+	// read records base values first read through overlay,
+	// Commit aborts if they are modified since read.
+	read map[string]string
+}
+
+// NewOverlayKV creates an overlay kv over the base store.
+func NewOverlayKV(base MetaKV) *OverlayKV {
+	return &OverlayKV{
+		base:    base,
+		entries: make(map[string]overlayEntry),
+		read:    make(map[string]string),
+	}
+}
+
+// Load returns value of the key.
+func (kv *OverlayKV) Load(ctx context.Context, key string, opts ...LoadOption) (string, error) {
+	kv.mut.RLock()
+	entry, ok := kv.entries[key]
+	kv.mut.RUnlock()
+	if !ok {
+		value, err := kv.base.Load(ctx, key, opts...)
+		if err == nil && !kv.keysOnly(opts) {
+			kv.remember(key, value)
+		}
+		return value, err
+	}
+	if entry.deleted {
+		return "", errors.Newf("key not found: %s", key)
+	}
+	return entry.value, nil
+}
+
+// LoadWithPrefix returns all the keys and values with the given key prefix, sorted by key.
+func (kv *OverlayKV) LoadWithPrefix(ctx context.Context, key string, opts ...LoadOption) ([]string, []string, error) {
+	keys, values, err := kv.base.LoadWithPrefix(ctx, key, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	opt := defaultLoadOption()
+	for _, f := range opts {
+		f(opt)
+	}
+
+	if !opt.withKeysOnly {
+		for i, k := range keys {
+			kv.remember(k, values[i])
+		}
+	}
+
+	kv.mut.RLock()
+	defer kv.mut.RUnlock()
+	merged := make(map[string]string, len(keys))
+	for i, k := range keys {
+		merged[k] = values[i]
+	}
+	for k, entry := range kv.entries {
+		if !strings.HasPrefix(k, key) {
+			continue
+		}
+		if entry.deleted {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = entry.value
+		if opt.withKeysOnly {
+			merged[k] = ""
+		}
+	}
+
+	resultKeys := make([]string, 0, len(merged))
+	for k := range merged {
+		resultKeys = append(resultKeys, k)
+	}
+	sort.Strings(resultKeys)
+	resultValues := make([]string, 0, len(resultKeys))
+	for _, k := range resultKeys {
+		resultValues = append(resultValues, merged[k])
+	}
+	return resultKeys, resultValues, nil
+}
+
+// Save saves the key-value pair in overlay.
+func (kv *OverlayKV) Save(ctx context.Context, key, value string) error {
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	kv.entries[key] = overlayEntry{value: value, reason: changeReason(ctx)}
+	return nil
+}
+
+// MultiSave saves the key-value pairs in overlay.
+func (kv *OverlayKV) MultiSave(ctx context.Context, keys, values []string) error {
+	if len(keys) != len(values) {
+		return errors.Newf("keys and values length not match, %d vs %d", len(keys), len(values))
+	}
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	for i, key := range keys {
+		kv.entries[key] = overlayEntry{value: values[i], reason: changeReason(ctx)}
+	}
+	return nil
+}
+
+// Remove marks the key deleted in overlay.
+func (kv *OverlayKV) Remove(ctx context.Context, key string) error {
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	kv.entries[key] = overlayEntry{deleted: true, reason: changeReason(ctx)}
+	return nil
+}
+
+// RemoveWithPrefix marks all keys with prefix deleted in overlay.
+func (kv *OverlayKV) RemoveWithPrefix(ctx context.Context, prefix string) error {
+	_, err := kv.removeWithPrefixAndPrevKV(ctx, prefix)
+	return err
+}
+
+// LoadWithRevision returns value in merged view, revision is always from base store.
+func (kv *OverlayKV) LoadWithRevision(ctx context.Context, key string) (string, int64, error) {
+	value, rev, err := kv.base.LoadWithRevision(ctx, key)
+	kv.mut.RLock()
+	entry, ok := kv.entries[key]
+	kv.mut.RUnlock()
+	switch {
+	case !ok:
+		return value, rev, err
+	case entry.deleted:
+		return "", rev, errors.Wrapf(ErrKeyNotFound, "key %s", key)
+	default:
+		return entry.value, rev, nil
+	}
+}
+
+// CompareAndSwap saves the new value in overlay only if value in merged view equals to oldValue.
+func (kv *OverlayKV) CompareAndSwap(ctx context.Context, key, oldValue, newValue string) error {
+	value, err := kv.Load(ctx, key)
+	if err != nil || value != oldValue {
+		return errors.Wrapf(ErrKeyModified, "key %s", key)
+	}
+	return kv.Save(ctx, key, newValue)
+}
+
+// SaveIfNotModifiedSince saves the value in overlay, see MultiSaveAndRemoveIf.
+func (kv *OverlayKV) SaveIfNotModifiedSince(ctx context.Context, key, value string, rev int64) error {
+	return kv.MultiSaveAndRemoveIf(ctx, map[string]string{key: value}, nil, RevisionGuard{Key: key, Revision: rev})
+}
+
+// MultiSaveAndRemoveIf saves & removes keys in overlay.
+// Guards are not checked since base store is not written, changes shall be validated again when applied.
+func (kv *OverlayKV) MultiSaveAndRemoveIf(ctx context.Context, saves map[string]string, removals []string, guards ...RevisionGuard) error {
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	for key, value := range saves {
+		kv.entries[key] = overlayEntry{value: value, reason: changeReason(ctx)}
+	}
+	for _, key := range removals {
+		kv.entries[key] = overlayEntry{deleted: true, reason: changeReason(ctx)}
+	}
+	return nil
+}
+
+func (kv *OverlayKV) removeWithPrevKV(ctx context.Context, key string) (*mvccpb.KeyValue, error) {
+	value, err := kv.Load(ctx, key)
+	if err != nil {
+		// allow key not exist, same as etcd
+		return nil, nil
+	}
+	kv.Remove(ctx, key)
+	return &mvccpb.KeyValue{Key: []byte(key), Value: []byte(value)}, nil
+}
+
+func (kv *OverlayKV) removeWithPrefixAndPrevKV(ctx context.Context, prefix string) ([]*mvccpb.KeyValue, error) {
+	keys, values, err := kv.LoadWithPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	result := make([]*mvccpb.KeyValue, 0, len(keys))
+	for i, key := range keys {
+		kv.entries[key] = overlayEntry{deleted: true, reason: changeReason(ctx)}
+		result = append(result, &mvccpb.KeyValue{Key: []byte(key), Value: []byte(values[i])})
+	}
+	return result, nil
+}
+
+// GetAllRootPath returns the first level path of all keys in merged view.
+func (kv *OverlayKV) GetAllRootPath(ctx context.Context) ([]string, error) {
+	keys, _, err := kv.LoadWithPrefix(ctx, "", WithKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+	return rootPaths(keys), nil
+}
+
+// BackupKV backups merged view of key-values with prefix.
+func (kv *OverlayKV) BackupKV(base, prefix string, w *bufio.Writer, ignoreRevision bool, batchSize int64) error {
+	keys, values, err := kv.LoadWithPrefix(context.Background(), joinPath(base, prefix))
+	if err != nil {
+		return err
+	}
+	snapshot := NewMemoryKV()
+	if err := snapshot.MultiSave(context.Background(), keys, values); err != nil {
+		return err
+	}
+	return snapshot.BackupKV(base, prefix, w, ignoreRevision, batchSize)
+}
+
+// WalkWithPrefix calls fn on each key-value with prefix in merged view.
+func (kv *OverlayKV) WalkWithPrefix(ctx context.Context, prefix string, paginationSize int, fn func([]byte, []byte) error) error {
+	keys, values, err := kv.LoadWithPrefix(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	for i, key := range keys {
+		if err := fn([]byte(key), []byte(values[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close discards pending changes, base store is owned by caller and not closed.
+func (kv *OverlayKV) Close() {
+	kv.Discard()
+}
+
+// Changes returns pending changes sorted by key, no-op writes are skipped.
+func (kv *OverlayKV) Changes(ctx context.Context) ([]*KVChange, error) {
+	kv.mut.RLock()
+	keys := make([]string, 0, len(kv.entries))
+	for key := range kv.entries {
+		keys = append(keys, key)
+	}
+	kv.mut.RUnlock()
+	sort.Strings(keys)
+
+	var changes []*KVChange
+	for _, key := range keys {
+		kv.mut.RLock()
+		entry := kv.entries[key]
+		kv.mut.RUnlock()
+
+		before, err := kv.base.Load(ctx, key)
+		existed := err == nil
+		switch {
+		case entry.deleted && !existed:
+			continue
+		case entry.deleted:
+			changes = append(changes, &KVChange{Key: key, Type: KVChangeDelete, Before: before, Existed: true, Reason: entry.reason})
+		case existed && before == entry.value:
+			continue
+		default:
+			changes = append(changes, &KVChange{Key: key, Type: KVChangePut, Before: before, Existed: existed, After: entry.value, Reason: entry.reason})
+		}
+	}
+	return changes, nil
+}
+
+// Commit writes pending changes into base store with conditional writes in batches and clears overlay.
+// Each changed key is guarded by its revision, returns ErrKeyModified if any key changed
+// since read through overlay, or since changes collected if not read before.
+func (kv *OverlayKV) Commit(ctx context.Context) error {
+	changes, err := kv.Changes(ctx)
+	if err != nil {
+		return err
+	}
+	planChanges := make([]*PlanChange, 0, len(changes))
+	for _, change := range changes {
+		pc := change.planChange()
+		kv.mut.RLock()
+		value, ok := kv.read[change.Key]
+		kv.mut.RUnlock()
+		if ok {
+			pc.OldDigest = ValueDigest(value)
+		}
+		planChanges = append(planChanges, pc)
+	}
+	if err := applyChanges(ctx, kv.base, planChanges); err != nil {
+		return errors.Wrap(err, "failed to commit changes")
+	}
+	kv.Discard()
+	return nil
+}
+
+// Discard drops all pending changes.
+func (kv *OverlayKV) Discard() {
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	kv.entries = make(map[string]overlayEntry)
+	kv.read = make(map[string]string)
+}
+
+// remember records the base value of key if not read before.
+func (kv *OverlayKV) remember(key, value string) {
+	kv.mut.Lock()
+	defer kv.mut.Unlock()
+	if _, ok := kv.read[key]; !ok {
+		kv.read[key] = value
+	}
+}
+
+func (kv *OverlayKV) keysOnly(opts []LoadOption) bool {
+	opt := defaultLoadOption()
+	for _, f := range opts {
+		f(opt)
+	}
+	return opt.withKeysOnly
+}
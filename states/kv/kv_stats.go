@@ -0,0 +1,151 @@
+package kv
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// KeyStat is the size & revision statistics of one key.
+type KeyStat struct {
+	Key string
+	// ValueSize is 0 when scanned with keys only.
+	ValueSize      int64
+	Version        int64
+	CreateRevision int64
+	ModRevision    int64
+	// Value is nil when scanned with keys only.
+	Value []byte
+}
+
+// StoreStatus is the storage status of kv store.
+type StoreStatus struct {
+	Endpoint    string
+	Version     string
+	DBSize      int64
+	DBSizeInUse int64
+}
+
+// StoreStats is the revision & storage statistics of kv store.
+type StoreStats struct {
+	Revision int64
+	// CompactRevision is 0 if store never compacted.
+	CompactRevision int64
+	Endpoints       []*StoreStatus
+}
+
+// StatsKV is implemented by MetaKV providing key revision & storage statistics, which is etcd only for now.
+type StatsKV interface {
+	WalkStatsWithPrefix(ctx context.Context, prefix string, keysOnly bool, paginationSize int, fn func(*KeyStat) error) error
+	StoreStats(ctx context.Context) (*StoreStats, error)
+}
+
+// implementation assertion
+var _ StatsKV = (*etcdKV)(nil)
+
+// WalkStatsWithPrefix calls fn on statistics of each key with prefix.
+func (kv *etcdKV) WalkStatsWithPrefix(ctx context.Context, prefix string, keysOnly bool, paginationSize int, fn func(*KeyStat) error) error {
+	prefix = joinPath(kv.rootPath, prefix)
+
+	opts := []clientv3.OpOption{
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend),
+		clientv3.WithLimit(int64(paginationSize)),
+		clientv3.WithRange(clientv3.GetPrefixRangeEnd(prefix)),
+	}
+	if keysOnly {
+		opts = append(opts, clientv3.WithKeysOnly())
+	}
+
+	key := prefix
+	for {
+		resp, err := kv.client.Get(ctx, key, opts...)
+		if err != nil {
+			return err
+		}
+
+		for _, kv := range resp.Kvs {
+			stat := &KeyStat{
+				Key:            string(kv.Key),
+				Version:        kv.Version,
+				CreateRevision: kv.CreateRevision,
+				ModRevision:    kv.ModRevision,
+			}
+			if !keysOnly {
+				stat.ValueSize = int64(len(kv.Value))
+				stat.Value = kv.Value
+			}
+			if err := fn(stat); err != nil {
+				return err
+			}
+		}
+
+		if !resp.More {
+			break
+		}
+		// move to next key
+		key = string(append(resp.Kvs[len(resp.Kvs)-1].Key, 0))
+	}
+	return nil
+}
+
+// StoreStats returns current & compacted revision and maintenance status of each endpoint.
+func (kv *etcdKV) StoreStats(ctx context.Context) (*StoreStats, error) {
+	resp, err := kv.client.Get(ctx, "/", clientv3.WithCountOnly())
+	if err != nil {
+		return nil, err
+	}
+	stats := &StoreStats{Revision: resp.Header.GetRevision()}
+
+	stats.CompactRevision, err = kv.compactRevision(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []string
+	if kv.client.ActiveConnection() == nil {
+		// embed etcd client has no connection, maintenance api is served in process regardless of endpoint
+		endpoints = []string{"embed"}
+	} else {
+		endpoints = kv.client.Endpoints()
+	}
+	for _, ep := range endpoints {
+		status, err := kv.client.Status(ctx, ep)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get status of endpoint %s", ep)
+		}
+		stats.Endpoints = append(stats.Endpoints, &StoreStatus{
+			Endpoint:    ep,
+			Version:     status.Version,
+			DBSize:      status.DbSize,
+			DBSizeInUse: status.DbSizeInUse,
+		})
+	}
+	return stats, nil
+}
+
+// compactRevision finds compacted revision with watching from revision 1,
+// which is responded with the compact revision if compacted.
+func (kv *etcdKV) compactRevision(ctx context.Context) (int64, error) {
+	_, err := kv.client.Get(ctx, "/", clientv3.WithRev(1), clientv3.WithCountOnly())
+	if err == nil {
+		return 0, nil
+	}
+	if !errors.Is(err, rpctypes.ErrCompacted) {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	for resp := range kv.client.Watch(ctx, "/", clientv3.WithRev(1)) {
+		if resp.CompactRevision > 0 {
+			return resp.CompactRevision, nil
+		}
+		if err := resp.Err(); err != nil {
+			return 0, err
+		}
+	}
+	return 0, errors.New("failed to get compact revision")
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.etcd.io/etcd/api/v3/mvccpb"
@@ -332,3 +333,206 @@ func TestBackupKV(t *testing.T) {
 		assert.NoError(t, err)
 	}
 }
+
+func TestOverlayKV(t *testing.T) {
+	ctx := context.TODO()
+	base := NewMemoryKV()
+	require.NoError(t, base.MultiSave(ctx, []string{"r/a", "r/b", "r/c"}, []string{"1", "2", "3"}))
+
+	overlay := NewOverlayKV(base)
+	require.NoError(t, overlay.Save(ctx, "r/a", "10"))
+	require.NoError(t, overlay.Save(ctx, "r/b", "2"))
+	require.NoError(t, overlay.Remove(ctx, "r/c"))
+	require.NoError(t, overlay.Save(ctx, "r/d", "4"))
+	require.NoError(t, overlay.Remove(ctx, "r/e"))
+
+	keys, vals, err := overlay.LoadWithPrefix(ctx, "r/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"r/a", "r/b", "r/d"}, keys)
+	assert.Equal(t, []string{"10", "2", "4"}, vals)
+
+	// base untouched before commit
+	keys, _, err = base.LoadWithPrefix(ctx, "r/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"r/a", "r/b", "r/c"}, keys)
+
+	changes, err := overlay.Changes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*KVChange{
+		{Key: "r/a", Type: KVChangePut, Before: "1", Existed: true, After: "10"},
+		{Key: "r/c", Type: KVChangeDelete, Before: "3", Existed: true},
+		{Key: "r/d", Type: KVChangePut, After: "4"},
+	}, changes)
+
+	require.NoError(t, overlay.Commit(ctx))
+	keys, vals, err = base.LoadWithPrefix(ctx, "r/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"r/a", "r/b", "r/d"}, keys)
+	assert.Equal(t, []string{"10", "2", "4"}, vals)
+
+	changes, err = overlay.Changes(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// base modified after read through overlay
+	_, err = overlay.Load(ctx, "r/a")
+	require.NoError(t, err)
+	require.NoError(t, base.Save(ctx, "r/a", "11"))
+	require.NoError(t, overlay.Save(ctx, "r/a", "12"))
+	require.NoError(t, overlay.Save(ctx, "r/f", "6"))
+	assert.ErrorIs(t, overlay.Commit(ctx), ErrKeyModified)
+	keys, vals, err = base.LoadWithPrefix(ctx, "r/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"r/a", "r/b", "r/d"}, keys)
+	assert.Equal(t, []string{"11", "2", "4"}, vals)
+}
+
+func TestApplyPlan(t *testing.T) {
+	ctx := context.TODO()
+	for _, kv := range kvClients {
+		require.NoError(t, kv.RemoveWithPrefix(ctx, ""))
+		require.NoError(t, kv.Save(ctx, "plan/a", "1"))
+		require.NoError(t, kv.Save(ctx, "plan/b", "2"))
+
+		genPlan := func() *Plan {
+			overlay := NewOverlayKV(kv)
+			require.NoError(t, overlay.Save(WithChangeReason(ctx, "update a"), "plan/a", "10"))
+			require.NoError(t, overlay.Remove(WithChangeReason(ctx, "remove b"), "plan/b"))
+			require.NoError(t, overlay.Save(ctx, "plan/c", "3"))
+			plan, err := NewPlan(ctx, overlay, "plan", "test")
+			require.NoError(t, err)
+			require.Len(t, plan.Changes, 3)
+			assert.Equal(t, []string{"update a", "remove b", ""}, lo.Map(plan.Changes, func(c *PlanChange, _ int) string { return c.Reason }))
+			return plan
+		}
+
+		// meta changed after plan generated
+		plan := genPlan()
+		require.NoError(t, kv.Save(ctx, "plan/b", "20"))
+		err := ApplyPlan(ctx, kv, plan)
+		assert.ErrorIs(t, err, ErrPlanConflict)
+		val, err := kv.Load(ctx, "plan/a")
+		require.NoError(t, err)
+		assert.Equal(t, "1", val)
+
+		plan = genPlan()
+		require.NoError(t, ApplyPlan(ctx, kv, plan))
+		keys, vals, err := kv.LoadWithPrefix(ctx, "plan/")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"plan/a", "plan/c"}, keys)
+		assert.Equal(t, []string{"10", "3"}, vals)
+
+		// applied plan cannot be applied again
+		assert.ErrorIs(t, ApplyPlan(ctx, kv, plan), ErrPlanConflict)
+
+		// plan exceeding ops limit of single txn
+		overlay := NewOverlayKV(kv)
+		for i := 0; i < MaxTxnOps*3; i++ {
+			require.NoError(t, overlay.Save(ctx, fmt.Sprintf("plan/batch/%03d", i), "v"))
+		}
+		plan, err = NewPlan(ctx, overlay, "plan", "test")
+		require.NoError(t, err)
+		require.NoError(t, ApplyPlan(ctx, kv, plan))
+		keys, _, err = kv.LoadWithPrefix(ctx, "plan/batch/", WithKeysOnly())
+		require.NoError(t, err)
+		assert.Len(t, keys, MaxTxnOps*3)
+
+		require.NoError(t, kv.RemoveWithPrefix(ctx, ""))
+	}
+}
+
+func TestConditionalWrite(t *testing.T) {
+	ctx := context.TODO()
+	for _, kv := range kvClients {
+		if _, ok := kv.(*OverlayKV); ok {
+			// overlay does not check revision guards
+			continue
+		}
+		require.NoError(t, kv.RemoveWithPrefix(ctx, ""))
+		require.NoError(t, kv.Save(ctx, "cas/a", "1"))
+
+		assert.ErrorIs(t, kv.CompareAndSwap(ctx, "cas/a", "0", "2"), ErrKeyModified)
+		assert.ErrorIs(t, kv.CompareAndSwap(ctx, "cas/missing", "", "2"), ErrKeyModified)
+		require.NoError(t, kv.CompareAndSwap(ctx, "cas/a", "1", "2"))
+
+		val, rev, err := kv.LoadWithRevision(ctx, "cas/a")
+		require.NoError(t, err)
+		assert.Equal(t, "2", val)
+		require.NoError(t, kv.Save(ctx, "cas/a", "3"))
+		assert.ErrorIs(t, kv.SaveIfNotModifiedSince(ctx, "cas/a", "4", rev), ErrKeyModified)
+		_, rev, err = kv.LoadWithRevision(ctx, "cas/a")
+		require.NoError(t, err)
+		require.NoError(t, kv.SaveIfNotModifiedSince(ctx, "cas/a", "4", rev))
+
+		_, missingRev, err := kv.LoadWithRevision(ctx, "cas/b")
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+		_, rev, err = kv.LoadWithRevision(ctx, "cas/a")
+		require.NoError(t, err)
+		guards := []RevisionGuard{{Key: "cas/a", Revision: rev}, {Key: "cas/b", Revision: missingRev}}
+		require.NoError(t, kv.MultiSaveAndRemoveIf(ctx, map[string]string{"cas/b": "1"}, []string{"cas/a"}, guards...))
+		keys, vals, err := kv.LoadWithPrefix(ctx, "cas/")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cas/b"}, keys)
+		assert.Equal(t, []string{"1"}, vals)
+
+		// key created after guard revision
+		assert.ErrorIs(t, kv.MultiSaveAndRemoveIf(ctx, map[string]string{"cas/c": "1"}, nil, guards[1]), ErrKeyModified)
+
+		require.NoError(t, kv.MultiSave(ctx, []string{"cas/d", "cas/e"}, []string{"1", "2"}))
+		keys, _, err = kv.LoadWithPrefix(ctx, "cas/")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cas/b", "cas/d", "cas/e"}, keys)
+
+		require.NoError(t, kv.RemoveWithPrefix(ctx, ""))
+	}
+}
+
+func TestGuardedKV(t *testing.T) {
+	ctx := context.TODO()
+	base := NewMemoryKV()
+	require.NoError(t, base.MultiSave(ctx, []string{"g/a", "g/b"}, []string{"1", "2"}))
+
+	guarded := NewGuardedKV(base)
+	_, _, err := guarded.LoadWithPrefix(ctx, "g/")
+	require.NoError(t, err)
+
+	// concurrent update after read
+	require.NoError(t, base.Save(ctx, "g/a", "10"))
+	assert.ErrorIs(t, guarded.Save(ctx, "g/a", "11"), ErrKeyModified)
+	assert.ErrorIs(t, guarded.MultiSave(ctx, []string{"g/a", "g/b"}, []string{"11", "21"}), ErrKeyModified)
+	val, err := base.Load(ctx, "g/b")
+	require.NoError(t, err)
+	assert.Equal(t, "2", val)
+
+	// read again
+	_, err = guarded.Load(ctx, "g/a")
+	require.NoError(t, err)
+	require.NoError(t, guarded.Save(ctx, "g/a", "11"))
+	require.NoError(t, guarded.Save(ctx, "g/c", "3"))
+	require.NoError(t, guarded.Remove(ctx, "g/missing"))
+
+	require.NoError(t, base.Remove(ctx, "g/b"))
+	assert.ErrorIs(t, guarded.Remove(ctx, "g/b"), ErrKeyModified)
+
+	require.NoError(t, guarded.RemoveWithPrefix(ctx, "g/"))
+	keys, _, err := base.LoadWithPrefix(ctx, "g/")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	// writes exceeding ops limit of single txn
+	for _, cli := range kvClients {
+		require.NoError(t, cli.RemoveWithPrefix(ctx, ""))
+		guarded := NewGuardedKV(cli)
+		keys := lo.Times(MaxTxnOps*3, func(i int) string { return fmt.Sprintf("g/batch/%03d", i) })
+		values := lo.Times(len(keys), func(int) string { return "v" })
+		require.NoError(t, guarded.MultiSave(ctx, keys, values))
+		loaded, _, err := cli.LoadWithPrefix(ctx, "g/batch/", WithKeysOnly())
+		require.NoError(t, err)
+		assert.Len(t, loaded, len(keys))
+
+		require.NoError(t, guarded.RemoveWithPrefix(ctx, "g/batch/"))
+		loaded, _, err = cli.LoadWithPrefix(ctx, "g/batch/", WithKeysOnly())
+		require.NoError(t, err)
+		assert.Empty(t, loaded)
+	}
+}
@@ -26,7 +26,7 @@ var (
 func setupLocalTiKV() {
 	setupLocalTxn()
 	setupLocalEtcd()
-	kvClients = []MetaKV{NewTiKV(txnClient), NewEtcdKV(etcdClient)}
+	kvClients = []MetaKV{NewTiKV(txnClient), NewEtcdKV(etcdClient), NewMemoryKV(), NewOverlayKV(NewMemoryKV())}
 }
 
 func setupLocalEtcd() {
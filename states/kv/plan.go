@@ -0,0 +1,180 @@
+package kv
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ErrPlanConflict is returned when meta changed after the plan was generated.
+var ErrPlanConflict = errors.New("meta changed since plan generated")
+
+// PlanChange is one key change in a Plan.
+type PlanChange struct {
+	Key  string       `json:"key"`
+	Type KVChangeType `json:"type"`
+	// OldDigest is the sha256 digest of value when plan generated, empty if key not exists.
+	OldDigest string `json:"old_digest,omitempty"`
+	NewValue  []byte `json:"new_value,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// Plan is the reviewable meta change set generated by a mutating command,
+// which could be applied later if the old values are not changed.
+type Plan struct {
+	Command   string        `json:"command"`
+	BasePath  string        `json:"base_path"`
+	CreatedAt time.Time     `json:"created_at"`
+	Changes   []*PlanChange `json:"changes"`
+}
+
+type changeReasonKey struct{}
+
+// WithChangeReason returns context annotating meta writes with reason,
+// which is recorded by OverlayKV and saved with each change of plan.
+func WithChangeReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, changeReasonKey{}, reason)
+}
+
+func changeReason(ctx context.Context) string {
+	reason, _ := ctx.Value(changeReasonKey{}).(string)
+	return reason
+}
+
+// NewPlan generates plan from pending changes of overlay kv.
+func NewPlan(ctx context.Context, overlay *OverlayKV, basePath, command string) (*Plan, error) {
+	changes, err := overlay.Changes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{
+		Command:   command,
+		BasePath:  basePath,
+		CreatedAt: time.Now(),
+		Changes:   make([]*PlanChange, 0, len(changes)),
+	}
+	for _, change := range changes {
+		pc := change.planChange()
+		pc.Reason = change.Reason
+		plan.Changes = append(plan.Changes, pc)
+	}
+	return plan, nil
+}
+
+// planChange converts pending change into PlanChange expecting the value before change.
+func (change *KVChange) planChange() *PlanChange {
+	pc := &PlanChange{
+		Key:  change.Key,
+		Type: change.Type,
+	}
+	if change.Existed {
+		pc.OldDigest = ValueDigest(change.Before)
+	}
+	if change.Type == KVChangePut {
+		pc.NewValue = []byte(change.After)
+	}
+	return pc
+}
+
+// ReadPlanFile reads plan from json file.
+func ReadPlanFile(file string) (*Plan, error) {
+	bs, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{}
+	if err := json.Unmarshal(bs, plan); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse plan file %s", file)
+	}
+	return plan, nil
+}
+
+// WriteFile writes plan into json file.
+func (p *Plan) WriteFile(file string) error {
+	bs, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, bs, 0o644)
+}
+
+// String returns the human readable summary of plan.
+func (p *Plan) String() string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "Plan of \"%s\" on %s, generated at %s\n", p.Command, p.BasePath, p.CreatedAt.Format(time.RFC3339))
+	for _, change := range p.Changes {
+		fmt.Fprintf(sb, "%-6s %s", change.Type, change.Key)
+		if change.Type == KVChangePut {
+			fmt.Fprintf(sb, " (%d bytes)", len(change.NewValue))
+		}
+		if change.Reason != "" {
+			fmt.Fprintf(sb, " reason: %s", change.Reason)
+		}
+		sb.WriteString("\n")
+	}
+	fmt.Fprintf(sb, "--- Total %d change(s)", len(p.Changes))
+	return sb.String()
+}
+
+// ApplyPlan applies changes of plan in batches of MaxTxnOps keys,
+// returns ErrPlanConflict if any old value changed since plan generated.
+// Old values of all changes are verified before writing, while a conflict
+// happened during writing leaves batches written before it applied.
+func ApplyPlan(ctx context.Context, cli MetaKV, plan *Plan) error {
+	err := applyChanges(ctx, cli, plan.Changes)
+	if errors.Is(err, ErrKeyModified) {
+		return errors.Wrap(ErrPlanConflict, err.Error())
+	}
+	return err
+}
+
+// applyChanges verifies old digest of all changes then writes them in batches,
+// each batch guarded by revisions of the keys loaded when verifying.
+func applyChanges(ctx context.Context, cli MetaKV, changes []*PlanChange) error {
+	ops := make([]guardedOp, 0, len(changes))
+	for _, change := range changes {
+		value, rev, err := cli.LoadWithRevision(ctx, change.Key)
+		if err != nil && !errors.Is(err, ErrKeyNotFound) {
+			return err
+		}
+		if err := change.check(value, err == nil); err != nil {
+			return err
+		}
+
+		switch change.Type {
+		case KVChangePut:
+			ops = append(ops, guardedOp{key: change.Key, value: string(change.NewValue), rev: rev})
+		case KVChangeDelete:
+			ops = append(ops, guardedOp{key: change.Key, remove: true, rev: rev})
+		default:
+			return errors.Newf("unknown change type %s of key %s", change.Type, change.Key)
+		}
+	}
+
+	return writeInBatches(ctx, cli, ops)
+}
+
+// ValueDigest returns the sha256 hex digest of value.
+func ValueDigest(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// check verifies current value of key matches the old value when plan generated.
+func (c *PlanChange) check(value string, exists bool) error {
+	var digest string
+	if exists {
+		digest = ValueDigest(value)
+	}
+	if digest != c.OldDigest {
+		return errors.Wrapf(ErrKeyModified, "key %s", c.Key)
+	}
+	return nil
+}
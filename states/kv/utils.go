@@ -1,8 +1,11 @@
 package kv
 
 import (
+	"context"
 	"path"
 	"strings"
+
+	"github.com/cockroachdb/errors"
 )
 
 func joinPath(parts ...string) string {
@@ -12,3 +15,42 @@ func joinPath(parts ...string) string {
 	}
 	return r
 }
+
+// MaxTxnOps is the max number of keys written in one conditional write,
+// etcd rejects txn with more than 128 operations by default.
+const MaxTxnOps = 64
+
+// guardedOp is a save or remove of one key guarded by the key revision.
+type guardedOp struct {
+	key    string
+	value  string
+	remove bool
+	rev    int64
+}
+
+// writeInBatches writes ops in batches of at most MaxTxnOps keys, each batch is
+// one MultiSaveAndRemoveIf guarded by revisions of the keys in it.
+// Batches are not atomic as a whole, error tells how many ops written before failure.
+func writeInBatches(ctx context.Context, cli MetaKV, ops []guardedOp) error {
+	for start := 0; start < len(ops); start += MaxTxnOps {
+		batch := ops[start:min(start+MaxTxnOps, len(ops))]
+		saves := make(map[string]string)
+		var removals []string
+		guards := make([]RevisionGuard, 0, len(batch))
+		for _, op := range batch {
+			if op.remove {
+				removals = append(removals, op.key)
+			} else {
+				saves[op.key] = op.value
+			}
+			guards = append(guards, RevisionGuard{Key: op.key, Revision: op.rev})
+		}
+		if err := cli.MultiSaveAndRemoveIf(ctx, saves, removals, guards...); err != nil {
+			if start > 0 {
+				return errors.Wrapf(err, "%d of %d key(s) written before failure", start, len(ops))
+			}
+			return err
+		}
+	}
+	return nil
+}
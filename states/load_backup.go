@@ -12,6 +12,7 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/mitchellh/go-homedir"
+	"go.etcd.io/etcd/server/v3/embed"
 
 	"github.com/milvus-io/birdwatcher/configs"
 	"github.com/milvus-io/birdwatcher/framework"
@@ -20,25 +21,14 @@ import (
 
 type LoadBackupParam struct {
 	framework.ParamBase `use:"load-backup [file]" desc:"load etcd backup file"`
-	backupFile          string
+	BackupFile          string `arg:"0" name:"file" required:"true"`
 	UseWorkspace        bool   `name:"use-workspace" default:"false"`
 	WorkspaceName       string `name:"workspace-name" default:""`
-}
-
-func (p *LoadBackupParam) ParseArgs(args []string) error {
-	if len(args) == 0 {
-		return errors.New("no backup file provided")
-	}
-	if len(args) > 1 {
-		return errors.New("only one backup file is allowed")
-	}
-
-	p.backupFile = args[0]
-	return nil
+	InMemory            bool   `name:"in-memory" default:"false" desc:"load backup into memory instead of embed etcd, no data written to disk"`
 }
 
 func (app *ApplicationState) LoadBackupCommand(ctx context.Context, p *LoadBackupParam) error {
-	f, err := openBackupFile(p.backupFile)
+	f, err := openBackupFile(p.BackupFile)
 	if err != nil {
 		return err
 	}
@@ -59,20 +49,27 @@ func (app *ApplicationState) LoadBackupCommand(ctx context.Context, p *LoadBacku
 		return err
 	}
 
+	if p.InMemory && p.UseWorkspace {
+		return errors.New("in-memory mode cannot be used with workspace")
+	}
+
 	if p.UseWorkspace {
 		if p.WorkspaceName == "" {
-			fileName := path.Base(p.backupFile)
+			fileName := path.Base(p.BackupFile)
 			p.WorkspaceName = fileName
 		}
 		p.WorkspaceName = createWorkspaceFolder(app.config, p.WorkspaceName)
 	}
 
-	server, err := startEmbedEtcdServer(p.WorkspaceName, p.UseWorkspace)
-	if err != nil {
-		fmt.Println("failed to start embed etcd server:", err.Error())
-		return err
+	var server *embed.Etcd
+	if !p.InMemory {
+		server, err = startEmbedEtcdServer(p.WorkspaceName, p.UseWorkspace)
+		if err != nil {
+			fmt.Println("failed to start embed etcd server:", err.Error())
+			return err
+		}
+		fmt.Println("using data dir:", server.Config().Dir)
 	}
-	fmt.Println("using data dir:", server.Config().Dir)
 
 	nextState := getEmbedEtcdInstanceV2(app.core, server, app.config)
 	start := time.Now()
@@ -99,10 +96,12 @@ func (app *ApplicationState) LoadBackupCommand(ctx context.Context, p *LoadBacku
 		return err
 	}
 	fmt.Println("load backup cost", time.Since(start))
-	err = nextState.setupWorkDir(server.Config().Dir)
-	if err != nil {
-		fmt.Println("failed to setup workspace for backup file", err.Error())
-		return err
+	if server != nil {
+		err = nextState.setupWorkDir(server.Config().Dir)
+		if err != nil {
+			fmt.Println("failed to setup workspace for backup file", err.Error())
+			return err
+		}
 	}
 
 	app.SetTagNext(etcdTag, nextState)
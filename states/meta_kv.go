@@ -0,0 +1,262 @@
+package states
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/kv"
+)
+
+// openEditor opens file with $EDITOR and waits until editor exits, replaced in tests.
+var openEditor = func(file string) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], file)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+type KVGetParam struct {
+	framework.ParamBase `use:"kv get" desc:"get raw meta value of key, key could be relative to instance meta path"`
+	Key                 string `arg:"0" name:"key" required:"true"`
+	Decode              bool   `name:"decode" default:"false" desc:"decode value with proto type registered for key prefix and print as json"`
+	Type                string `name:"type" default:"" desc:"proto full name to decode with, e.g. milvus.proto.data.SegmentInfo"`
+}
+
+// KVGetCommand returns `kv get` command.
+func (s *InstanceState) KVGetCommand(ctx context.Context, p *KVGetParam) error {
+	key := s.metaKey(p.Key)
+	value, err := s.client.Load(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !p.Decode && p.Type == "" {
+		fmt.Println(value)
+		return nil
+	}
+
+	msg, err := s.metaMessage(key, p.Type)
+	if err != nil {
+		// values not in proto, e.g. sessions, are json already
+		if json.Valid([]byte(value)) {
+			buf := &bytes.Buffer{}
+			json.Indent(buf, []byte(value), "", "  ")
+			fmt.Println(buf.String())
+			return nil
+		}
+		return err
+	}
+	if err := proto.Unmarshal([]byte(value), msg); err != nil {
+		return errors.Wrapf(err, "failed to decode value as %s", msg.ProtoReflect().Descriptor().FullName())
+	}
+	fmt.Printf("# %s\n", msg.ProtoReflect().Descriptor().FullName())
+	fmt.Println(protojson.MarshalOptions{Multiline: true, Indent: "  "}.Format(msg))
+	return nil
+}
+
+type KVEditParam struct {
+	framework.ParamBase `use:"kv edit" desc:"edit decoded meta value with $EDITOR, value is validated and saved with compare-and-swap"`
+	Key                 string `arg:"0" name:"key" required:"true"`
+	Type                string `name:"type" default:"" desc:"proto full name to decode with, e.g. milvus.proto.data.SegmentInfo"`
+}
+
+// KVEditCommand returns `kv edit` command.
+func (s *InstanceState) KVEditCommand(ctx context.Context, p *KVEditParam) error {
+	key := s.metaKey(p.Key)
+	value, err := s.client.Load(ctx, key)
+	if err != nil {
+		return err
+	}
+	msg, err := s.metaMessage(key, p.Type)
+	if err != nil {
+		return err
+	}
+	if err := proto.Unmarshal([]byte(value), msg); err != nil {
+		return errors.Wrapf(err, "failed to decode value as %s", msg.ProtoReflect().Descriptor().FullName())
+	}
+	if err := checkUnknownFields(key, msg); err != nil {
+		return err
+	}
+	origin, err := protojson.MarshalOptions{Multiline: true, Indent: "  ", EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp("", "bw_kv_edit_*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(origin)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	if err := openEditor(f.Name()); err != nil {
+		return errors.Wrap(err, "failed to run editor")
+	}
+	edited, err := os.ReadFile(f.Name())
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(bytes.TrimSpace(edited), bytes.TrimSpace(origin)) {
+		fmt.Println("value not changed, skip saving")
+		return nil
+	}
+
+	newValue, err := marshalMetaJSON(msg, edited)
+	if err != nil {
+		return err
+	}
+	if err := s.client.CompareAndSwap(ctx, key, value, newValue); err != nil {
+		return errors.Wrapf(err, "failed to save %s", key)
+	}
+	fmt.Printf("key %s updated\n", key)
+	return nil
+}
+
+type KVPutParam struct {
+	framework.ParamBase `use:"kv put" desc:"put meta value of key from protojson file"`
+	Key                 string `arg:"0" name:"key" required:"true"`
+	FromJSON            string `name:"from-json" default:"" required:"true" desc:"protojson file of value to put"`
+	Type                string `name:"type" default:"" desc:"proto full name to encode with, e.g. milvus.proto.data.SegmentInfo"`
+	Run                 bool   `name:"run" default:"false" desc:"actually put value, default set to \"false\" to dry run"`
+}
+
+// KVPutCommand returns `kv put` command.
+func (s *InstanceState) KVPutCommand(ctx context.Context, p *KVPutParam) error {
+	key := s.metaKey(p.Key)
+	msg, err := s.metaMessage(key, p.Type)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(p.FromJSON)
+	if err != nil {
+		return err
+	}
+	newValue, err := marshalMetaJSON(msg, data)
+	if err != nil {
+		return err
+	}
+
+	oldValue, rev, err := s.client.LoadWithRevision(ctx, key)
+	exists := err == nil
+	if err != nil && !errors.Is(err, kv.ErrKeyNotFound) {
+		return err
+	}
+	if exists {
+		old := msg.ProtoReflect().New().Interface()
+		if err := proto.Unmarshal([]byte(oldValue), old); err != nil {
+			return errors.Wrapf(err, "failed to decode current value as %s", msg.ProtoReflect().Descriptor().FullName())
+		}
+		if err := checkUnknownFields(key, old); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("put %s value as %s, key exists: %t\n", key, msg.ProtoReflect().Descriptor().FullName(), exists)
+	fmt.Println(protojson.MarshalOptions{Multiline: true, Indent: "  "}.Format(msg))
+	if !p.Run {
+		fmt.Println("dry run, use --run to put value")
+		return nil
+	}
+
+	if exists {
+		err = s.client.CompareAndSwap(ctx, key, oldValue, newValue)
+	} else {
+		// key shall still be absent
+		err = s.client.SaveIfNotModifiedSince(ctx, key, newValue, rev)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to put %s", key)
+	}
+	fmt.Printf("key %s saved\n", key)
+	return nil
+}
+
+// metaKey returns full key, key not starting with instance meta path is treated as relative one.
+func (s *InstanceState) metaKey(key string) string {
+	key = strings.TrimPrefix(key, "/")
+	if strings.HasPrefix(key, s.basePath+"/") {
+		return key
+	}
+	return path.Join(s.basePath, key)
+}
+
+// metaMessage returns proto message of type name if provided, otherwise the one registered for key prefix.
+func (s *InstanceState) metaMessage(key string, typeName string) (proto.Message, error) {
+	if typeName != "" {
+		return common.NewMetaMessageByName(typeName)
+	}
+	msg, ok := common.NewMetaMessage(strings.TrimPrefix(key, s.basePath+"/"))
+	if !ok {
+		return nil, errors.Newf("no proto type registered for key %s, use --type to specify one", key)
+	}
+	return msg, nil
+}
+
+// checkUnknownFields refuses value with fields unknown to decoding type, e.g. written by newer milvus,
+// since they are dropped by protojson and lost after saving.
+func checkUnknownFields(key string, msg proto.Message) error {
+	if hasUnknownFields(msg.ProtoReflect()) {
+		return errors.Newf("value of %s has fields unknown to %s, saving would drop them, use --type with matching proto or upgrade birdwatcher",
+			key, msg.ProtoReflect().Descriptor().FullName())
+	}
+	return nil
+}
+
+func hasUnknownFields(m protoreflect.Message) bool {
+	if len(m.GetUnknown()) > 0 {
+		return true
+	}
+	found := false
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					found = hasUnknownFields(mv.Message())
+					return !found
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				for i := 0; i < v.List().Len() && !found; i++ {
+					found = hasUnknownFields(v.List().Get(i).Message())
+				}
+			}
+		case fd.Message() != nil:
+			found = hasUnknownFields(v.Message())
+		}
+		return !found
+	})
+	return found
+}
+
+// marshalMetaJSON unmarshals & validates protojson data into msg and returns marshaled proto value.
+func marshalMetaJSON(msg proto.Message, data []byte) (string, error) {
+	if err := protojson.Unmarshal(data, msg); err != nil {
+		return "", errors.Wrapf(err, "invalid %s json", msg.ProtoReflect().Descriptor().FullName())
+	}
+	bs, err := proto.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
@@ -0,0 +1,81 @@
+package states
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+)
+
+var numOfRowsPattern = regexp.MustCompile(`"numOfRows":\s*"10"`)
+
+func TestMetaKV(t *testing.T) {
+	ctx := context.Background()
+	c := fakecluster.New(t)
+	c.AddSegment(fakecluster.NewSegment(1001, 100, 101).WithRows(10))
+	s := &InstanceState{client: c.KV(), basePath: c.BasePath()}
+	key := path.Join(common.DCPrefix, common.SegmentMetaPrefix, "100/101/1001")
+
+	loadSegment := func(key string) *datapb.SegmentInfo {
+		value, err := c.KV().Load(ctx, path.Join(c.BasePath(), key))
+		require.NoError(t, err)
+		info := &datapb.SegmentInfo{}
+		require.NoError(t, proto.Unmarshal([]byte(value), info))
+		return info
+	}
+
+	require.NoError(t, s.KVGetCommand(ctx, &KVGetParam{Key: key, Decode: true}))
+	assert.Error(t, s.KVGetCommand(ctx, &KVGetParam{Key: "unknown/1", Decode: true}))
+
+	defer func(fn func(string) error) { openEditor = fn }(openEditor)
+	openEditor = func(file string) error {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		// protojson randomizes whitespace of output
+		return os.WriteFile(file, numOfRowsPattern.ReplaceAll(data, []byte(`"numOfRows": "20"`)), 0o600)
+	}
+	require.NoError(t, s.KVEditCommand(ctx, &KVEditParam{Key: key}))
+	assert.EqualValues(t, 20, loadSegment(key).GetNumOfRows())
+
+	// invalid json is rejected and value kept
+	openEditor = func(file string) error {
+		return os.WriteFile(file, []byte(`{"numOfRows": "abc"}`), 0o600)
+	}
+	assert.Error(t, s.KVEditCommand(ctx, &KVEditParam{Key: key}))
+	assert.EqualValues(t, 20, loadSegment(key).GetNumOfRows())
+
+	jsonFile := filepath.Join(t.TempDir(), "segment.json")
+	require.NoError(t, os.WriteFile(jsonFile, []byte(`{"ID": "1002", "collectionID": "100", "numOfRows": "5"}`), 0o600))
+	newKey := path.Join(common.DCPrefix, common.SegmentMetaPrefix, "100/101/1002")
+	require.NoError(t, s.KVPutCommand(ctx, &KVPutParam{Key: newKey, FromJSON: jsonFile}))
+	_, err := c.KV().Load(ctx, path.Join(c.BasePath(), newKey))
+	assert.Error(t, err, "dry run shall not put value")
+
+	require.NoError(t, s.KVPutCommand(ctx, &KVPutParam{Key: path.Join(c.BasePath(), newKey), FromJSON: jsonFile, Run: true}))
+	assert.EqualValues(t, 5, loadSegment(newKey).GetNumOfRows())
+
+	// value with fields unknown to decoding type is refused
+	bs, err := proto.Marshal(&datapb.SegmentInfo{ID: 1003, NumOfRows: 10})
+	require.NoError(t, err)
+	bs = protowire.AppendVarint(protowire.AppendTag(bs, 9999, protowire.VarintType), 1)
+	unknownKey := path.Join(common.DCPrefix, common.SegmentMetaPrefix, "100/101/1003")
+	c.Put(unknownKey, string(bs))
+	assert.Error(t, s.KVEditCommand(ctx, &KVEditParam{Key: unknownKey}))
+	assert.Error(t, s.KVPutCommand(ctx, &KVPutParam{Key: unknownKey, FromJSON: jsonFile, Run: true}))
+	value, err := c.KV().Load(ctx, path.Join(c.BasePath(), unknownKey))
+	require.NoError(t, err)
+	assert.Equal(t, string(bs), value)
+}
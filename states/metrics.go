@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/states/etcd/common"
 	"github.com/milvus-io/birdwatcher/states/kv"
@@ -16,8 +17,9 @@ import (
 
 func getFetchMetricsCmd(cli kv.MetaKV, basePath string) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "fetch-metrics",
-		Short: "fetch metrics from milvus instances",
+		Use:         "fetch-metrics",
+		Annotations: map[string]string{framework.ReadOnlyAnnotation: "true"},
+		Short:       "fetch metrics from milvus instances",
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
@@ -10,6 +10,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/milvus-io/birdwatcher/configs"
 	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/oss"
@@ -38,6 +39,11 @@ func (s *InstanceState) TestMinioCfgCommand(ctx context.Context, p *TestMinioCfg
 }
 
 func (s *InstanceState) GetMinioClientFromCfg(ctx context.Context, params ...oss.MinioConnectParam) (client *minio.Client, bucketName, rootPath string, err error) {
+	// milvus config provided when connecting, no need to ask coordinator
+	if s.milvusConfig != nil {
+		return getMinioClientFromMilvusConfig(ctx, s.milvusConfig, params...)
+	}
+
 	sessions, err := common.ListSessions(ctx, s.client, s.basePath)
 	if err != nil {
 		return nil, "", "", err
@@ -130,6 +136,38 @@ func (s *InstanceState) GetMinioClientFromCfg(ctx context.Context, params ...oss
 	return mClient.Client, bucketName, rootPath, nil
 }
 
+func getMinioClientFromMilvusConfig(ctx context.Context, mc *configs.MilvusConfig, params ...oss.MinioConnectParam) (client *minio.Client, bucketName, rootPath string, err error) {
+	if storageType := mc.Get("common.storageType"); storageType == "local" {
+		return nil, "", "", errors.Newf("storage type %s not supported", storageType)
+	}
+
+	mp := oss.MinioClientParam{
+		CloudProvider: mc.Get("minio.cloudProvider"),
+		Region:        mc.Get("minio.region"),
+		Addr:          mc.Get("minio.address"),
+		Port:          mc.Get("minio.port"),
+		AK:            mc.Get("minio.accessKeyID"),
+		SK:            mc.Get("minio.secretAccessKey"),
+		UseIAM:        mc.GetBool("minio.useIAM"),
+		IAMEndpoint:   mc.Get("minio.iamEndpoint"),
+		UseSSL:        mc.GetBool("minio.useSSL"),
+
+		BucketName: mc.Get("minio.bucketName"),
+		RootPath:   mc.Get("minio.rootPath"),
+	}
+
+	for _, param := range params {
+		param(&mp)
+	}
+
+	mClient, err := oss.NewMinioClient(ctx, mp)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return mClient.Client, mp.BucketName, mp.RootPath, nil
+}
+
 func (s *InstanceState) GetMinioClientFromPrompt(ctx context.Context) (client *minio.Client, bucketName, rootPath string, err error) {
 	p := promptui.Prompt{
 		Label: "BucketName",
@@ -6,31 +6,17 @@ import (
 	"os"
 	"path"
 
-	"github.com/cockroachdb/errors"
-
 	"github.com/milvus-io/birdwatcher/framework"
 )
 
 type OpenParam struct {
 	framework.ParamBase `use:"open-workspace [workspace-name]" desc:"Open workspace"`
-	workspaceName       string
-}
-
-// ParseArgs parse args
-func (p *OpenParam) ParseArgs(args []string) error {
-	if len(args) == 0 {
-		return errors.New("no backup file provided")
-	}
-	if len(args) > 1 {
-		return errors.New("only one backup file is allowed")
-	}
-	p.workspaceName = args[0]
-	return nil
+	WorkspaceName       string `arg:"0" name:"workspace-name" required:"true"`
 }
 
 // OpenCommand implements open workspace command
 func (app *ApplicationState) OpenCommand(ctx context.Context, p *OpenParam) error {
-	workspaceName := p.workspaceName
+	workspaceName := p.WorkspaceName
 	workPath := path.Join(app.config.WorkspacePath, workspaceName)
 	info, err := os.Stat(workPath)
 	if os.IsNotExist(err) {
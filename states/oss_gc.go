@@ -0,0 +1,542 @@
+package states
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/minio/minio-go/v7"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/etcdpb"
+)
+
+const (
+	ossGarbageDroppedSegment    = "dropped-segment"
+	ossGarbageDroppedCollection = "dropped-collection"
+	ossGarbageUnknown           = "unknown"
+)
+
+type OssGCParam struct {
+	framework.ParamBase `use:"oss gc" desc:"detect object storage files not referenced by meta and reclaim them"`
+	MinAge              time.Duration `name:"min-age" default:"24h" desc:"only objects older than min age are reclaimable, objects of dropped segment age from segment drop time, e.g. 24h, 7d"`
+	DropTolerance       time.Duration `name:"drop-tolerance" default:"1h" desc:"extra wait after segment drop time, covers clock skew between datacoord and local host"`
+	Classes             []string      `name:"class" enum:"dropped-segment,dropped-collection,unknown" desc:"garbage classes deleted with --run, default dropped-segment & dropped-collection"`
+	Workers             int64         `name:"workers" default:"8" desc:"number of parallel listing workers"`
+	BatchSize           int64         `name:"batch-size" default:"500" desc:"number of objects deleted in one batch"`
+	RateLimit           int64         `name:"rate-limit" default:"5" desc:"max delete batches per second, 0 for unlimited"`
+	Manifest            string        `name:"manifest" default:"" desc:"manifest file recording deleted objects, default oss_gc_manifest_{time}.jsonl"`
+	Detail              bool          `name:"detail" default:"false" desc:"print each garbage object"`
+	Force               bool          `name:"force" default:"false" desc:"run even if querycoord targets are not available, dropped segments still being loaded could be deleted"`
+	Run                 bool          `name:"run" default:"false" desc:"flag to control actually run or dry"`
+}
+
+// OssGCCommand builds referenced object set from segment, segment index & stats meta,
+// scans known data prefixes of object storage and reclaims unreferenced objects.
+// Dropped segments still referenced by live compaction result or querycoord targets are treated as referenced.
+func (s *InstanceState) OssGCCommand(ctx context.Context, p *OssGCParam) error {
+	classes := p.Classes
+	if len(classes) == 0 {
+		classes = []string{ossGarbageDroppedSegment, ossGarbageDroppedCollection}
+	}
+	if p.Workers <= 0 || p.BatchSize <= 0 {
+		return errors.New("workers and batch-size shall be positive")
+	}
+
+	minioClient, bucketName, rootPath, err := s.GetMinioClientFromCfg(ctx)
+	if err != nil {
+		return err
+	}
+
+	refs, err := buildOssReferences(ctx, s, rootPath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d object(s) and %d directory(s) referenced by meta\n", len(refs.files), len(refs.dirs))
+	if refs.targets == nil && p.Run && !p.Force {
+		return errors.New("querycoord targets not available, dropped segments may still be loaded by querynodes, use --force to run anyway")
+	}
+
+	now := time.Now()
+	report, err := refs.scan(ctx, minioClient, bucketName, int(p.Workers), ossAgeCutoff{
+		modified: now.Add(-p.MinAge),
+		dropped:  now.Add(-p.MinAge - p.DropTolerance),
+	})
+	if err != nil {
+		return err
+	}
+	if p.Detail {
+		for _, obj := range report.garbage {
+			fmt.Printf("%-18s %s %s %s\n", obj.Class, obj.Key, hrSize(obj.Size), obj.LastModified.Format(time.RFC3339))
+		}
+	}
+	fmt.Println(report.String())
+
+	targets := lo.Filter(report.garbage, func(obj *ossGarbage, _ int) bool {
+		return obj.reclaimable && lo.Contains(classes, obj.Class)
+	})
+	fmt.Printf("%d object(s) %s to delete in classes %v\n", len(targets), hrSize(lo.SumBy(targets, func(obj *ossGarbage) int64 { return obj.Size })), classes)
+	if !p.Run || len(targets) == 0 {
+		return nil
+	}
+
+	manifest := p.Manifest
+	if manifest == "" {
+		manifest = fmt.Sprintf("oss_gc_manifest_%s.jsonl", time.Now().Format("20060102150405"))
+	}
+	deleted, err := deleteOssGarbage(ctx, minioClient, bucketName, targets, manifest, int(p.BatchSize), p.RateLimit)
+	fmt.Printf("%d object(s) deleted, manifest written to %s\n", deleted, manifest)
+	fmt.Println("objects in versioned bucket could be restored by removing delete markers recorded in manifest")
+	return err
+}
+
+// ossLayout describes object key layout under one data prefix of root path,
+// id positions are indexes of path parts after the prefix, -1 if not present.
+type ossLayout struct {
+	prefix string
+	// depth is the number of path parts after prefix identifying one referenced directory,
+	// 0 means objects are referenced by full key.
+	depth      int
+	collection int
+	segment    int
+	build      int
+}
+
+var ossLayouts = []ossLayout{
+	// insert_log/{coll}/{part}/{seg}/{field}/{log}
+	{prefix: "insert_log", collection: 0, segment: 2, build: -1},
+	{prefix: "stats_log", collection: 0, segment: 2, build: -1},
+	// delta_log/{coll}/{part}/{seg}/{log}
+	{prefix: "delta_log", collection: 0, segment: 2, build: -1},
+	{prefix: "bm25_stats", collection: 0, segment: 2, build: -1},
+	// index_files/{build}/{version}/{part}/{seg}/{file}
+	{prefix: "index_files", collection: -1, segment: 3, build: 0},
+	// text_log/{build}/{version}/{coll}/{part}/{seg}/{field}/...
+	{prefix: "text_log", depth: 7, collection: 2, segment: 4, build: 0},
+	{prefix: "json_key_index_log", depth: 7, collection: 2, segment: 4, build: 0},
+	// part_stats/{coll}/{part}/{vchannel}/{version}
+	{prefix: "part_stats", depth: 4, collection: 0, segment: -1, build: -1},
+}
+
+// ossReferences is the object set referenced by meta.
+type ossReferences struct {
+	rootPath string
+	files    map[string]struct{}
+	dirs     map[string]struct{}
+	// live collections
+	live     map[int64]struct{}
+	segments map[int64]*models.Segment
+	// segments with objects referenced, healthy ones and dropped ones still in use
+	kept map[int64]struct{}
+	// build id to collection id
+	builds map[int64]int64
+	// querycoord targets, nil if not available
+	targets *segmentTargets
+}
+
+func buildOssReferences(ctx context.Context, s *InstanceState, rootPath string) (*ossReferences, error) {
+	refs := &ossReferences{
+		rootPath: rootPath,
+		files:    make(map[string]struct{}),
+		dirs:     make(map[string]struct{}),
+		live:     make(map[int64]struct{}),
+		segments: make(map[int64]*models.Segment),
+		kept:     make(map[int64]struct{}),
+		builds:   make(map[int64]int64),
+	}
+
+	collections, err := common.ListCollections(ctx, s.client, s.basePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list collections")
+	}
+	for _, coll := range collections {
+		if coll.GetProto().GetState() != etcdpb.CollectionState_CollectionDropped {
+			refs.live[coll.GetProto().GetID()] = struct{}{}
+		}
+	}
+
+	segments, err := common.ListSegments(ctx, s.client, s.basePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list segments")
+	}
+	sessions, err := common.ListSessions(ctx, s.client, s.basePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list sessions")
+	}
+	targets := fetchSegmentTargets(ctx, sessions, 0)
+	refs.targets = targets
+	if targets == nil {
+		fmt.Println("querycoord targets not available, dropped segments in targets could not be excluded")
+	}
+	compactTo := make(map[int64]struct{})
+	for _, segment := range segments {
+		if !isSegmentHealthy(segment.SegmentInfo) {
+			continue
+		}
+		for _, from := range segment.GetCompactionFrom() {
+			compactTo[from] = struct{}{}
+		}
+	}
+	for _, segment := range segments {
+		refs.segments[segment.ID] = segment
+		_, compacted := compactTo[segment.ID]
+		if !isSegmentHealthy(segment.SegmentInfo) && !compacted && !targets.contains(segment.CollectionID, segment.ID) {
+			continue
+		}
+		refs.kept[segment.ID] = struct{}{}
+		refs.addSegment(segment)
+	}
+
+	segmentIndexes, err := common.ListSegmentIndex(ctx, s.client, s.basePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list segment indexes")
+	}
+	for _, segIdx := range segmentIndexes {
+		info := segIdx.GetProto()
+		refs.builds[info.GetBuildID()] = info.GetCollectionID()
+		if _, ok := refs.kept[info.GetSegmentID()]; info.GetDeleted() || !ok {
+			continue
+		}
+		for _, key := range info.GetIndexFileKeys() {
+			refs.files[path.Join(rootPath, "index_files", fmt.Sprint(info.GetBuildID()), fmt.Sprint(info.GetIndexVersion()),
+				fmt.Sprint(info.GetPartitionID()), fmt.Sprint(info.GetSegmentID()), key)] = struct{}{}
+		}
+	}
+
+	partStats, err := common.ListPartitionStats(ctx, s.client, s.basePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list partition stats")
+	}
+	for _, stats := range partStats {
+		info := stats.GetProto()
+		refs.dirs[path.Join(rootPath, "part_stats", fmt.Sprint(info.GetCollectionID()), fmt.Sprint(info.GetPartitionID()),
+			info.GetVChannel(), fmt.Sprint(info.GetVersion()))] = struct{}{}
+	}
+	return refs, nil
+}
+
+func (refs *ossReferences) addSegment(segment *models.Segment) {
+	ids := fmt.Sprintf("%d/%d/%d", segment.CollectionID, segment.PartitionID, segment.ID)
+	addLogs := func(prefix string, withField bool, fieldBinlogs ...*datapb.FieldBinlog) {
+		for _, fieldBinlog := range fieldBinlogs {
+			for _, binlog := range fieldBinlog.GetBinlogs() {
+				logPath := binlog.GetLogPath()
+				if logPath == "" {
+					logPath = path.Join(prefix, ids, fmt.Sprint(binlog.GetLogID()))
+					if withField {
+						logPath = path.Join(prefix, ids, fmt.Sprint(fieldBinlog.GetFieldID()), fmt.Sprint(binlog.GetLogID()))
+					}
+				}
+				refs.files[resolveLogPath(refs.rootPath, logPath)] = struct{}{}
+			}
+		}
+	}
+	addModelLogs := func(fieldBinlogs []*models.FieldBinlog) {
+		for _, fieldBinlog := range fieldBinlogs {
+			for _, binlog := range fieldBinlog.Binlogs {
+				refs.files[resolveLogPath(refs.rootPath, binlog.LogPath)] = struct{}{}
+			}
+		}
+	}
+
+	// binlogs embedded in legacy segment info
+	addLogs("insert_log", true, segment.SegmentInfo.GetBinlogs()...)
+	addLogs("stats_log", true, segment.SegmentInfo.GetStatslogs()...)
+	addLogs("delta_log", false, segment.SegmentInfo.GetDeltalogs()...)
+	addLogs("bm25_stats", true, segment.SegmentInfo.GetBm25Statslogs()...)
+	addModelLogs(segment.GetBinlogs())
+	addModelLogs(segment.GetStatslogs())
+	addModelLogs(segment.GetDeltalogs())
+	addModelLogs(segment.GetBm25Statslogs())
+
+	for fieldID, stats := range segment.SegmentInfo.GetTextStatsLogs() {
+		refs.dirs[path.Join(refs.rootPath, "text_log", fmt.Sprint(stats.GetBuildID()), fmt.Sprint(stats.GetVersion()), ids, fmt.Sprint(fieldID))] = struct{}{}
+	}
+	for fieldID, stats := range segment.SegmentInfo.GetJsonKeyStats() {
+		refs.dirs[path.Join(refs.rootPath, "json_key_index_log", fmt.Sprint(stats.GetBuildID()), fmt.Sprint(stats.GetVersion()), ids, fmt.Sprint(fieldID))] = struct{}{}
+	}
+}
+
+// resolveLogPath returns object key of log path, which could be relative to root path or with ROOT_PATH placeholder.
+func resolveLogPath(rootPath, logPath string) string {
+	if rest, ok := strings.CutPrefix(logPath, "ROOT_PATH/"); ok {
+		return path.Join(rootPath, rest)
+	}
+	if strings.HasPrefix(logPath, rootPath+"/") {
+		return logPath
+	}
+	return path.Join(rootPath, logPath)
+}
+
+// classify returns whether object is referenced, and the garbage class if not.
+// droppedAt is the drop time of the dropped segment object belongs to, zero if unknown.
+func (refs *ossReferences) classify(layout ossLayout, key string) (referenced bool, class string, droppedAt time.Time) {
+	parts := strings.Split(strings.TrimPrefix(key, path.Join(refs.rootPath, layout.prefix)+"/"), "/")
+	if layout.depth == 0 {
+		if _, ok := refs.files[key]; ok {
+			return true, "", time.Time{}
+		}
+	} else if len(parts) >= layout.depth {
+		if _, ok := refs.dirs[path.Join(append([]string{refs.rootPath, layout.prefix}, parts[:layout.depth]...)...)]; ok {
+			return true, "", time.Time{}
+		}
+	}
+
+	id := func(idx int) (int64, bool) {
+		if idx < 0 || idx >= len(parts) {
+			return 0, false
+		}
+		v, err := strconv.ParseInt(parts[idx], 10, 64)
+		return v, err == nil
+	}
+	collectionID, hasCollection := id(layout.collection)
+	segmentID, hasSegment := id(layout.segment)
+	segment, segmentFound := refs.segments[segmentID]
+	if !hasCollection {
+		switch buildID, ok := id(layout.build); {
+		case hasSegment && segmentFound:
+			collectionID, hasCollection = segment.CollectionID, true
+		case ok:
+			collectionID, hasCollection = refs.builds[buildID]
+		}
+	}
+
+	if hasCollection {
+		if _, ok := refs.live[collectionID]; !ok {
+			return false, ossGarbageDroppedCollection, time.Time{}
+		}
+	}
+	if _, kept := refs.kept[segmentID]; hasSegment && segmentFound && !kept {
+		if segment.GetDroppedAt() > 0 {
+			droppedAt = time.Unix(0, int64(segment.GetDroppedAt()))
+		}
+		return false, ossGarbageDroppedSegment, droppedAt
+	}
+	return false, ossGarbageUnknown, time.Time{}
+}
+
+// ossAgeCutoff is the age policy of unreferenced objects.
+type ossAgeCutoff struct {
+	// modified is the cutoff of object last modified time
+	modified time.Time
+	// dropped is the cutoff of segment drop time, used for objects of dropped segment
+	dropped time.Time
+}
+
+// reclaimable returns whether unreferenced object is old enough to delete.
+// Objects of dropped segment are gated on segment drop time, since compaction or import may write them long before drop.
+func (c ossAgeCutoff) reclaimable(obj minio.ObjectInfo, droppedAt time.Time) bool {
+	if !droppedAt.IsZero() {
+		return droppedAt.Before(c.dropped)
+	}
+	return obj.LastModified.Before(c.modified)
+}
+
+type ossGarbage struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+	Class        string    `json:"class"`
+	// DeleteMarkerVersionID is the delete marker created in versioned bucket, remove it to restore the object.
+	DeleteMarkerVersionID string `json:"delete_marker_version_id,omitempty"`
+
+	reclaimable bool
+}
+
+type ossClassStat struct {
+	objects, bytes               int64
+	reclaimObjects, reclaimBytes int64
+}
+
+type ossGCReport struct {
+	mut        sync.Mutex
+	scanned    int64
+	scannedSz  int64
+	referenced int64
+	classes    map[string]*ossClassStat
+	garbage    []*ossGarbage
+}
+
+func (r *ossGCReport) add(obj minio.ObjectInfo, referenced bool, class string, reclaimable bool) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	r.scanned++
+	r.scannedSz += obj.Size
+	if referenced {
+		r.referenced++
+		return
+	}
+	garbage := &ossGarbage{
+		Key:          obj.Key,
+		Size:         obj.Size,
+		ETag:         obj.ETag,
+		LastModified: obj.LastModified,
+		Class:        class,
+		reclaimable:  reclaimable,
+	}
+	r.garbage = append(r.garbage, garbage)
+	stat, ok := r.classes[class]
+	if !ok {
+		stat = &ossClassStat{}
+		r.classes[class] = stat
+	}
+	stat.objects++
+	stat.bytes += obj.Size
+	if garbage.reclaimable {
+		stat.reclaimObjects++
+		stat.reclaimBytes += obj.Size
+	}
+}
+
+func (r *ossGCReport) String() string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "Scanned %d object(s) %s, %d referenced\n", r.scanned, hrSize(r.scannedSz), r.referenced)
+	classes := lo.Keys(r.classes)
+	sort.Strings(classes)
+	for _, class := range classes {
+		stat := r.classes[class]
+		fmt.Fprintf(sb, "%-18s %d object(s) %s, reclaimable %d object(s) %s\n", class, stat.objects, hrSize(stat.bytes), stat.reclaimObjects, hrSize(stat.reclaimBytes))
+	}
+	fmt.Fprintf(sb, "--- Total garbage %d object(s)", len(r.garbage))
+	return sb.String()
+}
+
+// scan lists objects under data prefixes in parallel, each top level directory of prefix is listed by one worker.
+func (refs *ossReferences) scan(ctx context.Context, client *minio.Client, bucketName string, workers int, cutoff ossAgeCutoff) (*ossGCReport, error) {
+	type task struct {
+		layout ossLayout
+		prefix string
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	report := &ossGCReport{classes: make(map[string]*ossClassStat)}
+	tasks := make(chan task, workers)
+	errCh := make(chan error, workers+1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range tasks {
+				for obj := range client.ListObjects(ctx, bucketName, minio.ListObjectsOptions{Prefix: t.prefix, Recursive: true}) {
+					if obj.Err != nil {
+						errCh <- errors.Wrapf(obj.Err, "failed to list %s", t.prefix)
+						cancel()
+						return
+					}
+					referenced, class, droppedAt := refs.classify(t.layout, obj.Key)
+					report.add(obj, referenced, class, cutoff.reclaimable(obj, droppedAt))
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(tasks)
+		for _, layout := range ossLayouts {
+			prefix := path.Join(refs.rootPath, layout.prefix) + "/"
+			for obj := range client.ListObjects(ctx, bucketName, minio.ListObjectsOptions{Prefix: prefix}) {
+				if obj.Err != nil {
+					errCh <- errors.Wrapf(obj.Err, "failed to list %s", prefix)
+					cancel()
+					return
+				}
+				if !strings.HasSuffix(obj.Key, "/") {
+					referenced, class, droppedAt := refs.classify(layout, obj.Key)
+					report.add(obj, referenced, class, cutoff.reclaimable(obj, droppedAt))
+					continue
+				}
+				select {
+				case tasks <- task{layout: layout, prefix: obj.Key}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(errCh)
+	if err := <-errCh; err != nil {
+		return nil, err
+	}
+	sort.Slice(report.garbage, func(i, j int) bool { return report.garbage[i].Key < report.garbage[j].Key })
+	return report, nil
+}
+
+// deleteOssGarbage deletes objects in rate limited batches, each deleted object is recorded into manifest.
+func deleteOssGarbage(ctx context.Context, client *minio.Client, bucketName string, targets []*ossGarbage, manifest string, batchSize int, rateLimit int64) (int, error) {
+	f, err := os.Create(manifest)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	defer w.Flush()
+
+	var limiter <-chan time.Time
+	if rateLimit > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(rateLimit))
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+
+	var deleted int
+	var failed []string
+	for idx, batch := range lo.Chunk(targets, batchSize) {
+		if idx > 0 && limiter != nil {
+			select {
+			case <-ctx.Done():
+				return deleted, ctx.Err()
+			case <-limiter:
+			}
+		}
+		objects := lo.SliceToMap(batch, func(obj *ossGarbage) (string, *ossGarbage) { return obj.Key, obj })
+		ch := make(chan minio.ObjectInfo, len(batch))
+		for _, obj := range batch {
+			ch <- minio.ObjectInfo{Key: obj.Key}
+		}
+		close(ch)
+		for result := range client.RemoveObjectsWithResult(ctx, bucketName, ch, minio.RemoveObjectsOptions{}) {
+			if result.Err != nil {
+				failed = append(failed, result.ObjectName)
+				fmt.Printf("failed to delete %s, err: %s\n", result.ObjectName, result.Err.Error())
+				continue
+			}
+			obj, ok := objects[result.ObjectName]
+			if !ok {
+				continue
+			}
+			obj.DeleteMarkerVersionID = result.DeleteMarkerVersionID
+			bs, err := json.Marshal(obj)
+			if err != nil {
+				return deleted, err
+			}
+			w.Write(bs)
+			w.WriteString("\n")
+			deleted++
+		}
+		if err := w.Flush(); err != nil {
+			return deleted, err
+		}
+	}
+	if len(failed) > 0 {
+		return deleted, errors.Newf("%d object(s) failed to delete", len(failed))
+	}
+	return deleted, nil
+}
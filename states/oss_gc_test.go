@@ -0,0 +1,119 @@
+package states
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
+)
+
+func TestOssGC(t *testing.T) {
+	ctx := context.Background()
+	c := fakecluster.New(t)
+	s3 := c.StartS3()
+	c.StartServer(fakecluster.RoleRootCoord, 1)
+	// segment 1006 is still in querycoord target
+	qc := c.StartServer(fakecluster.RoleQueryCoord, 10)
+	targetsAvailable := true
+	qc.Handle("GetMetrics", func(ctx context.Context, req any) (any, error) {
+		if !targetsAvailable {
+			return nil, errors.New("mock error")
+		}
+		bs, _ := json.Marshal([]*metricsinfo.QueryCoordTarget{{
+			CollectionID: 100,
+			Segments:     []*metricsinfo.Segment{{SegmentID: 1006}},
+		}})
+		return &milvuspb.GetMetricsResponse{Status: &commonpb.Status{}, Response: string(bs)}, nil
+	})
+
+	longAgo := time.Now().Add(-48 * time.Hour)
+	healthy := fakecluster.NewSegment(1001, 100, 101).WithRows(10).WithBinlog(100, 1).WithBm25Statslog(102, 5)
+	// legacy dropped segment without drop time
+	dropped := fakecluster.NewSegment(1002, 100, 101).WithRows(10).WithBinlog(100, 2).WithState(commonpb.SegmentState_Dropped)
+	droppedLongAgo := fakecluster.NewSegment(1003, 100, 101).WithRows(10).WithBinlog(100, 6).WithState(commonpb.SegmentState_Dropped).WithDroppedAt(longAgo)
+	compacted := fakecluster.NewSegment(1004, 100, 101).WithRows(10).WithBinlog(100, 7).WithState(commonpb.SegmentState_Dropped).WithDroppedAt(longAgo)
+	compactTo := fakecluster.NewSegment(1005, 100, 101).WithRows(10).WithBinlog(100, 10).WithCompactionFrom(1004)
+	inTarget := fakecluster.NewSegment(1006, 100, 101).WithRows(10).WithBinlog(100, 8).WithState(commonpb.SegmentState_Dropped).WithDroppedAt(longAgo)
+	droppedRecently := fakecluster.NewSegment(1007, 100, 101).WithRows(10).WithBinlog(100, 9).WithState(commonpb.SegmentState_Dropped).WithDroppedAt(time.Now())
+	// collection 200 meta is gone, binlog still referenced by segment meta
+	orphan := fakecluster.NewSegment(2001, 200, 201).WithRows(10).WithBinlog(100, 3)
+	c.AddCollection(fakecluster.NewCollection(100, "coll").WithPrimaryKey(100, "pk").WithPartition(101, "_default")).
+		AddSegmentIndex(fakecluster.NewSegmentIndex(healthy, 10, 11).WithFiles("HNSW")).
+		AddSegmentIndex(fakecluster.NewSegmentIndex(dropped, 10, 12).WithFiles("HNSW"))
+	for _, segment := range []*fakecluster.SegmentBuilder{healthy, dropped, droppedLongAgo, compacted, compactTo, inTarget, droppedRecently, orphan} {
+		c.AddSegment(segment)
+		require.NoError(t, s3.PutInsertBinlogs(segment))
+	}
+	s3.PutObject(fakecluster.S3Bucket, "files/bm25_stats/100/101/1001/102/5", []byte("bm25"))
+	s3.PutObject(fakecluster.S3Bucket, "files/index_files/11/1/101/1001/HNSW", []byte("index"))
+	s3.PutObject(fakecluster.S3Bucket, "files/index_files/12/1/101/1002/HNSW", []byte("index"))
+	// segment meta of dropped collection is gone
+	s3.PutObject(fakecluster.S3Bucket, "files/insert_log/200/201/2002/100/4", []byte("log"))
+	// not referenced by healthy segment
+	s3.PutObject(fakecluster.S3Bucket, "files/insert_log/100/101/1001/100/99", []byte("log"))
+	// out of known layouts
+	s3.PutObject(fakecluster.S3Bucket, "files/other/file", []byte("other"))
+
+	s := &InstanceState{client: c.KV(), basePath: c.BasePath()}
+	before := s3.Keys(fakecluster.S3Bucket, "")
+
+	// dry run
+	require.NoError(t, s.OssGCCommand(ctx, &OssGCParam{MinAge: 0, Workers: 2, BatchSize: 2}))
+	assert.ElementsMatch(t, before, s3.Keys(fakecluster.S3Bucket, ""))
+
+	// run refused without querycoord targets unless forced
+	targetsAvailable = false
+	assert.Error(t, s.OssGCCommand(ctx, &OssGCParam{MinAge: 0, Workers: 2, BatchSize: 2, Run: true}))
+	assert.ElementsMatch(t, before, s3.Keys(fakecluster.S3Bucket, ""))
+	require.NoError(t, s.OssGCCommand(ctx, &OssGCParam{MinAge: 1000 * time.Hour, Workers: 2, BatchSize: 2, Run: true, Force: true}))
+	assert.ElementsMatch(t, before, s3.Keys(fakecluster.S3Bucket, ""))
+	targetsAvailable = true
+
+	// young objects are kept, unless segment dropped long ago
+	require.NoError(t, s.OssGCCommand(ctx, &OssGCParam{MinAge: time.Hour, Workers: 2, BatchSize: 2, Manifest: filepath.Join(t.TempDir(), "young.jsonl"), Run: true}))
+	assert.ElementsMatch(t, lo.Without(before, "files/insert_log/100/101/1003/100/6"), s3.Keys(fakecluster.S3Bucket, ""))
+
+	manifest := filepath.Join(t.TempDir(), "manifest.jsonl")
+	require.NoError(t, s.OssGCCommand(ctx, &OssGCParam{MinAge: 0, DropTolerance: time.Hour, Workers: 2, BatchSize: 2, Manifest: manifest, Run: true}))
+	assert.ElementsMatch(t, []string{
+		"files/insert_log/100/101/1001/100/1",
+		"files/insert_log/100/101/1001/100/99",
+		"files/bm25_stats/100/101/1001/102/5",
+		"files/insert_log/100/101/1004/100/7",
+		"files/insert_log/100/101/1005/100/10",
+		"files/insert_log/100/101/1006/100/8",
+		"files/insert_log/100/101/1007/100/9",
+		"files/insert_log/200/201/2001/100/3",
+		"files/index_files/11/1/101/1001/HNSW",
+		"files/other/file",
+	}, s3.Keys(fakecluster.S3Bucket, ""))
+
+	f, err := os.Open(manifest)
+	require.NoError(t, err)
+	defer f.Close()
+	classes := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		obj := &ossGarbage{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), obj))
+		classes[obj.Key] = obj.Class
+	}
+	assert.Equal(t, map[string]string{
+		"files/insert_log/100/101/1002/100/2":  ossGarbageDroppedSegment,
+		"files/index_files/12/1/101/1002/HNSW": ossGarbageDroppedSegment,
+		"files/insert_log/200/201/2002/100/4":  ossGarbageDroppedCollection,
+	}, classes)
+}
@@ -22,20 +22,12 @@ import (
 
 type ParseIndexParam struct {
 	framework.ParamBase `use:"parse-indexparam [file]" desc:"parse index params"`
-	filePath            string
-}
-
-func (p *ParseIndexParam) ParseArgs(args []string) error {
-	if len(args) != 1 {
-		return errors.New("should provide only one file path")
-	}
-	p.filePath = args[0]
-	return nil
+	FilePath            string `arg:"0" name:"file" required:"true"`
 }
 
 // ParseIndexParamCommand parses index params from file.
 func (app *ApplicationState) ParseIndexParamCommand(ctx context.Context, p *ParseIndexParam) error {
-	f, err := openBackupFile(p.filePath)
+	f, err := openBackupFile(p.FilePath)
 	if err != nil {
 		return err
 	}
@@ -75,19 +67,11 @@ func (app *ApplicationState) ParseIndexParamCommand(ctx context.Context, p *Pars
 
 type ValidateIndexParam struct {
 	framework.ParamBase `use:"validate-indexfiles [directory]" desc:"validate index file size"`
-	directory           string
-}
-
-func (p *ValidateIndexParam) ParseArgs(args []string) error {
-	if len(args) != 1 {
-		return errors.New("should provide only one folder path")
-	}
-	p.directory = args[0]
-	return nil
+	Directory           string `arg:"0" name:"directory" required:"true"`
 }
 
 func (app *ApplicationState) ValidateIndexFilesCommand(ctx context.Context, p *ValidateIndexParam) error {
-	folder := p.directory
+	folder := p.Directory
 	if err := testFolder(folder); err != nil {
 		return err
 	}
@@ -178,19 +162,11 @@ func validateIndexFolder(fp string, params map[string]string) {
 
 type AssembleIndexFilesParam struct {
 	framework.ParamBase `use:"assemble-indexfiles [directory]" desc:""`
-	directory           string
-}
-
-func (p *AssembleIndexFilesParam) ParseArgs(args []string) error {
-	if len(args) != 1 {
-		return errors.New("should provide only one folder path")
-	}
-	p.directory = args[0]
-	return nil
+	Directory           string `arg:"0" name:"directory" required:"true"`
 }
 
 func (app *ApplicationState) AssembleIndexFilesCommand(ctx context.Context, p *AssembleIndexFilesParam) error {
-	folder := p.directory
+	folder := p.Directory
 	if err := testFolder(folder); err != nil {
 		return err
 	}
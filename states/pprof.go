@@ -20,7 +20,7 @@ import (
 
 type PprofParam struct {
 	framework.ParamBase `use:"pprof" desc:"get pprof from online components"`
-	Type                string `name:"type" default:"goroutine" desc:"pprof metric type to fetch"`
+	Type                string `name:"type" default:"goroutine" enum:"goroutine,heap,profile,allocs,block,mutex" desc:"pprof metric type to fetch"`
 	Port                int64  `name:"port" default:"9091" desc:"metrics port milvus component is using"`
 }
 
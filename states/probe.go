@@ -137,7 +137,7 @@ func getProbeQueryCmd(cli kv.MetaKV, basePath string) *cobra.Command {
 
 type ProbePKParam struct {
 	framework.ParamBase `use:"probe pk" desc:"probe pk in segment"`
-	CollectionID        int64    `name:"collection" default:"0" desc:"collection id to probe"`
+	CollectionID        int64    `name:"collection" resolve:"collection" default:"0" desc:"collection id to probe"`
 	PK                  string   `name:"pk" default:"" desc:"pk value to probe"`
 	OutputFields        []string `name:"outputField" default:"" desc:"output fields list"`
 	MvccTimestamp       int64    `name:"mvccTimestamp" default:"0" desc:"mvcc timestamp to probe"`
@@ -149,6 +149,8 @@ func (s *InstanceState) ProbePKCommand(ctx context.Context, p *ProbePKParam) err
 		return err
 	}
 
+	fmt.Printf("Probing pk %s in collection %s\n", p.PK,
+		common.CollectionLabel(common.CollectionNames(ctx, s.client, s.basePath), coll.GetProto().GetID()))
 	pkf, _ := coll.GetPKField()
 	var datatype schemapb.DataType
 	var val *planpb.GenericValue
@@ -22,7 +22,7 @@ import (
 
 type ScanBinlogParams struct {
 	framework.ParamBase `use:"scan-binlog" desc:"scan binlog to check data"`
-	CollectionID        int64    `name:"collection" default:"0"`
+	CollectionID        int64    `name:"collection" resolve:"collection" default:"0"`
 	SegmentID           int64    `name:"segment" default:"0"`
 	Fields              []string `name:"fields"`
 	Expr                string   `name:"expr"`
@@ -38,7 +38,8 @@ func (s *InstanceState) ScanBinlogCommand(ctx context.Context, p *ScanBinlogPara
 	if err != nil {
 		return err
 	}
-	fmt.Println("=== Checking collection schema ===")
+	fmt.Printf("=== Checking collection %s schema ===\n",
+		common.CollectionLabel(common.CollectionNames(ctx, s.client, s.basePath), collection.GetProto().GetID()))
 	pkField, ok := collection.GetPKField()
 	if !ok {
 		return errors.New("pk field not found")
@@ -21,7 +21,7 @@ import (
 
 type ScanDeltalogParams struct {
 	framework.ParamBase `use:"scan-deltalog" desc:"scan deltalog to check delta data"`
-	CollectionID        int64    `name:"collection" default:"0"`
+	CollectionID        int64    `name:"collection" resolve:"collection" default:"0"`
 	SegmentID           int64    `name:"segment" default:"0"`
 	Fields              []string `name:"fields"`
 	Expr                string   `name:"expr"`
@@ -4,8 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/cockroachdb/errors"
-
 	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/states/etcd/common"
@@ -14,28 +12,14 @@ import (
 
 type StorageAnalysisParam struct {
 	framework.ParamBase `use:"storage-analysis" desc:"segment storage analysis" require:"etcd,minio"`
-	CollectionID        int64 `name:"collection" default:"0" desc:"collection id to analysis"`
-	Detail              bool  `name:"detail" default:"false" desc:"print detailed binlog size info"`
+	CollectionID        int64               `name:"collection" resolve:"collection" default:"0" desc:"collection id to analysis"`
+	Detail              bool                `name:"detail" default:"false" desc:"print detailed binlog size info"`
+	Etcd                *InstanceState      `state:"etcd"`
+	Minio               *storage.MinioState `state:"minio"`
 }
 
 func (app *ApplicationState) StorageAnalysisCommand(ctx context.Context, p *StorageAnalysisParam) error {
-	state, ok := app.states[etcdTag]
-	if !ok {
-		return errors.New("Etcd instance not connected")
-	}
-	etcd, ok := state.(*InstanceState)
-	if !ok {
-		return errors.New("Etcd instance not connected")
-	}
-	state, ok = app.states[minioTag]
-	if !ok {
-		return errors.New("Minio instance not connected")
-	}
-	minio, ok := state.(*storage.MinioState)
-	if !ok {
-		return errors.New("Minio instance not connected")
-	}
-
+	etcd, minio := p.Etcd, p.Minio
 	segments, err := common.ListSegments(ctx, etcd.client, etcd.basePath, func(s *models.Segment) bool {
 		return p.CollectionID == 0 || s.CollectionID == p.CollectionID
 	})
@@ -17,8 +17,9 @@ const (
 // Start returns the first state - offline.
 func Start(config *configs.Config, multiStage bool) framework.State {
 	app := &ApplicationState{
-		states: map[string]framework.State{},
-		config: config,
+		states:    map[string]framework.State{},
+		config:    config,
+		instances: map[string]*instanceHandle{},
 	}
 
 	app.core = framework.NewCmdState("[core]", config)
@@ -2,7 +2,6 @@ package storage
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"path"
 	"strings"
@@ -25,21 +24,7 @@ func (s *MinioState) getBase() string {
 type LsParam struct {
 	framework.ParamBase `use:"ls" desc:"ls file/folder"`
 
-	prefix string
-}
-
-func (p *LsParam) ParseArgs(args []string) error {
-	if len(args) == 0 {
-		return nil
-	}
-
-	if len(args) > 1 {
-		return errors.New("too many parameters")
-	}
-
-	p.prefix = args[0]
-
-	return nil
+	Prefix string `arg:"0" name:"prefix"`
 }
 
 func (s *MinioState) LsCommand(ctx context.Context, p *LsParam) error {
@@ -72,36 +57,22 @@ func (s *MinioState) LsCommand(ctx context.Context, p *LsParam) error {
 type CdParam struct {
 	framework.ParamBase `use:"cd" desc:"ls file/folder"`
 
-	prefix string
-}
-
-func (p *CdParam) ParseArgs(args []string) error {
-	if len(args) == 0 {
-		return nil
-	}
-
-	if len(args) > 1 {
-		return errors.New("too many parameters")
-	}
-
-	p.prefix = args[0]
-
-	return nil
+	Prefix string `arg:"0" name:"prefix"`
 }
 
 func (s *MinioState) CdCommand(ctx context.Context, p *CdParam) error {
 	base := s.getBase()
 
 	// use absolute path
-	if strings.HasPrefix(p.prefix, "/") {
-		base = path.Dir(strings.TrimPrefix(p.prefix, "/"))
+	if strings.HasPrefix(p.Prefix, "/") {
+		base = path.Dir(strings.TrimPrefix(p.Prefix, "/"))
 	} else {
-		base = path.Join(base, path.Dir(p.prefix))
+		base = path.Join(base, path.Dir(p.Prefix))
 	}
 	if base == "." {
 		base = ""
 	}
-	p.prefix = strings.TrimSuffix(path.Base(p.prefix), "/")
+	p.Prefix = strings.TrimSuffix(path.Base(p.Prefix), "/")
 	if !strings.HasSuffix(base, "/") {
 		base += "/"
 	}
@@ -121,12 +92,12 @@ func (s *MinioState) CdCommand(ctx context.Context, p *CdParam) error {
 		}
 	}
 
-	fmt.Println(base, p.prefix, folders)
-	if _, ok := folders[p.prefix]; !ok {
-		return fmt.Errorf("folder %s not exists", p.prefix)
+	fmt.Println(base, p.Prefix, folders)
+	if _, ok := folders[p.Prefix]; !ok {
+		return fmt.Errorf("folder %s not exists", p.Prefix)
 	}
 
-	s.prefix = path.Join(base, p.prefix)
+	s.prefix = path.Join(base, p.Prefix)
 
 	return nil
 }
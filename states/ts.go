@@ -0,0 +1,117 @@
+package states
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/utils"
+)
+
+// TSInfo is the parsed hybrid timestamp.
+type TSInfo struct {
+	TS       uint64
+	Physical time.Time
+	Logical  uint64
+}
+
+func newTSInfo(ts uint64) *TSInfo {
+	physical, logical := utils.ParseTS(ts)
+	return &TSInfo{TS: ts, Physical: physical, Logical: logical}
+}
+
+type TSInfos struct {
+	framework.ListResultSet[*TSInfo]
+}
+
+func (rs *TSInfos) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatDefault, framework.FormatPlain:
+		sb := &strings.Builder{}
+		for _, info := range rs.Data {
+			fmt.Fprintf(sb, "TS: %d\n", info.TS)
+			fmt.Fprintf(sb, "\tPhysical(UTC):   %s\n", info.Physical.UTC().Format(time.RFC3339Nano))
+			fmt.Fprintf(sb, "\tPhysical(Local): %s\n", info.Physical.Local().Format(time.RFC3339Nano))
+			fmt.Fprintf(sb, "\tLogical:         %d\n", info.Logical)
+		}
+		return sb.String()
+	default:
+	}
+	return ""
+}
+
+type TSParseParam struct {
+	framework.ParamBase `use:"ts parse" alias:"/parse-ts" desc:"parse hybrid timestamp into physical time & logical counter"`
+	TS                  []string `arg:"*" name:"tso"`
+}
+
+// TSParseCommand returns `ts parse` command.
+func (app *ApplicationState) TSParseCommand(ctx context.Context, p *TSParseParam) (*TSInfos, error) {
+	if len(p.TS) == 0 {
+		return nil, errors.New("no ts provided")
+	}
+	infos := make([]*TSInfo, 0, len(p.TS))
+	for _, arg := range p.TS {
+		ts, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse ts from %s", arg)
+		}
+		infos = append(infos, newTSInfo(ts))
+	}
+	return framework.NewListResult[TSInfos](infos), nil
+}
+
+type TSMakeParam struct {
+	framework.ParamBase `use:"ts make" desc:"make hybrid timestamp from time, e.g. 2024-01-01T00:00:00Z or now-5m"`
+	Time                time.Time `arg:"0" name:"time" required:"true"`
+	Logical             int64     `name:"logical" default:"0" desc:"logical counter of hybrid timestamp"`
+}
+
+// TSMakeCommand returns `ts make` command.
+func (app *ApplicationState) TSMakeCommand(ctx context.Context, p *TSMakeParam) (*TSInfos, error) {
+	if p.Logical < 0 || p.Logical >= 1<<18 {
+		return nil, errors.Newf("logical counter %d out of range [0, %d)", p.Logical, 1<<18)
+	}
+	ts := utils.MakeTS(p.Time, uint64(p.Logical))
+	return framework.NewListResult[TSInfos]([]*TSInfo{newTSInfo(ts)}), nil
+}
+
+type TSDiffParam struct {
+	framework.ParamBase `use:"ts diff" desc:"print time difference between two hybrid timestamps or times"`
+	From                string `arg:"0" name:"a" required:"true"`
+	To                  string `arg:"1" name:"b" required:"true"`
+}
+
+// TSDiffCommand returns `ts diff` command.
+func (app *ApplicationState) TSDiffCommand(ctx context.Context, p *TSDiffParam) error {
+	from, err := parseTSArg(p.From)
+	if err != nil {
+		return err
+	}
+	to, err := parseTSArg(p.To)
+	if err != nil {
+		return err
+	}
+	fromInfo, toInfo := newTSInfo(from), newTSInfo(to)
+	fmt.Printf("a: %d\t%s\n", from, fromInfo.Physical.Format(time.RFC3339Nano))
+	fmt.Printf("b: %d\t%s\n", to, toInfo.Physical.Format(time.RFC3339Nano))
+	fmt.Printf("b - a: %s, logical: %d\n", toInfo.Physical.Sub(fromInfo.Physical), int64(toInfo.Logical)-int64(fromInfo.Logical))
+	return nil
+}
+
+// parseTSArg parses hybrid timestamp, or time as timestamp with zero logical counter.
+func parseTSArg(raw string) (uint64, error) {
+	if ts, err := strconv.ParseUint(raw, 10, 64); err == nil {
+		return ts, nil
+	}
+	t, err := utils.ParseTimeExpr(raw, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return utils.MakeTS(t, 0), nil
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/milvus-io/birdwatcher/framework"
 	"github.com/milvus-io/birdwatcher/models"
 	"github.com/milvus-io/birdwatcher/states/etcd/common"
 	"github.com/milvus-io/birdwatcher/states/kv"
@@ -22,8 +23,9 @@ const httpAPIListenPort = 9091
 
 func getShowLogLevelCmd(cli kv.MetaKV, basePath string) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "show-log-level",
-		Short: "show log level of milvus roles",
+		Use:         "show-log-level",
+		Annotations: map[string]string{framework.ReadOnlyAnnotation: "true"},
+		Short:       "show log level of milvus roles",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
@@ -0,0 +1,354 @@
+package states
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"github.com/minio/minio-go/v7"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/birdwatcher/states/etcd/common"
+	"github.com/milvus-io/birdwatcher/storage"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+)
+
+const (
+	verifyMissing   = "missing"
+	verifySize      = "size"
+	verifyHeader    = "header"
+	verifyRows      = "rows"
+	verifyTimestamp = "timestamp"
+)
+
+type VerifySegmentsParam struct {
+	framework.ParamBase `use:"verify segments" desc:"verify insert/stats/delta/bm25 logs and index files of flushed, sealed & L0 segments are complete in object storage"`
+	CollectionID        int64  `name:"collection" resolve:"collection" default:"0" desc:"collection id to verify"`
+	SegmentID           int64  `name:"segment" default:"0" desc:"segment id to verify"`
+	Workers             int64  `name:"workers" default:"8" desc:"number of parallel verifying workers"`
+	ReadRows            bool   `name:"read-rows" default:"false" desc:"read insert log payloads to verify row count, downloads all insert logs"`
+	Output              string `name:"output" default:"" desc:"file to write JSON report into"`
+	Format              string `name:"format" default:"" desc:"output format, default or json"`
+}
+
+// VerifySegmentsCommand checks every file referenced by segment meta for existence, size, binlog header,
+// row count and timestamp range, and reports all discrepancies found.
+// Insert log payload rows are counted against meta entries number only when --read-rows provided.
+func (s *InstanceState) VerifySegmentsCommand(ctx context.Context, p *VerifySegmentsParam) (*framework.PresetResultSet, error) {
+	if p.Workers <= 0 {
+		return nil, errors.New("workers shall be positive")
+	}
+	segments, err := common.ListSegments(ctx, s.client, s.basePath, func(segment *models.Segment) bool {
+		return (p.CollectionID == 0 || segment.CollectionID == p.CollectionID) &&
+			(p.SegmentID == 0 || segment.ID == p.SegmentID) &&
+			(segment.State == commonpb.SegmentState_Flushed || segment.State == commonpb.SegmentState_Sealed)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list segments")
+	}
+	segmentIndexes, err := common.ListSegmentIndex(ctx, s.client, s.basePath, func(segIdx *models.SegmentIndex) bool {
+		info := segIdx.GetProto()
+		return (p.CollectionID == 0 || info.GetCollectionID() == p.CollectionID) &&
+			!info.GetDeleted() && info.GetState() == commonpb.IndexState_Finished
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list segment indexes")
+	}
+
+	minioClient, bucketName, rootPath, err := s.GetMinioClientFromCfg(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &segmentVerifier{
+		client:     minioClient,
+		bucketName: bucketName,
+		rootPath:   rootPath,
+		readRows:   p.ReadRows,
+		report:     &SegmentVerifyReport{Segments: len(segments)},
+	}
+	indexes := make(map[int64][]*models.SegmentIndex)
+	for _, segIdx := range segmentIndexes {
+		indexes[segIdx.GetProto().GetSegmentID()] = append(indexes[segIdx.GetProto().GetSegmentID()], segIdx)
+	}
+
+	tasks := make(chan func(), p.Workers)
+	var wg sync.WaitGroup
+	for i := 0; i < int(p.Workers); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range tasks {
+				task()
+			}
+		}()
+	}
+	for _, segment := range segments {
+		v.checkRows(segment)
+		for _, fn := range v.fileChecks(ctx, segment, indexes[segment.ID]) {
+			tasks <- fn
+		}
+	}
+	close(tasks)
+	wg.Wait()
+
+	sort.SliceStable(v.report.Discrepancies, func(i, j int) bool {
+		return v.report.Discrepancies[i].SegmentID < v.report.Discrepancies[j].SegmentID
+	})
+	if p.Output != "" {
+		bs, err := json.MarshalIndent(v.report, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(p.Output, bs, 0o644); err != nil {
+			return nil, err
+		}
+		fmt.Printf("report written to %s\n", p.Output)
+	}
+	return framework.NewPresetResultSet(v.report, framework.NameFormat(p.Format)), nil
+}
+
+// SegmentDiscrepancy is one file or meta mismatch found in segment verification.
+type SegmentDiscrepancy struct {
+	CollectionID int64  `json:"collection_id"`
+	SegmentID    int64  `json:"segment_id"`
+	FieldID      int64  `json:"field_id"`
+	Kind         string `json:"kind"`
+	Path         string `json:"path,omitempty"`
+	Check        string `json:"check"`
+	Expected     string `json:"expected,omitempty"`
+	Actual       string `json:"actual,omitempty"`
+}
+
+// SegmentVerifyReport is the result of verify segments.
+type SegmentVerifyReport struct {
+	mut           sync.Mutex
+	Segments      int                   `json:"segments"`
+	Files         int64                 `json:"files"`
+	Discrepancies []*SegmentDiscrepancy `json:"discrepancies"`
+}
+
+func (rs *SegmentVerifyReport) Entities() any {
+	return rs.Discrepancies
+}
+
+func (rs *SegmentVerifyReport) PrintAs(format framework.Format) string {
+	switch format {
+	case framework.FormatJSON:
+		bs, err := json.MarshalIndent(rs, "", "  ")
+		if err != nil {
+			return err.Error()
+		}
+		return string(bs)
+	case framework.FormatDefault, framework.FormatPlain:
+		sb := &strings.Builder{}
+		for _, d := range rs.Discrepancies {
+			fmt.Fprintf(sb, "Segment %d %-8s %-9s %s", d.SegmentID, d.Kind, d.Check, d.Path)
+			if d.Expected != "" || d.Actual != "" {
+				fmt.Fprintf(sb, " expected: %s, actual: %s", d.Expected, d.Actual)
+			}
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(sb, "--- %d segment(s), %d file(s) verified, %d discrepancy(s) found", rs.Segments, rs.Files, len(rs.Discrepancies))
+		return sb.String()
+	default:
+	}
+	return ""
+}
+
+func (rs *SegmentVerifyReport) add(d *SegmentDiscrepancy) {
+	rs.mut.Lock()
+	defer rs.mut.Unlock()
+	rs.Discrepancies = append(rs.Discrepancies, d)
+}
+
+func (rs *SegmentVerifyReport) addFile() {
+	rs.mut.Lock()
+	defer rs.mut.Unlock()
+	rs.Files++
+}
+
+type segmentVerifier struct {
+	client     *minio.Client
+	bucketName string
+	rootPath   string
+	readRows   bool
+	report     *SegmentVerifyReport
+}
+
+// checkRows verifies entries number of each insert log field sums up to segment row number.
+// L0 segments have no insert log, and sealed segments may have rows not flushed yet.
+func (v *segmentVerifier) checkRows(segment *models.Segment) {
+	if segment.GetLevel() == datapb.SegmentLevel_L0 || segment.GetState() == commonpb.SegmentState_Sealed {
+		return
+	}
+	for _, fieldBinlog := range segment.GetBinlogs() {
+		var rows int64
+		for _, binlog := range fieldBinlog.Binlogs {
+			rows += binlog.EntriesNum
+		}
+		if rows != segment.GetNumOfRows() {
+			v.report.add(&SegmentDiscrepancy{
+				CollectionID: segment.CollectionID,
+				SegmentID:    segment.ID,
+				FieldID:      fieldBinlog.FieldID,
+				Kind:         "binlog",
+				Check:        verifyRows,
+				Expected:     fmt.Sprint(segment.GetNumOfRows()),
+				Actual:       fmt.Sprint(rows),
+			})
+		}
+	}
+}
+
+// fileChecks returns check functions of all files referenced by segment & segment index meta.
+func (v *segmentVerifier) fileChecks(ctx context.Context, segment *models.Segment, indexes []*models.SegmentIndex) []func() {
+	var checks []func()
+	addLogs := func(kind string, withHeader bool, fieldBinlogs []*models.FieldBinlog) {
+		for _, fieldBinlog := range fieldBinlogs {
+			for _, binlog := range fieldBinlog.Binlogs {
+				fieldID, binlog := fieldBinlog.FieldID, binlog
+				checks = append(checks, func() {
+					v.checkLog(ctx, segment, kind, fieldID, binlog, withHeader)
+				})
+			}
+		}
+	}
+	addLogs("binlog", true, segment.GetBinlogs())
+	addLogs("statslog", false, segment.GetStatslogs())
+	addLogs("deltalog", true, segment.GetDeltalogs())
+	addLogs("bm25", false, segment.GetBm25Statslogs())
+
+	for _, segIdx := range indexes {
+		info := segIdx.GetProto()
+		checks = append(checks, func() {
+			v.checkIndex(ctx, segment, info.GetBuildID(), info.GetIndexVersion(), info.GetIndexFileKeys(), info.GetSerializeSize())
+		})
+	}
+	return checks
+}
+
+func (v *segmentVerifier) checkLog(ctx context.Context, segment *models.Segment, kind string, fieldID int64, binlog *models.Binlog, withHeader bool) {
+	v.report.addFile()
+	key := resolveLogPath(v.rootPath, binlog.LogPath)
+	discrepancy := func(check, expected, actual string) {
+		v.report.add(&SegmentDiscrepancy{
+			CollectionID: segment.CollectionID,
+			SegmentID:    segment.ID,
+			FieldID:      fieldID,
+			Kind:         kind,
+			Path:         key,
+			Check:        check,
+			Expected:     expected,
+			Actual:       actual,
+		})
+	}
+
+	info, err := v.client.StatObject(ctx, v.bucketName, key, minio.StatObjectOptions{})
+	if err != nil {
+		discrepancy(verifyMissing, "", minio.ToErrorResponse(err).Code)
+		return
+	}
+	if binlog.LogSize > 0 && info.Size != binlog.LogSize {
+		discrepancy(verifySize, fmt.Sprint(binlog.LogSize), fmt.Sprint(info.Size))
+	}
+
+	// segment time range, start position is not reliable for compacted segment
+	var lower, upper uint64
+	if !segment.GetCreatedByCompaction() {
+		lower = segment.GetStartPosition().GetTimestamp()
+	}
+	upper = segment.GetDmlPosition().GetTimestamp()
+	outOfRange := func(from, to uint64) bool {
+		return (lower > 0 && from > 0 && from < lower) || (upper > 0 && to > upper)
+	}
+	if outOfRange(binlog.TimestampFrom, binlog.TimestampTo) {
+		discrepancy(verifyTimestamp, fmt.Sprintf("[%d, %d]", lower, upper), fmt.Sprintf("[%d, %d]", binlog.TimestampFrom, binlog.TimestampTo))
+	}
+	if !withHeader {
+		return
+	}
+
+	obj, err := v.client.GetObject(ctx, v.bucketName, key, minio.GetObjectOptions{})
+	if err != nil {
+		discrepancy(verifyHeader, "", err.Error())
+		return
+	}
+	defer obj.Close()
+	reader, desc, err := storage.NewBinlogReader(obj)
+	if err != nil {
+		discrepancy(verifyHeader, "magic number & descriptor event", err.Error())
+		return
+	}
+	if desc.CollectionID != segment.CollectionID || desc.SegmentID != segment.ID {
+		discrepancy(verifyHeader, fmt.Sprintf("collection %d segment %d", segment.CollectionID, segment.ID),
+			fmt.Sprintf("collection %d segment %d", desc.CollectionID, desc.SegmentID))
+	}
+	if outOfRange(desc.StartTimestamp, desc.EndTimestamp) ||
+		(binlog.TimestampFrom > 0 && desc.StartTimestamp < binlog.TimestampFrom) ||
+		(binlog.TimestampTo > 0 && desc.EndTimestamp > binlog.TimestampTo) {
+		discrepancy(verifyTimestamp, fmt.Sprintf("[%d, %d]", binlog.TimestampFrom, binlog.TimestampTo), fmt.Sprintf("[%d, %d]", desc.StartTimestamp, desc.EndTimestamp))
+	}
+	if !v.readRows || kind != "binlog" {
+		return
+	}
+
+	var rows int64
+	for {
+		eventRows, err := reader.NextEventRows()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			discrepancy(verifyRows, fmt.Sprint(binlog.EntriesNum), err.Error())
+			return
+		}
+		rows += eventRows
+	}
+	if rows != binlog.EntriesNum {
+		discrepancy(verifyRows, fmt.Sprint(binlog.EntriesNum), fmt.Sprint(rows))
+	}
+}
+
+// checkIndex verifies index files of one build exist and total size matches serialized size.
+func (v *segmentVerifier) checkIndex(ctx context.Context, segment *models.Segment, buildID, version int64, keys []string, serializeSize uint64) {
+	dir := path.Join(v.rootPath, "index_files", fmt.Sprint(buildID), fmt.Sprint(version), fmt.Sprint(segment.PartitionID), fmt.Sprint(segment.ID))
+	var total uint64
+	for _, fileKey := range keys {
+		v.report.addFile()
+		key := path.Join(dir, fileKey)
+		info, err := v.client.StatObject(ctx, v.bucketName, key, minio.StatObjectOptions{})
+		if err != nil {
+			v.report.add(&SegmentDiscrepancy{
+				CollectionID: segment.CollectionID,
+				SegmentID:    segment.ID,
+				Kind:         "index",
+				Path:         key,
+				Check:        verifyMissing,
+				Actual:       minio.ToErrorResponse(err).Code,
+			})
+			continue
+		}
+		total += uint64(info.Size)
+	}
+	if serializeSize > 0 && total != serializeSize {
+		v.report.add(&SegmentDiscrepancy{
+			CollectionID: segment.CollectionID,
+			SegmentID:    segment.ID,
+			Kind:         "index",
+			Path:         dir,
+			Check:        verifySize,
+			Expected:     fmt.Sprint(serializeSize),
+			Actual:       fmt.Sprint(total),
+		})
+	}
+}
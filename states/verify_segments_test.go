@@ -0,0 +1,71 @@
+package states
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/framework"
+	"github.com/milvus-io/birdwatcher/states/fakecluster"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+)
+
+func TestVerifySegments(t *testing.T) {
+	ctx := context.Background()
+	c := fakecluster.New(t)
+	s3 := c.StartS3()
+	c.StartServer(fakecluster.RoleRootCoord, 1)
+
+	healthy := fakecluster.NewSegment(1001, 100, 101).WithRows(10).WithBinlog(100, 1).WithBm25Statslog(102, 1)
+	missing := fakecluster.NewSegment(1002, 100, 101).WithRows(10).WithBinlog(100, 2)
+	corrupted := fakecluster.NewSegment(1003, 100, 101).WithRows(10).WithBinlog(100, 3)
+	// binlog entries do not sum up to segment rows
+	rows := fakecluster.NewSegment(1004, 100, 101).WithRows(10).WithBinlog(100, 4).WithRows(20)
+	// bm25 stats log missing
+	bm25 := fakecluster.NewSegment(1005, 100, 101).WithRows(10).WithBinlog(100, 5).WithBm25Statslog(102, 5)
+	// sealed segment may have rows not flushed yet, deltalog missing
+	sealed := fakecluster.NewSegment(1006, 100, 101).WithRows(20).WithBinlog(100, 6).WithDeltalog(1, 7).WithState(commonpb.SegmentState_Sealed)
+	l0 := fakecluster.NewSegment(1007, 100, 101).WithLevel(datapb.SegmentLevel_L0).WithDeltalog(2, 8, 9)
+	// payload rows do not match binlog entries
+	payload := fakecluster.NewSegment(1008, 100, 101).WithRows(5).WithBinlog(100, 10)
+	c.AddCollection(fakecluster.NewCollection(100, "coll").WithPrimaryKey(100, "pk").WithPartition(101, "_default")).
+		AddSegment(healthy).AddSegment(missing).AddSegment(corrupted).AddSegment(rows).AddSegment(bm25).AddSegment(sealed).AddSegment(l0).
+		AddSegment(fakecluster.NewSegment(1008, 100, 101).WithRows(10).WithBinlog(100, 10)).
+		AddSegmentIndex(fakecluster.NewSegmentIndex(healthy, 10, 11).WithFiles("HNSW"))
+	for _, segment := range []*fakecluster.SegmentBuilder{healthy, rows, bm25, sealed, payload} {
+		require.NoError(t, s3.PutInsertBinlogs(segment))
+	}
+	require.NoError(t, s3.PutDeltalog(l0, 8, 1, 1, 2))
+	require.NoError(t, s3.PutDeltalog(l0, 9, 1, 3, 4))
+	s3.PutObject(fakecluster.S3Bucket, "files/insert_log/100/101/1003/100/3", []byte("not a binlog"))
+	s3.PutObject(fakecluster.S3Bucket, "files/bm25_stats/100/101/1001/102/1", []byte("bm25"))
+
+	s := &InstanceState{client: c.KV(), basePath: c.BasePath()}
+	collect := func(rs *framework.PresetResultSet) [][2]any {
+		return lo.Map(rs.ResultSet.(*SegmentVerifyReport).Discrepancies, func(d *SegmentDiscrepancy, _ int) [2]any { return [2]any{d.SegmentID, d.Kind + "/" + d.Check} })
+	}
+	expected := [][2]any{
+		{int64(1001), "index/" + verifyMissing},
+		{int64(1002), "binlog/" + verifyMissing},
+		{int64(1003), "binlog/" + verifyHeader},
+		{int64(1004), "binlog/" + verifyRows},
+		{int64(1005), "bm25/" + verifyMissing},
+		{int64(1006), "deltalog/" + verifyMissing},
+	}
+
+	rs, err := s.VerifySegmentsCommand(ctx, &VerifySegmentsParam{CollectionID: 100, Workers: 2})
+	require.NoError(t, err)
+	report := rs.ResultSet.(*SegmentVerifyReport)
+	assert.Equal(t, 8, report.Segments)
+	assert.EqualValues(t, 13, report.Files)
+	assert.ElementsMatch(t, expected, collect(rs))
+	assert.Contains(t, rs.PrintAs(framework.FormatJSON), `"check": "missing"`)
+
+	rs, err = s.VerifySegmentsCommand(ctx, &VerifySegmentsParam{CollectionID: 100, Workers: 2, ReadRows: true})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, append(expected, [2]any{int64(1008), "binlog/" + verifyRows}), collect(rs))
+}
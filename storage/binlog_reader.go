@@ -178,6 +178,26 @@ func readData[T any, Reader interface {
 	file.ColumnChunkReader
 	ReadBatch(int64, []T, []int16, []int16) (int64, int, error)
 }](f io.Reader, colIdx int) ([]T, error) {
+	pqReader, err := readPayload(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return readPayloadAll[T, Reader](pqReader, colIdx)
+}
+
+// NextEventRows returns row number of next insert event payload without decoding column data.
+// io.EOF is returned when no event left.
+func (reader *BinlogReader) NextEventRows() (int64, error) {
+	pqReader, err := readPayload(reader.reader)
+	if err != nil {
+		return 0, err
+	}
+	return pqReader.NumRows(), nil
+}
+
+// readPayload reads next insert event and returns parquet reader of its payload.
+func readPayload(f io.Reader) (*file.Reader, error) {
 	eventReader := newEventReader()
 	header, err := eventReader.readHeader(f)
 	if err != nil {
@@ -191,12 +211,9 @@ func readData[T any, Reader interface {
 	next := int(header.EventLength - header.GetMemoryUsageInBytes() - insertEventData.GetEventDataFixPartSize())
 
 	data := make([]byte, next)
-	io.ReadFull(f, data)
-
-	pqReader, err := file.NewParquetReader(bytes.NewReader(data))
-	if err != nil {
+	if _, err := io.ReadFull(f, data); err != nil {
 		return nil, err
 	}
 
-	return readPayloadAll[T, Reader](pqReader, colIdx)
+	return file.NewParquetReader(bytes.NewReader(data))
 }
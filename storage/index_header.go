@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"encoding/binary"
+	"strings"
+)
+
+// IndexHeader is the index engine information decoded from index binary header.
+// Dim & Vectors are -1 if not recorded in header.
+type IndexHeader struct {
+	Format  string
+	Dim     int64
+	Vectors int64
+}
+
+// faissFourCCs are the index type fourcc faiss writes at the beginning of serialized index.
+var faissFourCCs = map[string]string{
+	"IxF2": "faiss IndexFlatL2",
+	"IxFI": "faiss IndexFlatIP",
+	"IxFl": "faiss IndexFlat",
+	"IwFl": "faiss IndexIVFFlat",
+	"IwPQ": "faiss IndexIVFPQ",
+	"IwSq": "faiss IndexIVFScalarQuantizer",
+	"IHNf": "faiss IndexHNSWFlat",
+	"IHNs": "faiss IndexHNSWSQ",
+	"IHNp": "faiss IndexHNSWPQ",
+}
+
+// faissBinaryFourCCs are the fourcc of faiss binary indexes, which have code size before ntotal.
+var faissBinaryFourCCs = map[string]string{
+	"IBxF": "faiss IndexBinaryFlat",
+	"IBwF": "faiss IndexBinaryIVF",
+	"IBHf": "faiss IndexBinaryHNSW",
+}
+
+// ParseIndexHeader detects index engine format with binary name & header bytes.
+func ParseIndexHeader(name string, data []byte) IndexHeader {
+	header := IndexHeader{Format: "unknown", Dim: -1, Vectors: -1}
+	if len(data) >= 4 {
+		fourCC := string(data[:4])
+		// faiss header: fourcc, d int32, ntotal int64, ...
+		if format, ok := faissFourCCs[fourCC]; ok && len(data) >= 16 {
+			header.Format = format
+			header.Dim = int64(int32(binary.LittleEndian.Uint32(data[4:])))
+			header.Vectors = int64(binary.LittleEndian.Uint64(data[8:]))
+			return header
+		}
+		// faiss binary header: fourcc, d int32, code_size int32, ntotal int64, ...
+		if format, ok := faissBinaryFourCCs[fourCC]; ok && len(data) >= 20 {
+			header.Format = format
+			header.Dim = int64(int32(binary.LittleEndian.Uint32(data[4:])))
+			header.Vectors = int64(binary.LittleEndian.Uint64(data[12:]))
+			return header
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(name, "_disk.index"):
+		// diskann disk index starts with metadata bin: npts int32, ndims int32, followed by uint64 npts, dim, ...
+		header.Format = "DiskANN disk index"
+		if len(data) >= 24 {
+			header.Vectors = int64(binary.LittleEndian.Uint64(data[8:]))
+			header.Dim = int64(binary.LittleEndian.Uint64(data[16:]))
+		}
+	case strings.Contains(name, "_pq_"):
+		header.Format = "DiskANN pq data"
+	case name == "index_length":
+		// STL_SORT stores element number as size_t
+		header.Format = "scalar sort index length"
+		if len(data) >= 8 {
+			header.Vectors = int64(binary.LittleEndian.Uint64(data))
+		}
+	case name == "index_data":
+		header.Format = "scalar sort index data"
+	case strings.HasPrefix(name, "marisa_trie"):
+		header.Format = "scalar marisa trie"
+	case strings.HasPrefix(name, "HNSW"):
+		header.Format = "Knowhere HNSW"
+	}
+	return header
+}
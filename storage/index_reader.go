@@ -1,19 +1,24 @@
 package storage
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"os"
 
 	"github.com/samber/lo"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 )
 
+const (
+	// IndexFileKeyExtra is the descriptor extra recording index file key, e.g. `HNSW`, `SLICE_META`.
+	IndexFileKeyExtra = "key"
+)
+
 type IndexReader struct{}
 
-func NewIndexReader(f *os.File) (*IndexReader, DescriptorEvent, error) {
+func NewIndexReader(f ReadSeeker) (*IndexReader, DescriptorEvent, error) {
 	reader := &IndexReader{}
 	var de DescriptorEvent
 	var err error
@@ -30,7 +35,7 @@ func NewIndexReader(f *os.File) (*IndexReader, DescriptorEvent, error) {
 	return reader, de, err
 }
 
-func (reader *IndexReader) NextEventReader(f *os.File, dataType schemapb.DataType) ([][]byte, error) {
+func (reader *IndexReader) NextEventReader(f io.Reader, dataType schemapb.DataType) ([][]byte, error) {
 	eventReader := newEventReader()
 	header, err := eventReader.readHeader(f)
 	if err != nil {
@@ -43,7 +48,9 @@ func (reader *IndexReader) NextEventReader(f *os.File, dataType schemapb.DataTyp
 
 	next := header.EventLength - header.GetMemoryUsageInBytes() - ifed.GetEventDataFixPartSize()
 	data := make([]byte, next)
-	io.ReadFull(f, data)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, err
+	}
 
 	pr, err := NewParquetPayloadReader(dataType, data)
 	if err != nil {
@@ -51,7 +58,7 @@ func (reader *IndexReader) NextEventReader(f *os.File, dataType schemapb.DataTyp
 		return nil, err
 	}
 	switch dataType {
-	case schemapb.DataType_String:
+	case schemapb.DataType_String, schemapb.DataType_VarChar:
 		result, err := pr.GetStringFromPayload(0)
 		if err != nil {
 			fmt.Println(err.Error())
@@ -70,3 +77,33 @@ func (reader *IndexReader) NextEventReader(f *os.File, dataType schemapb.DataTyp
 	}
 	return nil, errors.New("unexpected data type")
 }
+
+// IndexFile is the decoded content of one binlog-wrapped index file.
+type IndexFile struct {
+	DescriptorEvent
+	Key    string
+	Extras map[string]any
+	Data   []byte
+}
+
+// ReadIndexFile decodes index file, data blocks of payload are concatenated.
+func ReadIndexFile(f ReadSeeker) (*IndexFile, error) {
+	r, de, err := NewIndexReader(f)
+	if err != nil {
+		return nil, err
+	}
+	file := &IndexFile{DescriptorEvent: de, Extras: make(map[string]any)}
+	if err := json.Unmarshal(de.ExtraBytes, &file.Extras); err != nil {
+		return nil, fmt.Errorf("failed to parse descriptor extras: %w", err)
+	}
+	file.Key, _ = file.Extras[IndexFileKeyExtra].(string)
+
+	blocks, err := r.NextEventReader(f, de.PayloadDataType)
+	if err != nil {
+		return nil, err
+	}
+	for _, block := range blocks {
+		file.Data = append(file.Data, block...)
+	}
+	return file, nil
+}
@@ -1,25 +1,145 @@
 package storage
 
 import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
 	"github.com/apache/arrow/go/v8/parquet/file"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+	"github.com/cockroachdb/errors"
 
-	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/birdwatcher/models"
 )
 
+// ParquetReader reads rows of parquet file, e.g. the one written by ParquetWriter.
+// Values are converted back to milvus field types if milvus fields recorded in metadata.
 type ParquetReader struct {
-	reader *file.Reader
+	reader  *file.Reader
+	schema  *arrow.Schema
+	fields  []ParquetField
+	records pqarrow.RecordReader
+
+	record arrow.Record
+	offset int
 }
 
-func NewParquetReader(colType schemapb.DataType, r ReadSeeker) (*ParquetReader, error) {
+func NewParquetReader(r ReadSeeker) (*ParquetReader, error) {
 	reader, err := file.NewParquetReader(r)
 	if err != nil {
 		return nil, err
 	}
+	fr, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{BatchSize: 1024}, memory.DefaultAllocator)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+	schema, err := fr.Schema()
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+	records, err := fr.GetRecordReader(context.Background(), nil, nil)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+
+	var fields []ParquetField
+	if meta := reader.MetaData().KeyValueMetadata().FindValue(ParquetMilvusFieldsKey); meta != nil {
+		if err := json.Unmarshal([]byte(*meta), &fields); err != nil {
+			records.Release()
+			reader.Close()
+			return nil, errors.Wrap(err, "failed to parse milvus fields metadata")
+		}
+	}
+	if len(fields) != len(schema.Fields()) {
+		fields = make([]ParquetField, 0, len(schema.Fields()))
+		for _, field := range schema.Fields() {
+			fields = append(fields, ParquetField{Name: field.Name, DataType: models.DataTypeNone})
+		}
+	}
+
 	return &ParquetReader{
-		reader: reader,
+		reader:  reader,
+		schema:  schema,
+		fields:  fields,
+		records: records,
 	}, nil
 }
 
-func (r *ParquetReader) Next() {
-	// r.reader.RowGroup(0).Column(0)
+// Fields returns columns of file.
+func (r *ParquetReader) Fields() []ParquetField {
+	return r.fields
+}
+
+// NumRows returns total row number of file.
+func (r *ParquetReader) NumRows() int64 {
+	return r.reader.NumRows()
+}
+
+// Next returns next row in column name to value map, io.EOF is returned after all rows read.
+func (r *ParquetReader) Next() (map[string]any, error) {
+	for r.record == nil || r.offset >= int(r.record.NumRows()) {
+		if !r.records.Next() {
+			return nil, io.EOF
+		}
+		r.record = r.records.Record()
+		r.offset = 0
+	}
+
+	row := make(map[string]any, len(r.fields))
+	for i, field := range r.fields {
+		v, err := arrowValue(r.record.Column(i), r.offset, field)
+		if err != nil {
+			return nil, err
+		}
+		row[field.Name] = v
+	}
+	r.offset++
+	return row, nil
+}
+
+func (r *ParquetReader) Close() {
+	r.records.Release()
+	r.reader.Close()
+}
+
+func arrowValue(column arrow.Array, idx int, field ParquetField) (any, error) {
+	if column.IsNull(idx) {
+		return nil, nil
+	}
+	switch arr := column.(type) {
+	case *array.Boolean:
+		return arr.Value(idx), nil
+	case *array.Int8:
+		return arr.Value(idx), nil
+	case *array.Int16:
+		return arr.Value(idx), nil
+	case *array.Int32:
+		return arr.Value(idx), nil
+	case *array.Int64:
+		return arr.Value(idx), nil
+	case *array.Float32:
+		return arr.Value(idx), nil
+	case *array.Float64:
+		return arr.Value(idx), nil
+	case *array.String:
+		return arr.Value(idx), nil
+	case *array.Binary:
+		return append([]byte{}, arr.Value(idx)...), nil
+	case *array.FixedSizeBinary:
+		bs := arr.Value(idx)
+		if field.DataType == models.DataTypeFloatVector {
+			vector := make([]float32, len(bs)/4)
+			copy(arrow.Float32Traits.CastToBytes(vector), bs)
+			return vector, nil
+		}
+		return append([]byte{}, bs...), nil
+	default:
+		return nil, errors.Newf("column %s arrow type %s not supported", field.Name, column.DataType())
+	}
 }
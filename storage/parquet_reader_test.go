@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/birdwatcher/models"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/etcdpb"
+)
+
+func TestParquetRoundTrip(t *testing.T) {
+	collection := models.NewCollection(&etcdpb.CollectionInfo{
+		ID: 100,
+		Schema: &schemapb.CollectionSchema{Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{{Key: "dim", Value: "2"}}},
+			{FieldID: 102, Name: "name", DataType: schemapb.DataType_VarChar},
+			{FieldID: 103, Name: "meta", DataType: schemapb.DataType_JSON},
+		}},
+	}, "")
+
+	w := NewParquetWriter(collection, WithParquetFields("pk", "vec", "name"), WithRowGroupSize(2))
+	assert.False(t, w.HasField("meta"))
+	buf := &bytes.Buffer{}
+	require.NoError(t, w.Open(buf))
+	for i := 0; i < 5; i++ {
+		require.NoError(t, w.AppendInt64("pk", int64(i)))
+		require.NoError(t, w.AppendFloatVector("vec", []float32{float32(i), 0.5}))
+		require.NoError(t, w.AppendString("name", string(rune('a'+i))))
+		require.NoError(t, w.EndRow())
+	}
+	require.NoError(t, w.Close())
+
+	r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	defer r.Close()
+	assert.EqualValues(t, 5, r.NumRows())
+	assert.Equal(t, []ParquetField{
+		{Name: "pk", FieldID: 100, DataType: models.DataTypeInt64},
+		{Name: "vec", FieldID: 101, DataType: models.DataTypeFloatVector, Dim: 2},
+		{Name: "name", FieldID: 102, DataType: models.DataTypeVarChar},
+	}, r.Fields())
+
+	var rows []map[string]any
+	for {
+		row, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+	require.Len(t, rows, 5)
+	assert.Equal(t, map[string]any{"pk": int64(3), "vec": []float32{3, 0.5}, "name": "d"}, rows[3])
+}
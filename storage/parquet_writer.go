@@ -2,8 +2,10 @@ package storage
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"io"
 	"math"
+	"strconv"
 
 	"github.com/apache/arrow/go/v8/arrow"
 	"github.com/apache/arrow/go/v8/arrow/array"
@@ -52,19 +54,78 @@ func ToArrowDataType(dataType models.DataType, dim int) arrow.DataType {
 	return nil
 }
 
+// ParquetMilvusFieldsKey is the schema metadata key recording milvus fields of parquet columns.
+const ParquetMilvusFieldsKey = "milvus.fields"
+
+// ParquetField is the milvus field information of parquet column.
+type ParquetField struct {
+	Name     string          `json:"name"`
+	FieldID  int64           `json:"field_id"`
+	DataType models.DataType `json:"data_type"`
+	Dim      int             `json:"dim,omitempty"`
+}
+
+// ParquetWriterOption is the option to create ParquetWriter.
+type ParquetWriterOption func(*parquetWriterOption)
+
+type parquetWriterOption struct {
+	fields       []string
+	rowGroupSize int64
+}
+
+// WithParquetFields selects fields to write, all collection fields are written if not provided.
+func WithParquetFields(names ...string) ParquetWriterOption {
+	return func(opt *parquetWriterOption) {
+		opt.fields = names
+	}
+}
+
+// WithRowGroupSize sets max rows of one parquet row group.
+func WithRowGroupSize(rows int64) ParquetWriterOption {
+	return func(opt *parquetWriterOption) {
+		opt.rowGroupSize = rows
+	}
+}
+
 type ParquetWriter struct {
 	schema   *arrow.Schema
 	builders map[string]array.Builder
 	fields   map[string]arrow.Field
+
+	rowGroupSize int64
+	rows         int64
+	fileWriter   *pqarrow.FileWriter
 }
 
-func NewParquetWriter(collection *models.Collection) *ParquetWriter {
-	fields := lo.Map(collection.GetProto().Schema.Fields, func(field *schemapb.FieldSchema, _ int) arrow.Field {
-		fs := models.NewFieldSchemaFromBase(field)
-		dim, _ := fs.GetDim()
+func NewParquetWriter(collection *models.Collection, opts ...ParquetWriterOption) *ParquetWriter {
+	opt := &parquetWriterOption{rowGroupSize: 1024 * 1024 * 1024}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	schemaFields := collection.GetProto().Schema.Fields
+	if len(opt.fields) > 0 {
+		byName := lo.SliceToMap(schemaFields, func(field *schemapb.FieldSchema) (string, *schemapb.FieldSchema) {
+			return field.GetName(), field
+		})
+		schemaFields = lo.FilterMap(opt.fields, func(name string, _ int) (*schemapb.FieldSchema, bool) {
+			field, ok := byName[name]
+			return field, ok
+		})
+	}
+
+	pqFields := lo.Map(schemaFields, func(field *schemapb.FieldSchema, _ int) ParquetField {
+		return ParquetField{
+			Name:     field.GetName(),
+			FieldID:  field.GetFieldID(),
+			DataType: models.DataType(field.GetDataType()),
+			Dim:      fieldDim(field),
+		}
+	})
+	fields := lo.Map(pqFields, func(field ParquetField, _ int) arrow.Field {
 		return arrow.Field{
 			Name: field.Name,
-			Type: ToArrowDataType(fs.DataType, int(dim)),
+			Type: ToArrowDataType(field.DataType, field.Dim),
 		}
 	})
 
@@ -75,13 +136,27 @@ func NewParquetWriter(collection *models.Collection) *ParquetWriter {
 		return field.Name, field
 	})
 
-	schema := arrow.NewSchema(fields, nil)
+	bs, _ := json.Marshal(pqFields)
+	meta := arrow.NewMetadata([]string{ParquetMilvusFieldsKey}, []string{string(bs)})
+	schema := arrow.NewSchema(fields, &meta)
 
 	return &ParquetWriter{
-		schema:   schema,
-		builders: builders,
-		fields:   fieldMap,
+		schema:       schema,
+		builders:     builders,
+		fields:       fieldMap,
+		rowGroupSize: opt.rowGroupSize,
+	}
+}
+
+// fieldDim returns dim type param of vector field, 0 for other fields.
+func fieldDim(field *schemapb.FieldSchema) int {
+	for _, kv := range field.GetTypeParams() {
+		if kv.GetKey() == "dim" {
+			dim, _ := strconv.Atoi(kv.GetValue())
+			return dim
+		}
 	}
+	return 0
 }
 
 func (w *ParquetWriter) AppendBool(field string, v bool) error {
@@ -137,22 +212,46 @@ func (w *ParquetWriter) AppendBinaryVector(field string, vec []byte) error {
 	return AppendBuilder[[]byte, *array.FixedSizeBinaryBuilder](field, w.builders, vec)
 }
 
-func (w *ParquetWriter) Flush(writer io.Writer) error {
-	columns := make([]arrow.Column, 0, len(w.builders))
-	arrs := make([]arrow.Array, 0, len(w.builders))
+// HasField returns whether field is written by writer.
+func (w *ParquetWriter) HasField(field string) bool {
+	_, ok := w.builders[field]
+	return ok
+}
+
+func (w *ParquetWriter) writerProperties() *parquet.WriterProperties {
+	return parquet.NewWriterProperties(
+		parquet.WithCompression(compress.Codecs.Zstd),
+		parquet.WithCompressionLevel(3),
+		parquet.WithMaxRowGroupLength(w.rowGroupSize),
+	)
+}
 
-	rows := int64(0)
-	for field, builder := range w.builders {
-		rowCount := builder.Len()
-		if rows != 0 && rows != int64(rowCount) {
-			return errors.New("columns row count differs")
+// newArrays returns arrays of buffered rows in schema order, builders are reset.
+func (w *ParquetWriter) newArrays() ([]arrow.Array, int64, error) {
+	arrs := make([]arrow.Array, 0, len(w.builders))
+	rows := int64(-1)
+	for _, field := range w.schema.Fields() {
+		builder := w.builders[field.Name]
+		if rows >= 0 && rows != int64(builder.Len()) {
+			for _, arr := range arrs {
+				arr.Release()
+			}
+			return nil, 0, errors.New("columns row count differs")
 		}
-		rows = int64(rowCount)
-		arr := builder.NewArray()
-		column := arrow.NewColumnFromArr(w.fields[field], arr)
+		rows = int64(builder.Len())
+		arrs = append(arrs, builder.NewArray())
+	}
+	return arrs, max(rows, 0), nil
+}
 
-		arrs = append(arrs, arr)
-		columns = append(columns, column)
+func (w *ParquetWriter) Flush(writer io.Writer) error {
+	arrs, rows, err := w.newArrays()
+	if err != nil {
+		return err
+	}
+	columns := make([]arrow.Column, 0, len(arrs))
+	for i, arr := range arrs {
+		columns = append(columns, arrow.NewColumnFromArr(w.schema.Field(i), arr))
 	}
 
 	defer func() {
@@ -167,13 +266,57 @@ func (w *ParquetWriter) Flush(writer io.Writer) error {
 	table := array.NewTable(w.schema, columns, rows)
 	defer table.Release()
 
-	props := parquet.NewWriterProperties(
-		parquet.WithCompression(compress.Codecs.Zstd),
-		parquet.WithCompressionLevel(3),
-	)
-	pqarrow.NewArrowWriterProperties()
+	return pqarrow.WriteTable(table, writer, w.rowGroupSize, w.writerProperties(), pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema()))
+}
+
+// Open starts streaming rows into writer, buffered rows are written as one row group
+// once row group size is reached.
+func (w *ParquetWriter) Open(writer io.Writer) error {
+	fw, err := pqarrow.NewFileWriter(w.schema, writer, w.writerProperties(), pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema()))
+	if err != nil {
+		return err
+	}
+	w.fileWriter = fw
+	return nil
+}
 
-	return pqarrow.WriteTable(table, writer, 1024*1024*1024, props, pqarrow.NewArrowWriterProperties())
+// EndRow shall be called after all fields of one row appended in streaming mode.
+func (w *ParquetWriter) EndRow() error {
+	w.rows++
+	if w.rows < w.rowGroupSize {
+		return nil
+	}
+	return w.writeRowGroup()
+}
+
+func (w *ParquetWriter) writeRowGroup() error {
+	if w.fileWriter == nil {
+		return errors.New("parquet writer not opened")
+	}
+	arrs, rows, err := w.newArrays()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, arr := range arrs {
+			arr.Release()
+		}
+	}()
+	w.rows = 0
+	if rows == 0 {
+		return nil
+	}
+	record := array.NewRecord(w.schema, arrs, rows)
+	defer record.Release()
+	return w.fileWriter.Write(record)
+}
+
+// Close writes buffered rows and closes streaming writer.
+func (w *ParquetWriter) Close() error {
+	if err := w.writeRowGroup(); err != nil {
+		return err
+	}
+	return w.fileWriter.Close()
 }
 
 func AppendBuilder[T any, Builder interface {
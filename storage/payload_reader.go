@@ -137,6 +137,7 @@ func readPayloadAll[T any, Reader interface {
 		if total != groupCount || int64(read) != groupCount {
 			return nil, errors.New("row count not matched")
 		}
+		offset += groupCount
 	}
 
 	return result, nil
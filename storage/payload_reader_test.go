@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestReadPayloadAllMultiRowGroups(t *testing.T) {
+	builder := array.NewInt64Builder(memory.DefaultAllocator)
+	expected := make([]int64, 0, 10)
+	for i := int64(0); i < 10; i++ {
+		builder.Append(i * 10)
+		expected = append(expected, i*10)
+	}
+	column := builder.NewArray()
+	defer column.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "val", Type: column.DataType()}}, nil)
+	record := array.NewRecord(schema, []arrow.Array{column}, int64(column.Len()))
+	defer record.Release()
+	table := array.NewTableFromRecords(schema, []arrow.Record{record})
+	defer table.Release()
+
+	// 3 rows per row group, last group is partial
+	buf := &bytes.Buffer{}
+	require.NoError(t, pqarrow.WriteTable(table, buf, 3,
+		parquet.NewWriterProperties(parquet.WithAllocator(memory.DefaultAllocator)),
+		pqarrow.DefaultWriterProps()))
+
+	r, err := NewParquetPayloadReader(schemapb.DataType_Int64, buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, 4, r.reader.NumRowGroups())
+
+	values, err := r.GetInt64sFromPayload(0)
+	require.NoError(t, err)
+	assert.Equal(t, expected, values)
+}
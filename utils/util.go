@@ -1,6 +1,10 @@
 package utils
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 const (
 	logicalBits     = 18
@@ -13,3 +17,28 @@ func ParseTS(ts uint64) (time.Time, uint64) {
 	physicalTime := time.Unix(int64(physical/1000), int64(physical)%1000*time.Millisecond.Nanoseconds())
 	return physicalTime, logical
 }
+
+// MakeTS composes hybrid timestamp with physical time in milliseconds and logical counter.
+func MakeTS(physical time.Time, logical uint64) uint64 {
+	return uint64(physical.UnixMilli())<<logicalBits + logical&logicalBitsMask
+}
+
+// ParseTimeExpr parses time in RFC3339 format or relative to now, e.g. "now", "now-5m" or "now+1d".
+func ParseTimeExpr(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if rest, ok := strings.CutPrefix(s, "now"); ok {
+		if rest == "" {
+			return now, nil
+		}
+		d, err := ParseDuration(strings.TrimPrefix(rest, "+"))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid relative time %s: %w", s, err)
+		}
+		return now.Add(d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %s, shall be RFC3339 or now[+-]duration", s)
+	}
+	return t, nil
+}